@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go-crud-todo-list/models"
+	"os"
+	"sync"
+	"time"
+)
+
+// Audit actions recorded for todo mutations
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionArchive moves a completed todo out of the active data file into the archive
+	ActionArchive = "archive"
+)
+
+// Entry represents a single recorded mutation with before/after snapshots
+type Entry struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Action    string       `json:"action"`
+	TodoID    int          `json:"todo_id"`
+	Before    *models.Todo `json:"before,omitempty"`
+	After     *models.Todo `json:"after,omitempty"`
+}
+
+// Logger defines the interface for recording and querying audit entries
+type Logger interface {
+	Log(entry Entry) error
+	History(todoID int) ([]Entry, error)
+}
+
+// FileLogger implements Logger by appending JSON lines to a file
+type FileLogger struct {
+	filePath string
+	mutex    sync.Mutex
+}
+
+// NewFileLogger creates a new file-backed audit logger writing to the given path
+func NewFileLogger(filePath string) *FileLogger {
+	return &FileLogger{
+		filePath: filePath,
+	}
+}
+
+// Log appends the entry to the audit file as a single JSON line
+func (l *FileLogger) Log(entry Entry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	file, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// maxEntrySize bounds a single audit line when scanning the log
+const maxEntrySize = 1 << 20
+
+// History scans the audit file and returns the entries for the given todo in the order
+// they were recorded, which is chronological since the file is append-only
+func (l *FileLogger) History(todoID int) ([]Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries := make([]Entry, 0)
+
+	file, err := os.Open(l.filePath)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEntrySize)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		if entry.TodoID == todoID {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
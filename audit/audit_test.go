@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLogger_AppendsJSONLines(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewFileLogger(filePath)
+
+	todo := &models.Todo{ID: 1, Title: "Audited"}
+	entries := []Entry{
+		{Timestamp: time.Now(), Action: ActionCreate, TodoID: 1, After: todo},
+		{Timestamp: time.Now(), Action: ActionDelete, TodoID: 1, Before: todo},
+	}
+
+	for _, entry := range entries {
+		if err := logger.Log(entry); err != nil {
+			t.Fatalf("Failed to log entry: %v", err)
+		}
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var actions []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode audit line: %v", err)
+		}
+		actions = append(actions, entry.Action)
+	}
+
+	if len(actions) != 2 || actions[0] != ActionCreate || actions[1] != ActionDelete {
+		t.Errorf("Expected [create delete], got %v", actions)
+	}
+}
+
+func TestFileLogger_History(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger := NewFileLogger(filePath)
+
+	// A log that doesn't exist yet has no history
+	entries, err := logger.History(1)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Expected empty history, got %v (err %v)", entries, err)
+	}
+
+	start := time.Now()
+	titles := []string{"First", "Second", "Third"}
+	logger.Log(Entry{Timestamp: start, Action: ActionCreate, TodoID: 1, After: &models.Todo{ID: 1, Title: titles[0]}})
+	logger.Log(Entry{Timestamp: start.Add(time.Second), Action: ActionCreate, TodoID: 2, After: &models.Todo{ID: 2, Title: "Other"}})
+	for i := 1; i < len(titles); i++ {
+		logger.Log(Entry{
+			Timestamp: start.Add(time.Duration(i+1) * time.Second),
+			Action:    ActionUpdate,
+			TodoID:    1,
+			Before:    &models.Todo{ID: 1, Title: titles[i-1]},
+			After:     &models.Todo{ID: 1, Title: titles[i]},
+		})
+	}
+
+	entries, err = logger.History(1)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if len(entries) != len(titles) {
+		t.Fatalf("Expected %d entries, got %d", len(titles), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.After.Title != titles[i] {
+			t.Errorf("Entry %d: expected title %q, got %q", i, titles[i], entry.After.Title)
+		}
+		if i > 0 && entry.Timestamp.Before(entries[i-1].Timestamp) {
+			t.Errorf("Entry %d is out of chronological order", i)
+		}
+	}
+
+	entries, _ = logger.History(3)
+	if len(entries) != 0 {
+		t.Errorf("Expected no history for an unknown todo, got %d entries", len(entries))
+	}
+}
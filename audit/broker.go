@@ -0,0 +1,97 @@
+package audit
+
+import "sync"
+
+// SubscriberBuffer is how many entries a subscriber may fall behind before further entries
+// are dropped for it
+const SubscriberBuffer = 64
+
+// Broker fans recorded audit entries out to live subscribers such as the audit stream
+type Broker struct {
+	mutex       sync.Mutex
+	subscribers map[chan Entry]struct{}
+	closed      bool
+}
+
+// NewBroker creates a broker with no subscribers
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[chan Entry]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving every entry published from now on, and a function
+// that unsubscribes and closes the channel. The unsubscribe function may be called more
+// than once. After Close, the returned channel is already closed.
+func (b *Broker) Subscribe() (<-chan Entry, func()) {
+	ch := make(chan Entry, SubscriberBuffer)
+
+	b.mutex.Lock()
+	if b.closed {
+		close(ch)
+	} else {
+		b.subscribers[ch] = struct{}{}
+	}
+	b.mutex.Unlock()
+
+	return ch, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		// Close may already have ended the subscription
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close ends every subscription by closing its channel, so streams waiting on the broker
+// finish, for example when the server shuts down. Later subscriptions end immediately.
+func (b *Broker) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish delivers the entry to every subscriber without blocking; a subscriber whose
+// buffer is full misses the entry rather than stalling the mutation that produced it
+func (b *Broker) Publish(entry Entry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// PublishingLogger records entries with the wrapped Logger and publishes each entry that
+// was recorded to a Broker
+type PublishingLogger struct {
+	Logger
+	broker *Broker
+}
+
+// NewPublishingLogger wraps logger so recorded entries are also published to broker
+func NewPublishingLogger(logger Logger, broker *Broker) *PublishingLogger {
+	return &PublishingLogger{
+		Logger: logger,
+		broker: broker,
+	}
+}
+
+// Log records the entry and, once it is recorded, publishes it
+func (l *PublishingLogger) Log(entry Entry) error {
+	if err := l.Logger.Log(entry); err != nil {
+		return err
+	}
+	l.broker.Publish(entry)
+	return nil
+}
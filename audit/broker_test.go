@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBroker_PublishesToSubscribers(t *testing.T) {
+	broker := NewBroker()
+	first, unsubscribeFirst := broker.Subscribe()
+	second, unsubscribeSecond := broker.Subscribe()
+	defer unsubscribeSecond()
+	
+	broker.Publish(Entry{Action: ActionCreate, TodoID: 1})
+	for _, ch := range []<-chan Entry{first, second} {
+		if entry := <-ch; entry.TodoID != 1 {
+			t.Errorf("Expected entry for todo 1, got %+v", entry)
+		}
+	}
+	
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, open := <-first; open {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+	broker.Publish(Entry{Action: ActionDelete, TodoID: 1})
+	if entry := <-second; entry.Action != ActionDelete {
+		t.Errorf("Expected the remaining subscriber to get the delete, got %+v", entry)
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	
+	broker.Close()
+	if _, open := <-ch; open {
+		t.Error("Expected the channel to be closed by Close")
+	}
+	// Unsubscribing after Close must not close the channel again
+	unsubscribe()
+	
+	late, unsubscribeLate := broker.Subscribe()
+	defer unsubscribeLate()
+	if _, open := <-late; open {
+		t.Error("Expected a subscription after Close to end immediately")
+	}
+	broker.Publish(Entry{Action: ActionCreate, TodoID: 1})
+}
+
+func TestBroker_DropsForSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= SubscriberBuffer+10; i++ {
+			broker.Publish(Entry{Action: ActionUpdate, TodoID: i})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Publish not to block on a full subscriber")
+	}
+	if len(ch) != SubscriberBuffer {
+		t.Errorf("Expected %d buffered entries, got %d", SubscriberBuffer, len(ch))
+	}
+}
+
+// failingLogger rejects every entry
+type failingLogger struct{}
+
+func (failingLogger) Log(Entry) error              { return errors.New("disk full") }
+func (failingLogger) History(int) ([]Entry, error) { return nil, nil }
+
+func TestPublishingLogger_PublishesOnlyRecordedEntries(t *testing.T) {
+	broker := NewBroker()
+	ch, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	
+	if err := NewPublishingLogger(failingLogger{}, broker).Log(Entry{TodoID: 1}); err == nil {
+		t.Fatal("Expected the wrapped logger's error")
+	}
+	if len(ch) != 0 {
+		t.Error("Expected an unrecorded entry not to be published")
+	}
+	
+	logger := NewPublishingLogger(NewFileLogger(filepath.Join(t.TempDir(), "audit.jsonl")), broker)
+	if err := logger.Log(Entry{Action: ActionCreate, TodoID: 2}); err != nil {
+		t.Fatalf("Failed to log entry: %v", err)
+	}
+	if entry := <-ch; entry.TodoID != 2 {
+		t.Errorf("Expected the recorded entry to be published, got %+v", entry)
+	}
+}
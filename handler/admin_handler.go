@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PurgeResponse represents the response body for a purge
+type PurgeResponse struct {
+	Purged int `json:"purged"`
+}
+
+// checkConsistency handles GET /admin/check - reports data integrity problems without modifying data
+func (h *TodoHandler) checkConsistency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	report, err := h.serviceFor(r).CheckConsistency()
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to check consistency")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, report)
+}
+
+// diffSnapshot handles POST /admin/diff - compares a previous data file snapshot with the current todos
+func (h *TodoHandler) diffSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	var snapshot models.TodoStorage
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.serviceFor(r).DiffSnapshot(snapshot.Todos)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to diff snapshot")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// purgeTodos handles POST /admin/purge - permanently deletes todos created (or completed)
+// before a date. It requires confirm=true so a stray request cannot wipe data.
+func (h *TodoHandler) purgeTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "before", "field", "confirm") {
+		return
+	}
+
+	query := r.URL.Query()
+
+	before, err := parseQueryTime("before", query.Get("before"), responseLocation(w), false)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	field := query.Get("field")
+	if field == "" {
+		field = models.PurgeFieldCreated
+	}
+	if query.Get("confirm") != "true" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Purge permanently deletes todos: repeat the request with confirm=true")
+		return
+	}
+
+	purged, err := h.serviceFor(r).PurgeBefore(before, field)
+	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to purge todos")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, PurgeResponse{Purged: purged})
+}
+
+// AuditStreamHeartbeat is how often an idle audit stream sends a heartbeat comment
+const AuditStreamHeartbeat = 30 * time.Second
+
+// auditStream handles GET /admin/audit/stream - sends each audit entry as an "audit"
+// server-sent event as it is recorded, until the client disconnects. Entries recorded
+// before the client connected are not replayed; /todos/{id}/history serves those.
+func (h *TodoHandler) auditStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	if h.config.AuditBroker == nil {
+		h.writeErrorResponse(w, http.StatusNotImplemented, "Audit stream is unavailable: audit streaming is disabled")
+		return
+	}
+
+	entries, unsubscribe := h.config.AuditBroker.Subscribe()
+	defer unsubscribe()
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	stream := newEventStream(w)
+	// Send the headers now so the client knows it is subscribed before the first entry
+	if err := stream.heartbeat(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(AuditStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if err := stream.heartbeat(); err != nil {
+				return
+			}
+		case entry, ok := <-entries:
+			// The broker closed, as it does when the server shuts down
+			if !ok {
+				return
+			}
+			data, err := h.presentResponse(w, entry)
+			if err != nil {
+				log.Printf("Failed to encode audit entry for todo %d: %v", entry.TodoID, err)
+				continue
+			}
+			if err := stream.send("audit", data); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Valid"})
+	mockService.todos = append(mockService.todos, models.Todo{ID: 1, Title: "Duplicate"})
+	
+	req := httptest.NewRequest(http.MethodGet, "/admin/check", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var report models.ConsistencyReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if report.Consistent || len(report.DuplicateIDs) != 1 {
+		t.Errorf("Expected duplicate ID to be reported, got %+v", report)
+	}
+}
+
+func TestCheckConsistency_MethodNotAllowed(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodPost, "/admin/check", nil)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Unchanged"},
+		{ID: 2, Title: "Edited", Completed: true},
+		{ID: 4, Title: "Added"},
+	}
+	
+	snapshot := `{"todos":[{"id":1,"title":"Unchanged"},{"id":2,"title":"Original"},{"id":3,"title":"Removed"}],"next_id":4}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/diff", strings.NewReader(snapshot))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	var result models.DiffResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if len(result.Added) != 1 || result.Added[0].ID != 4 {
+		t.Errorf("Expected todo 4 to be added, got %+v", result.Added)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].ID != 3 {
+		t.Errorf("Expected todo 3 to be removed, got %+v", result.Removed)
+	}
+	if len(result.Modified) != 1 || result.Modified[0].ID != 2 {
+		t.Fatalf("Expected todo 2 to be modified, got %+v", result.Modified)
+	}
+	
+	fields := make(map[string]bool)
+	for _, change := range result.Modified[0].Changes {
+		fields[change.Field] = true
+	}
+	if len(fields) != 2 || !fields["title"] || !fields["completed"] {
+		t.Errorf("Expected title and completed changes, got %+v", result.Modified[0].Changes)
+	}
+}
+
+func TestDiffSnapshot_InvalidJSON(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodPost, "/admin/diff", strings.NewReader("{"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestPurgeTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	old := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Old", CreatedAt: old},
+		{ID: 2, Title: "Recent", CreatedAt: recent},
+		{ID: 3, Title: "Old done", CreatedAt: old, Completed: true, CompletedAt: &old},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantPurged int
+	}{
+		{"missing confirmation", "before=2023-01-01", http.StatusBadRequest, 0},
+		{"missing date", "confirm=true", http.StatusBadRequest, 0},
+		{"invalid date", "before=01/01/2023&confirm=true", http.StatusBadRequest, 0},
+		{"invalid field", "before=2023-01-01&field=updated_at&confirm=true", http.StatusBadRequest, 0},
+		{"completed before", "before=2023-01-01&field=completed_at&confirm=true", http.StatusOK, 1},
+		{"created before", "before=2023-01-01&confirm=true", http.StatusOK, 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/purge?"+tt.query, nil)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			
+			var resp PurgeResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Purged != tt.wantPurged {
+				t.Errorf("Expected %d purged, got %d", tt.wantPurged, resp.Purged)
+			}
+		})
+	}
+	
+	if len(mockService.todos) != 1 || mockService.todos[0].ID != 2 {
+		t.Errorf("Expected only the todo created after the cutoff to remain, got %v", mockService.todos)
+	}
+}
+
+func TestAuditStream(t *testing.T) {
+	broker := audit.NewBroker()
+	logger := audit.NewPublishingLogger(audit.NewFileLogger(filepath.Join(t.TempDir(), "audit.jsonl")), broker)
+	repo := repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))
+	todoService := service.NewTodoServiceWithConfig(repo, service.ServiceConfig{AuditLogger: logger})
+	config := DefaultHandlerConfig()
+	config.AuditBroker = broker
+	server := httptest.NewServer(NewTodoHandlerWithConfig(todoService, config).SetupHandler())
+	defer server.Close()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/admin/audit/stream", nil)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got Content-Type %q", ct)
+	}
+	
+	created, err := http.Post(server.URL+"/todos", "application/json", strings.NewReader(`{"title": "Streamed"}`))
+	if err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	created.Body.Close()
+	
+	// Skip the heartbeat comment sent when the stream opens
+	events := bufio.NewReader(resp.Body)
+	var event, data string
+	for event == "" || data == "" {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "event: "); ok {
+			event = name
+		}
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			data = value
+		}
+	}
+	
+	var entry audit.Entry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if event != "audit" || entry.Action != audit.ActionCreate || entry.TodoID != 1 || entry.After == nil || entry.After.Title != "Streamed" {
+		t.Errorf("Expected an audit event for creating todo 1, got %s %+v", event, entry)
+	}
+}
+
+func TestAuditStream_Disabled(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit/stream", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status %d, got %d", http.StatusNotImplemented, w.Code)
+	}
+}
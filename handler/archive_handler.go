@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"errors"
+	"go-crud-todo-list/service"
+	"net/http"
+)
+
+// archivedTodos handles GET /todos/archive - lists the completed todos moved out of the
+// active data file, in the order they were archived
+func (h *TodoHandler) archivedTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	todos, err := h.serviceFor(r).ListArchived()
+	if err != nil {
+		if errors.Is(err, service.ErrArchiveUnavailable) {
+			h.writeErrorResponse(w, http.StatusNotImplemented, "Archive is unavailable: archiving is disabled")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve archived todos")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todos)
+}
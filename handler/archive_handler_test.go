@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// serveGet sends a GET request for path through the routes
+func serveGet(handler *TodoHandler, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestArchivedTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.archived = []models.Todo{}
+	done := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Done", Completed: true, CompletedAt: &done},
+		{ID: 2, Title: "Open"},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	if _, err := mockService.ArchiveCompleted(); err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	
+	w := serveGet(handler, "/todos/archive")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var archived []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&archived); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != 1 {
+		t.Errorf("Expected the completed todo in the archive, got %v", archived)
+	}
+	
+	w = serveGet(handler, "/todos")
+	var active []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&active); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(active) != 1 || active[0].ID != 2 {
+		t.Errorf("Expected only the open todo in the active list, got %v", active)
+	}
+}
+
+func TestArchivedTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		enabled    bool
+		wantStatus int
+	}{
+		{"disabled", http.MethodGet, false, http.StatusNotImplemented},
+		{"wrong method", http.MethodDelete, true, http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			if tt.enabled {
+				mockService.archived = []models.Todo{}
+			}
+			handler := NewTodoHandler(mockService)
+			
+			req := httptest.NewRequest(tt.method, "/todos/archive", nil)
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+)
+
+// BulkUpdateResponse represents the per-item outcome of a bulk update
+type BulkUpdateResponse struct {
+	Results []models.BulkUpdateResult `json:"results"`
+}
+
+// bulkUpdateTodos handles PUT /todos/bulk - applies many full todo updates in one transaction
+func (h *TodoHandler) bulkUpdateTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	if !h.decodeBodyIDs(w, r) {
+		return
+	}
+
+	var items []models.BulkUpdateItem
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	if !h.checkBatchSize(w, len(items)) {
+		return
+	}
+
+	results, err := h.serviceFor(r).BulkUpdateTodos(items)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to apply bulk update")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, BulkUpdateResponse{Results: results})
+}
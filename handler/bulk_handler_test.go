@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBulkUpdateTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Second"})
+	mockService.todos[1].Version = 3
+	
+	body := `[
+		{"id": 1, "title": "First synced", "completed": true, "version": 1},
+		{"id": 2, "title": "Second synced", "version": 2}
+	]`
+	req := httptest.NewRequest(http.MethodPut, "/todos/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	var resp BulkUpdateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if len(resp.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(resp.Results))
+	}
+	if resp.Results[0].Status != models.BulkStatusUpdated {
+		t.Errorf("Expected first item updated, got %+v", resp.Results[0])
+	}
+	if resp.Results[1].Status != models.BulkStatusConflict || resp.Results[1].Version != 3 {
+		t.Errorf("Expected second item to conflict at version 3, got %+v", resp.Results[1])
+	}
+}
+
+func TestBulkUpdateTodos_MethodNotAllowed(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/bulk", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestBulkUpdateTodos_MaxBatchSize(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	config := DefaultHandlerConfig()
+	config.MaxBatchSize = 2
+	mux := NewTodoHandlerWithConfig(mockService, config).SetupRoutes()
+	
+	tests := []struct {
+		body     string
+		expected int
+	}{
+		{`[{"id": 1, "title": "At limit"}, {"id": 2, "title": "Missing"}]`, http.StatusOK},
+		{`[{"id": 1, "title": "Over limit"}, {"id": 2, "title": "Missing"}, {"id": 3, "title": "Missing"}]`, http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/todos/bulk", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != tt.expected {
+			t.Errorf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+		}
+	}
+	
+	if mockService.todos[0].Title != "At limit" {
+		t.Errorf("Expected the oversized batch to be rejected before any work, got title %q", mockService.todos[0].Title)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+	"go-crud-todo-list/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BurndownResponse represents the response body for a burndown report
+type BurndownResponse struct {
+	Bucket  string                  `json:"bucket"`
+	Buckets []models.BurndownBucket `json:"buckets"`
+}
+
+// parseQueryTime parses a date query value given as YYYY-MM-DD or RFC 3339.
+// Plain dates are interpreted in loc; an end date covers its whole day.
+func parseQueryTime(name, value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s is required", name)
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: expected YYYY-MM-DD or RFC 3339 timestamp", name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// burndown handles GET /todos/burndown - counts todos created and completed per bucket
+func (h *TodoHandler) burndown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "from", "to", "bucket") {
+		return
+	}
+
+	query := r.URL.Query()
+	loc := responseLocation(w)
+
+	from, err := parseQueryTime("from", query.Get("from"), loc, false)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseQueryTime("to", query.Get("to"), loc, true)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		bucket = models.BucketDay
+	}
+
+	buckets, err := h.serviceFor(r).Burndown(from, to, bucket)
+	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to compute burndown")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, BurndownResponse{Bucket: bucket, Buckets: buckets})
+}
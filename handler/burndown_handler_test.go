@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBurndown(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	completedAt := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "A", CreatedAt: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC), Completed: true, CompletedAt: &completedAt},
+		{ID: 2, Title: "B", CreatedAt: time.Date(2024, time.March, 5, 23, 0, 0, 0, time.UTC)},
+	}
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/burndown?from=2024-03-04&to=2024-03-05", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	var response BurndownResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Bucket != models.BucketDay || len(response.Buckets) != 2 {
+		t.Fatalf("Expected two day buckets, got %+v", response)
+	}
+	if b := response.Buckets[0]; b.Created != 1 || b.Completed != 0 {
+		t.Errorf("Expected 1 created on the first day, got %+v", b)
+	}
+	if b := response.Buckets[1]; b.Created != 1 || b.Completed != 1 {
+		t.Errorf("Expected 1 created and 1 completed on the second day, got %+v", b)
+	}
+}
+
+func TestBurndown_Errors(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{"missing from", http.MethodGet, "/todos/burndown?to=2024-03-05", http.StatusBadRequest},
+		{"invalid to", http.MethodGet, "/todos/burndown?from=2024-03-04&to=tomorrow", http.StatusBadRequest},
+		{"invalid bucket", http.MethodGet, "/todos/burndown?from=2024-03-04&to=2024-03-05&bucket=month", http.StatusBadRequest},
+		{"reversed range", http.MethodGet, "/todos/burndown?from=2024-03-05&to=2024-03-04", http.StatusBadRequest},
+		{"wrong method", http.MethodPost, "/todos/burndown", http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if tt.method == http.MethodPost {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
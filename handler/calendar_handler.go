@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"go-crud-todo-list/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsMaxLineOctets is the longest content line iCalendar allows before folding (RFC 5545 3.1)
+const icsMaxLineOctets = 75
+
+// icsEscaper escapes TEXT property values (RFC 5545 3.3.11)
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icsEscape escapes a value for use in a TEXT property
+func icsEscape(value string) string {
+	return icsEscaper.Replace(value)
+}
+
+// writeICSLine writes a content line ending in CRLF, folding it so no physical line exceeds
+// 75 octets. Continuation lines start with a space and never split a UTF-8 sequence.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the continuation line's length
+		limit = icsMaxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// icsTimestamp formats t as a UTC DATE-TIME value
+func icsTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// renderCalendar renders todos with a due date as VTODO components of a VCALENDAR. Due dates
+// are given as dates in loc; uid returns the identifier shown for a todo's ID.
+func renderCalendar(todos []models.Todo, loc *time.Location, uid func(int) string) []byte {
+	var buf bytes.Buffer
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//go-crud-todo-list//Todos//EN")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+
+	for _, todo := range todos {
+		if todo.DueDate == nil {
+			continue
+		}
+		writeICSLine(&buf, "BEGIN:VTODO")
+		writeICSLine(&buf, "UID:todo-"+uid(todo.ID)+"@go-crud-todo-list")
+		writeICSLine(&buf, "DTSTAMP:"+icsTimestamp(todo.UpdatedAt))
+		writeICSLine(&buf, "CREATED:"+icsTimestamp(todo.CreatedAt))
+		writeICSLine(&buf, "LAST-MODIFIED:"+icsTimestamp(todo.UpdatedAt))
+		writeICSLine(&buf, "SUMMARY:"+icsEscape(todo.Title))
+		if todo.Description != "" {
+			writeICSLine(&buf, "DESCRIPTION:"+icsEscape(todo.Description))
+		}
+		writeICSLine(&buf, "DUE;VALUE=DATE:"+todo.DueDate.In(loc).Format("20060102"))
+		if len(todo.Tags) > 0 {
+			categories := make([]string, len(todo.Tags))
+			for i, tag := range todo.Tags {
+				categories[i] = icsEscape(tag)
+			}
+			writeICSLine(&buf, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+		if todo.Completed {
+			writeICSLine(&buf, "STATUS:COMPLETED")
+			if todo.CompletedAt != nil {
+				writeICSLine(&buf, "COMPLETED:"+icsTimestamp(*todo.CompletedAt))
+			}
+		} else {
+			writeICSLine(&buf, "STATUS:NEEDS-ACTION")
+		}
+		writeICSLine(&buf, "END:VTODO")
+	}
+
+	writeICSLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// calendarFeed handles GET /todos/calendar.ics - an iCalendar feed with a VTODO for each
+// todo that has a due date. It accepts the list filters and shows only open todos unless
+// completed is given.
+func (h *TodoHandler) calendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, listFilterParams...) {
+		return
+	}
+
+	filter, err := h.parseListFilter(r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Completed == nil {
+		open := false
+		filter.Completed = &open
+	}
+
+	todos, err := h.serviceFor(r).ListTodos(filter)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve todos")
+		return
+	}
+
+	uid := strconv.Itoa
+	if h.ids != nil {
+		uid = h.ids.encode
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="todos.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(renderCalendar(todos, responseLocation(w), uid))
+}
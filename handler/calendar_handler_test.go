@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseVTODOs unfolds an iCalendar document and returns the properties of each VTODO,
+// keyed by name with parameters, e.g. "DUE;VALUE=DATE"
+func parseVTODOs(t *testing.T, body string) []map[string]string {
+	t.Helper()
+	if !strings.HasSuffix(body, "\r\n") {
+		t.Fatalf("Expected CRLF line endings, got %q", body)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines folded to 75 octets, got %d: %q", len(line), line)
+		}
+	}
+	
+	unfolded := strings.ReplaceAll(body, "\r\n ", "")
+	var todos []map[string]string
+	var current map[string]string
+	for _, line := range strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n") {
+		switch line {
+		case "BEGIN:VTODO":
+			current = make(map[string]string)
+		case "END:VTODO":
+			todos = append(todos, current)
+			current = nil
+		default:
+			if current != nil {
+				name, value, _ := strings.Cut(line, ":")
+				current[name] = value
+			}
+		}
+	}
+	return todos
+}
+
+func TestCalendarFeed(t *testing.T) {
+	mockService := NewMockTodoService()
+	due := time.Date(2024, 7, 4, 15, 0, 0, 0, time.UTC)
+	longDescription := strings.Repeat("Bring snacks; drinks, and a chair. ", 4) + "Überall\nsecond line"
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Picnic, park", Description: longDescription, DueDate: &due, Tags: []string{"fun"}, CreatedAt: due, UpdatedAt: due},
+		{ID: 2, Title: "No due date", CreatedAt: due, UpdatedAt: due},
+		{ID: 3, Title: "Already done", Completed: true, DueDate: &due, CreatedAt: due, UpdatedAt: due},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/calendar.ics", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Expected text/calendar, got %q", ct)
+	}
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+		t.Fatalf("Expected a VCALENDAR, got %q", body)
+	}
+	
+	todos := parseVTODOs(t, body)
+	if len(todos) != 1 {
+		t.Fatalf("Expected only the open todo with a due date, got %d VTODOs", len(todos))
+	}
+	todo := todos[0]
+	expected := map[string]string{
+		"UID":            "todo-1@go-crud-todo-list",
+		"SUMMARY":        `Picnic\, park`,
+		"DESCRIPTION":    strings.Repeat(`Bring snacks\; drinks\, and a chair. `, 4) + `Überall\nsecond line`,
+		"DUE;VALUE=DATE": "20240704",
+		"STATUS":         "NEEDS-ACTION",
+		"CATEGORIES":     "fun",
+	}
+	for name, want := range expected {
+		if got := todo[name]; got != want {
+			t.Errorf("Expected %s %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestCalendarFeed_CompletedAndTimezone(t *testing.T) {
+	mockService := NewMockTodoService()
+	// Late on July 4th in UTC is already July 5th in Tokyo
+	due := time.Date(2024, 7, 4, 20, 0, 0, 0, time.UTC)
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Open", DueDate: &due},
+		{ID: 2, Title: "Done", Completed: true, DueDate: &due, CompletedAt: &due},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/calendar.ics?completed=true", nil)
+	req.Header.Set("X-Timezone", "Asia/Tokyo")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	todos := parseVTODOs(t, w.Body.String())
+	if len(todos) != 1 || todos[0]["SUMMARY"] != "Done" || todos[0]["STATUS"] != "COMPLETED" {
+		t.Fatalf("Expected only the completed todo, got %v", todos)
+	}
+	if todos[0]["DUE;VALUE=DATE"] != "20240705" {
+		t.Errorf("Expected the due date in the requested timezone, got %q", todos[0]["DUE;VALUE=DATE"])
+	}
+}
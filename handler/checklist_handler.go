@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AddChecklistItemRequest represents the request body for adding a checklist item
+type AddChecklistItemRequest struct {
+	Text string `json:"text"`
+}
+
+// UpdateChecklistItemRequest represents the request body for changing a checklist item;
+// omitting done, or sending no body, toggles the item
+type UpdateChecklistItemRequest struct {
+	Done *bool `json:"done"`
+}
+
+// ChecklistResponse represents a todo's checklist and how much of it is done
+type ChecklistResponse struct {
+	TodoID          int                    `json:"todo_id"`
+	Items           []models.ChecklistItem `json:"items"`
+	Done            int                    `json:"done"`
+	Total           int                    `json:"total"`
+	CompletionRatio float64                `json:"completion_ratio"`
+}
+
+// newChecklistResponse builds the checklist response for a todo
+func newChecklistResponse(todo *models.Todo) ChecklistResponse {
+	done, total, ratio := todo.ChecklistProgress()
+	items := todo.Checklist
+	if items == nil {
+		items = []models.ChecklistItem{}
+	}
+	return ChecklistResponse{
+		TodoID:          todo.ID,
+		Items:           items,
+		Done:            done,
+		Total:           total,
+		CompletionRatio: ratio,
+	}
+}
+
+// checklist handles /todos/{id}/checklist - GET returns the checklist, POST adds an item,
+// and PATCH and DELETE change or remove the item at ?index=N (0-based)
+func (h *TodoHandler) checklist(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	switch r.Method {
+	case http.MethodGet, http.MethodPost:
+	case http.MethodPatch, http.MethodDelete:
+		allowed = []string{"index"}
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, allowed...) {
+		return
+	}
+
+	id, err := h.extractSubresourceID(r.URL.Path, "checklist")
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+
+	var todo *models.Todo
+	status := http.StatusOK
+	switch r.Method {
+	case http.MethodGet:
+		todo, err = h.serviceFor(r).GetTodoByID(id)
+	case http.MethodPost:
+		var req AddChecklistItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeDecodeError(w, err)
+			return
+		}
+		todo, err = h.serviceFor(r).AddChecklistItem(id, req.Text)
+		status = http.StatusCreated
+	case http.MethodPatch:
+		index, ok := h.checklistIndex(w, r)
+		if !ok {
+			return
+		}
+		var req UpdateChecklistItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.writeDecodeError(w, err)
+			return
+		}
+		todo, err = h.serviceFor(r).SetChecklistItemDone(id, index, req.Done)
+	case http.MethodDelete:
+		index, ok := h.checklistIndex(w, r)
+		if !ok {
+			return
+		}
+		todo, err = h.serviceFor(r).RemoveChecklistItem(id, index)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrChecklistItemNotFound):
+			h.writeErrorResponse(w, http.StatusNotFound, "Checklist item not found")
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+		case strings.Contains(err.Error(), "validation failed"):
+			h.writeValidationError(w, err)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update checklist")
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, status, newChecklistResponse(todo))
+}
+
+// checklistIndex parses the required ?index= of a checklist item, writing a 400 when it is
+// missing or not a non-negative integer
+func (h *TodoHandler) checklistIndex(w http.ResponseWriter, r *http.Request) (int, bool) {
+	index, err := strconv.Atoi(r.URL.Query().Get("index"))
+	if err != nil || index < 0 {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid index: must be a non-negative integer")
+		return 0, false
+	}
+	return index, true
+}
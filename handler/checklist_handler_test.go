@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/service"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveChecklist sends a checklist request through the routes and decodes a successful response
+func serveChecklist(t *testing.T, handler *TodoHandler, method, path, body string) (int, ChecklistResponse) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	var resp ChecklistResponse
+	if w.Code < 300 {
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return w.Code, resp
+}
+
+func TestChecklist(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Move house"})
+	
+	status, resp := serveChecklist(t, handler, http.MethodGet, "/todos/1/checklist", "")
+	if status != http.StatusOK || resp.Total != 0 || resp.CompletionRatio != 0 || resp.Items == nil {
+		t.Fatalf("Expected an empty checklist, got %d %+v", status, resp)
+	}
+	
+	for _, text := range []string{"Pack books", "Pack kitchen", "Return keys", "Cancel internet"} {
+		if status, _ := serveChecklist(t, handler, http.MethodPost, "/todos/1/checklist", `{"text": "`+text+`"}`); status != http.StatusCreated {
+			t.Fatalf("Expected status %d adding %q, got %d", http.StatusCreated, text, status)
+		}
+	}
+	
+	// A PATCH without a body toggles the item
+	serveChecklist(t, handler, http.MethodPatch, "/todos/1/checklist?index=0", "")
+	status, resp = serveChecklist(t, handler, http.MethodPatch, "/todos/1/checklist?index=2", `{"done": true}`)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	if !resp.Items[0].Done || resp.Items[1].Done || !resp.Items[2].Done || resp.Items[3].Done {
+		t.Errorf("Expected items 0 and 2 done, got %+v", resp.Items)
+	}
+	if resp.Done != 2 || resp.Total != 4 || resp.CompletionRatio != 0.5 {
+		t.Errorf("Expected 2 of 4 done (0.5), got %d of %d (%v)", resp.Done, resp.Total, resp.CompletionRatio)
+	}
+	
+	// Toggling again undoes it
+	_, resp = serveChecklist(t, handler, http.MethodPatch, "/todos/1/checklist?index=0", "")
+	if resp.Items[0].Done || resp.CompletionRatio != 0.25 {
+		t.Errorf("Expected item 0 open again and a 0.25 ratio, got %+v", resp)
+	}
+	
+	status, resp = serveChecklist(t, handler, http.MethodDelete, "/todos/1/checklist?index=1", "")
+	if status != http.StatusOK || resp.Total != 3 || resp.Items[1].Text != "Return keys" {
+		t.Errorf("Expected the second item removed, got %d %+v", status, resp)
+	}
+	if resp.CompletionRatio != 1.0/3 {
+		t.Errorf("Expected a ratio of 1/3, got %v", resp.CompletionRatio)
+	}
+}
+
+func TestChecklist_Errors(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Move house"})
+	mockService.AddChecklistItem(1, "Pack books")
+	
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"empty text", http.MethodPost, "/todos/1/checklist", `{"text": " "}`, http.StatusBadRequest},
+		{"text too long", http.MethodPost, "/todos/1/checklist", `{"text": "` + strings.Repeat("a", 201) + `"}`, http.StatusBadRequest},
+		{"unknown todo", http.MethodPost, "/todos/99/checklist", `{"text": "Pack"}`, http.StatusNotFound},
+		{"missing index", http.MethodPatch, "/todos/1/checklist", "", http.StatusBadRequest},
+		{"negative index", http.MethodDelete, "/todos/1/checklist?index=-1", "", http.StatusBadRequest},
+		{"index past the end", http.MethodPatch, "/todos/1/checklist?index=1", "", http.StatusNotFound},
+		{"invalid done", http.MethodPatch, "/todos/1/checklist?index=0", `{"done": "yes"}`, http.StatusBadRequest},
+		{"wrong method", http.MethodPut, "/todos/1/checklist", `{}`, http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := serveChecklist(t, handler, tt.method, tt.path, tt.body); status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// exportTodos handles GET /todos/export - streams the todos matching the list filters
+// as a downloadable JSON array, in the same order and form as GET /todos
+func (h *TodoHandler) exportTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, listFilterParams...) {
+		return
+	}
+
+	filter, err := h.parseListFilter(r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="todos.json"`)
+
+	// Encoded IDs, key styles and timezones are applied to whole responses, so the export is
+	// buffered in those cases
+	if h.ids != nil || h.camelCaseResponses() || responseLocation(w) != time.UTC {
+		todos, err := h.serviceFor(r).ListTodos(filter)
+		if err != nil {
+			w.Header().Del("Content-Disposition")
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to export todos")
+			return
+		}
+		h.writeJSONResponse(w, http.StatusOK, todos)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent once streaming starts, so a failure can only be logged
+	if err := h.serviceFor(r).StreamFiltered(w, filter); err != nil {
+		log.Printf("Failed to stream export: %v", err)
+	}
+}
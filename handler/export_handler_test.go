@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Open"})
+	done, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Done"})
+	mockService.UpdateTodo(done.ID, service.UpdateTodoInput{Title: "Done", Completed: true})
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/export?completed=true", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got == "" {
+		t.Error("Expected a Content-Disposition header")
+	}
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 1 || todos[0].Title != "Done" {
+		t.Errorf("Expected only the completed todo, got %+v", todos)
+	}
+}
+
+// TestExportTodos_MatchesList tests that the export has the same todos, order and timestamps
+// as GET /todos, whether it is streamed or buffered
+func TestExportTodos_MatchesList(t *testing.T) {
+	repo := repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))
+	todoService := service.NewTodoService(repo)
+	routes := NewTodoHandler(todoService).SetupRoutes()
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, err := todoService.CreateTodo(service.CreateTodoInput{Title: title}); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+	}
+	if _, err := todoService.PinTodo(3); err != nil {
+		t.Fatalf("Failed to pin todo: %v", err)
+	}
+	
+	get := func(url, timezone string) []map[string]any {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if timezone != "" {
+			req.Header.Set("X-Timezone", timezone)
+		}
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, url, w.Code, w.Body.String())
+		}
+		var todos []map[string]any
+		if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+			t.Fatalf("Failed to decode %s: %v", url, err)
+		}
+		return todos
+	}
+	
+	for _, timezone := range []string{"", "Asia/Taipei"} {
+		listed := get("/todos", timezone)
+		exported := get("/todos/export", timezone)
+		if !reflect.DeepEqual(listed, exported) {
+			t.Errorf("Expected the export to match the list with X-Timezone %q\nlist:   %v\nexport: %v", timezone, listed, exported)
+		}
+		if len(exported) != 3 || exported[0]["title"] != "Third" {
+			t.Errorf("Expected the pinned todo first, got %v", exported)
+		}
+	}
+}
+
+func TestExportTodos_Errors(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{"invalid filter", http.MethodGet, "/todos/export?completed=maybe", http.StatusBadRequest},
+		{"wrong method", http.MethodDelete, "/todos/export", http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
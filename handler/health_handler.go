@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"go-crud-todo-list/models"
+	"net/http"
+)
+
+// HealthResponse represents the body returned by health check endpoints
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// healthz handles GET /healthz - reports that the process is up and serving requests
+func (h *TodoHandler) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// ReadinessResponse represents the body returned by the readiness check, with the
+// outcome of each check that ran
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// readyz handles GET /readyz - reports whether the todo data can be served, and with
+// DeepReadiness whether it survives a save and load round trip
+func (h *TodoHandler) readyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	resp := ReadinessResponse{Status: "ready", Checks: map[string]string{}}
+
+	if _, err := h.serviceFor(r).CountTodos(models.ListFilter{}); err != nil {
+		resp.Status = "unavailable"
+		resp.Checks["storage"] = err.Error()
+	} else {
+		resp.Checks["storage"] = "ok"
+	}
+
+	if h.config.DeepReadiness {
+		if err := h.serviceFor(r).VerifyStorage(); err != nil {
+			resp.Status = "unavailable"
+			resp.Checks["roundtrip"] = err.Error()
+		} else {
+			resp.Checks["roundtrip"] = "ok"
+		}
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ready" {
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSONResponse(w, status, resp)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthz(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var resp HealthResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Status != "ok" {
+		t.Errorf("Expected status ok, got %q", resp.Status)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name          string
+		deep          bool
+		failGet       bool
+		wantStatus    int
+		wantReadiness string
+		wantChecks    map[string]string
+	}{
+		{"basic", false, false, http.StatusOK, "ready", map[string]string{"storage": "ok"}},
+		{"deep healthy", true, false, http.StatusOK, "ready", map[string]string{"storage": "ok", "roundtrip": "ok"}},
+		{"deep failing", true, true, http.StatusServiceUnavailable, "unavailable", map[string]string{"storage": "service error", "roundtrip": "service error"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			mockService.failGet = tt.failGet
+			config := DefaultHandlerConfig()
+			config.DeepReadiness = tt.deep
+			handler := NewTodoHandlerWithConfig(mockService, config)
+			
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			
+			var resp ReadinessResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Status != tt.wantReadiness {
+				t.Errorf("Expected status %q, got %q", tt.wantReadiness, resp.Status)
+			}
+			if len(resp.Checks) != len(tt.wantChecks) {
+				t.Errorf("Expected checks %v, got %v", tt.wantChecks, resp.Checks)
+			}
+			for name, want := range tt.wantChecks {
+				if resp.Checks[name] != want {
+					t.Errorf("Expected check %s = %q, got %q", name, want, resp.Checks[name])
+				}
+			}
+		})
+	}
+}
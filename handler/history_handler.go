@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"go-crud-todo-list/service"
+	"net/http"
+	"strings"
+)
+
+// getTodoHistory handles GET /todos/{id}/history - returns the todo's audit entries oldest first
+func (h *TodoHandler) getTodoHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	id, err := h.extractSubresourceID(r.URL.Path, "history")
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+
+	entries, err := h.serviceFor(r).GetTodoHistory(id)
+	if err != nil {
+		if errors.Is(err, service.ErrHistoryUnavailable) {
+			h.writeErrorResponse(w, http.StatusNotImplemented, "History is unavailable: audit logging is disabled")
+			return
+		}
+		if strings.Contains(err.Error(), "invalid todo ID") {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve history")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, entries)
+}
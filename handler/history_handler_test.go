@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetTodoHistory(t *testing.T) {
+	mockService := NewMockTodoService()
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	mockService.history = []audit.Entry{
+		{Timestamp: start, Action: audit.ActionCreate, TodoID: 1, After: &models.Todo{ID: 1, Title: "v1"}},
+		{Timestamp: start.Add(time.Minute), Action: audit.ActionCreate, TodoID: 2, After: &models.Todo{ID: 2, Title: "Other"}},
+		{Timestamp: start.Add(2 * time.Minute), Action: audit.ActionUpdate, TodoID: 1, Before: &models.Todo{ID: 1, Title: "v1"}, After: &models.Todo{ID: 1, Title: "v2"}},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedEntries int
+	}{
+		{"todo with history", "/todos/1/history", http.StatusOK, 2},
+		{"todo without history", "/todos/3/history", http.StatusOK, 0},
+		{"invalid ID", "/todos/abc/history", http.StatusBadRequest, 0},
+		{"missing ID", "/todos/history", http.StatusBadRequest, 0},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			
+			var entries []audit.Entry
+			if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if entries == nil || len(entries) != tt.expectedEntries {
+				t.Errorf("Expected %d entries, got %v", tt.expectedEntries, entries)
+			}
+		})
+	}
+}
+
+func TestGetTodoHistory_AuditDisabled(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/1/history", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected status %d, got %d", http.StatusNotImplemented, w.Code)
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"strings"
+)
+
+// ID encodings supported for todo IDs in URLs, request bodies and responses
+const (
+	// IDEncodingNone exposes todo IDs as plain integers
+	IDEncodingNone = "none"
+	// IDEncodingHashID exposes todo IDs as short opaque strings derived from a salt
+	IDEncodingHashID = "hashid"
+)
+
+// ValidateIDEncoding checks that encoding is supported and has the settings it needs
+func ValidateIDEncoding(encoding, salt string) error {
+	switch encoding {
+	case IDEncodingNone:
+		return nil
+	case IDEncodingHashID:
+		if salt == "" {
+			return fmt.Errorf("%s encoding requires a salt", IDEncodingHashID)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported ID encoding %q (supported: %s, %s)", encoding, IDEncodingNone, IDEncodingHashID)
+}
+
+// hashIDAlphabet is the base-62 alphabet encoded IDs are written in
+const hashIDAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// idCodec reversibly maps positive integer IDs to opaque strings. The ID is scrambled by
+// multiplying with a salt-derived odd constant (a bijection modulo 2^32) and XORing with
+// a salt-derived key, then written in base 62. It hides sequence, not secrets.
+type idCodec struct {
+	multiplier uint32
+	inverse    uint32
+	key        uint32
+}
+
+// newIDCodec derives an idCodec from salt
+func newIDCodec(salt string) *idCodec {
+	h := fnv.New64a()
+	h.Write([]byte(salt))
+	sum := h.Sum64()
+
+	c := &idCodec{multiplier: uint32(sum) | 1, key: uint32(sum >> 32)}
+	// Newton's iteration for the multiplicative inverse modulo 2^32
+	c.inverse = c.multiplier
+	for i := 0; i < 5; i++ {
+		c.inverse *= 2 - c.multiplier*c.inverse
+	}
+	return c
+}
+
+// encode returns the opaque form of id
+func (c *idCodec) encode(id int) string {
+	v := uint32(id)*c.multiplier ^ c.key
+	if v == 0 {
+		return hashIDAlphabet[:1]
+	}
+	var buf [6]byte
+	i := len(buf)
+	for v > 0 {
+		i--
+		buf[i] = hashIDAlphabet[v%62]
+		v /= 62
+	}
+	return string(buf[i:])
+}
+
+// decode returns the ID an encoded value stands for, rejecting anything encode could not
+// have produced
+func (c *idCodec) decode(value string) (int, error) {
+	if value == "" || len(value) > 6 {
+		return 0, fmt.Errorf("malformed encoded ID %q", value)
+	}
+	var v uint64
+	for _, r := range value {
+		digit := strings.IndexRune(hashIDAlphabet, r)
+		if digit < 0 {
+			return 0, fmt.Errorf("malformed encoded ID %q", value)
+		}
+		v = v*62 + uint64(digit)
+	}
+	if v > math.MaxUint32 {
+		return 0, fmt.Errorf("malformed encoded ID %q", value)
+	}
+
+	id := int((uint32(v) ^ c.key) * c.inverse)
+	if id <= 0 || id > math.MaxInt32 || c.encode(id) != value {
+		return 0, fmt.Errorf("malformed encoded ID %q", value)
+	}
+	return id, nil
+}
+
+// Field names whose values are todo IDs (idFields) or lists of them (idListFields)
+var (
+	idFields     = map[string]bool{"id": true, "todo_id": true}
+	idListFields = map[string]bool{"ids": true, "missing_ids": true, "duplicate_ids": true, "ids_not_below_next_id": true}
+)
+
+// rewriteIDs walks a decoded JSON value and replaces every todo ID field using convert
+func rewriteIDs(value interface{}, convert func(interface{}) (interface{}, error)) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, child := range v {
+			switch {
+			case idFields[field]:
+				converted, err := convert(child)
+				if err != nil {
+					return err
+				}
+				v[field] = converted
+			case idListFields[field]:
+				list, ok := child.([]interface{})
+				if !ok {
+					continue
+				}
+				for i := range list {
+					converted, err := convert(list[i])
+					if err != nil {
+						return err
+					}
+					list[i] = converted
+				}
+			default:
+				if err := rewriteIDs(child, convert); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := rewriteIDs(child, convert); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeJSONWithNumbers decodes JSON into generic values, keeping numbers exact
+func decodeJSONWithNumbers(r io.Reader) (interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// encodeResponseIDs returns data with every todo ID replaced by its encoded form
+func (c *idCodec) encodeResponseIDs(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeJSONWithNumbers(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	err = rewriteIDs(value, func(id interface{}) (interface{}, error) {
+		number, ok := id.(json.Number)
+		if !ok {
+			return id, nil
+		}
+		n, err := number.Int64()
+		if err != nil || n <= 0 || n > math.MaxInt32 {
+			return id, nil
+		}
+		return c.encode(int(n)), nil
+	})
+	return value, err
+}
+
+// decodeRequestIDs rewrites the encoded todo IDs in a JSON request body to integers
+func (c *idCodec) decodeRequestIDs(body io.Reader) ([]byte, error) {
+	value, err := decodeJSONWithNumbers(body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rewriteIDs(value, func(id interface{}) (interface{}, error) {
+		encoded, ok := id.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: IDs must be encoded strings", errInvalidID)
+		}
+		decoded, err := c.decode(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidID, err)
+		}
+		return decoded, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIDCodec_RoundTrip(t *testing.T) {
+	codec := newIDCodec("pepper")
+	
+	seen := make(map[string]int)
+	for _, id := range []int{1, 2, 3, 42, 1000, 65536, math.MaxInt32} {
+		encoded := codec.encode(id)
+		decoded, err := codec.decode(encoded)
+		if err != nil || decoded != id {
+			t.Errorf("Expected %q to decode to %d, got %d (err %v)", encoded, id, decoded, err)
+		}
+		if other, ok := seen[encoded]; ok {
+			t.Errorf("IDs %d and %d both encode to %q", other, id, encoded)
+		}
+		seen[encoded] = id
+	}
+	
+	if newIDCodec("salt").encode(1) == codec.encode(1) {
+		t.Error("Expected different salts to produce different encodings")
+	}
+}
+
+func TestIDCodec_RejectsMalformed(t *testing.T) {
+	codec := newIDCodec("pepper")
+	
+	for _, value := range []string{"", "not-an-id", "ab_c", "abcdefg", "zzzzzz", "0" + codec.encode(7)} {
+		if id, err := codec.decode(value); err == nil {
+			t.Errorf("Expected %q to be rejected, got ID %d", value, id)
+		}
+	}
+}
+
+func TestIDEncoding_Endpoints(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.IDEncoding = IDEncodingHashID
+	config.IDSalt = "pepper"
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	codec := newIDCodec("pepper")
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "Opaque"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	
+	var created map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	encoded, ok := created["id"].(string)
+	if !ok || encoded != codec.encode(1) {
+		t.Fatalf("Expected encoded ID %q, got %v", codec.encode(1), created["id"])
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/todos/"+encoded, nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":"`+encoded+`"`) {
+		t.Errorf("Expected the todo by encoded ID, got %d: %s", w.Code, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/todos/not-an-id", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a malformed ID, got %d", http.StatusBadRequest, w.Code)
+	}
+	
+	body := `{"ids": ["` + encoded + `", "` + codec.encode(99) + `"], "add": ["work"]}`
+	req = httptest.NewRequest(http.MethodPost, "/todos/tag", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var result struct {
+		Updated    []map[string]interface{} `json:"updated"`
+		MissingIDs []string                 `json:"missing_ids"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0]["id"] != encoded {
+		t.Errorf("Expected the encoded todo to be tagged, got %+v", result.Updated)
+	}
+	if len(result.MissingIDs) != 1 || result.MissingIDs[0] != codec.encode(99) {
+		t.Errorf("Expected the missing ID to be reported encoded, got %v", result.MissingIDs)
+	}
+	
+	req = httptest.NewRequest(http.MethodPost, "/todos/tag", strings.NewReader(`{"ids": [1], "add": ["work"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a plain ID in the body, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-todo-list/models"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultImportProgressEvery is how many records an import saves at once, and so processes
+// between progress events
+const DefaultImportProgressEvery = 100
+
+// ImportStatus reports how many todos an import has created, and why it stopped if it failed.
+// It is the JSON response body, and the data of each server-sent event.
+type ImportStatus struct {
+	Imported int    `json:"imported"`
+	Error    string `json:"error,omitempty"`
+}
+
+// errImportAborted reports that the client went away before the import finished
+var errImportAborted = errors.New("import aborted: client disconnected")
+
+// acceptsEventStream reports whether the client asked for a server-sent events response
+func acceptsEventStream(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
+
+// eventStream writes server-sent events, flushing each so the client sees it immediately
+type eventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// newEventStream sends the event stream headers. HTTP/1.x closes the request body once the
+// response starts unless full duplex is enabled, so handlers can keep reading the body
+// while they stream events; HTTP/2 is always full duplex.
+func newEventStream(w http.ResponseWriter) *eventStream {
+	rc := http.NewResponseController(w)
+	_ = rc.EnableFullDuplex()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	return &eventStream{w: w, rc: rc}
+}
+
+// send writes one event with data encoded as JSON; an error means the client is gone
+func (s *eventStream) send(event string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, encoded); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// heartbeat writes a comment line, which clients ignore, to keep an idle stream open
+// through proxies and detect clients that have gone away
+func (s *eventStream) heartbeat() error {
+	if _, err := io.WriteString(s.w, ": heartbeat\n\n"); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// importTodos handles POST /todos/import - creates a todo for every record in a JSON Lines
+// body of exported todos, as it is read, saving every progress_every records at once. With
+// Accept: text/event-stream, "progress" events report the running count after each save,
+// followed by "done" or "error". A client that disconnects aborts the import; todos saved
+// before then are kept. A failed import without events still answers with ImportStatus,
+// counting the todos it kept.
+func (h *TodoHandler) importTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "progress_every") {
+		return
+	}
+
+	every := DefaultImportProgressEvery
+	if value := r.URL.Query().Get("progress_every"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid progress_every: must be a positive integer")
+			return
+		}
+		every = n
+	}
+
+	// A large import outlives the server's read and write timeouts
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	var stream *eventStream
+	if acceptsEventStream(r) {
+		stream = newEventStream(w)
+	}
+
+	imported, err := h.importRecords(r, every, func(imported int) error {
+		if stream == nil {
+			return nil
+		}
+		return stream.send("progress", ImportStatus{Imported: imported})
+	})
+
+	if errors.Is(err, errImportAborted) {
+		log.Printf("Import aborted after %d todos: client disconnected", imported)
+		return
+	}
+
+	if stream != nil {
+		if err != nil {
+			stream.send("error", ImportStatus{Imported: imported, Error: err.Error()})
+			return
+		}
+		stream.send("done", ImportStatus{Imported: imported})
+		return
+	}
+
+	// Todos imported before a failure are kept, so the response reports how many there were
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			h.writeJSONResponse(w, http.StatusRequestEntityTooLarge, ImportStatus{Imported: imported, Error: "Request body too large"})
+		case strings.Contains(err.Error(), "validation failed") || strings.Contains(err.Error(), "invalid JSON"):
+			h.writeJSONResponse(w, http.StatusBadRequest, ImportStatus{Imported: imported, Error: err.Error()})
+		default:
+			log.Printf("Import failed after %d todos: %v", imported, err)
+			h.writeJSONResponse(w, http.StatusInternalServerError, ImportStatus{Imported: imported, Error: "Failed to import todos"})
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, ImportStatus{Imported: imported})
+}
+
+// importRecords decodes the records in the request body as they arrive and imports them in
+// batches of every records, saving once per batch and calling progress after each. It
+// stops at the first bad record, importing the records before it, or with errImportAborted
+// when the client disconnects or progress cannot be delivered; an unsaved batch is dropped.
+func (h *TodoHandler) importRecords(r *http.Request, every int, progress func(imported int) error) (int, error) {
+	decoder := json.NewDecoder(r.Body)
+	imported := 0
+	batch := make([]models.Todo, 0, every)
+
+	// flush imports the pending batch; record is the number of the first record in it
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		record := imported + 1
+		n, err := h.serviceFor(r).ImportTodos(batch)
+		imported += n
+		batch = batch[:0]
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record+n, err)
+		}
+		return nil
+	}
+
+	for record := 1; ; record++ {
+		if r.Context().Err() != nil {
+			return imported, errImportAborted
+		}
+
+		var todo models.Todo
+		if err := decoder.Decode(&todo); err != nil {
+			if r.Context().Err() != nil {
+				return imported, errImportAborted
+			}
+			// The records read before the failure are still imported
+			if flushErr := flush(); flushErr != nil {
+				return imported, flushErr
+			}
+			if errors.Is(err, io.EOF) {
+				return imported, nil
+			}
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return imported, err
+			}
+			return imported, fmt.Errorf("record %d: invalid JSON: %w", record, err)
+		}
+
+		batch = append(batch, todo)
+		if len(batch) < every {
+			continue
+		}
+		if err := flush(); err != nil {
+			return imported, err
+		}
+		if err := progress(imported); err != nil {
+			return imported, errImportAborted
+		}
+	}
+}
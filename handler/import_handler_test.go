@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-crud-todo-list/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// importBody returns count JSON Lines records starting at record first
+func importBody(first, count int) string {
+	var b strings.Builder
+	for i := first; i < first+count; i++ {
+		fmt.Fprintf(&b, "{\"title\": \"Imported %d\"}\n", i)
+	}
+	return b.String()
+}
+
+// readEvent reads the next server-sent event, returning its name and decoded data
+func readEvent(t *testing.T, r *bufio.Reader) (string, ImportStatus) {
+	t.Helper()
+	var event string
+	var status ImportStatus
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return event, status
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &status); err != nil {
+				t.Fatalf("Failed to decode event data: %v", err)
+			}
+		}
+	}
+}
+
+func TestImportTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos/import", strings.NewReader(importBody(1, 3)))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp ImportStatus
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Imported != 3 || len(mockService.todos) != 3 {
+		t.Errorf("Expected 3 imported todos, got %d (%d stored)", resp.Imported, len(mockService.todos))
+	}
+	if mockService.todos[2].Title != "Imported 3" {
+		t.Errorf("Unexpected imported todo: %+v", mockService.todos[2])
+	}
+}
+
+func TestImportTodos_BadRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"validation", importBody(1, 1) + "{\"title\": \"\"}\n" + importBody(3, 1)},
+		{"invalid JSON", importBody(1, 1) + "{\"title\": \n"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			handler := NewTodoHandler(mockService)
+			
+			req := httptest.NewRequest(http.MethodPost, "/todos/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			var resp ImportStatus
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !strings.Contains(resp.Error, "record 2") {
+				t.Errorf("Expected the failing record in the error, got %q", resp.Error)
+			}
+			// Records before the bad one stay imported, and the response says how many
+			if resp.Imported != 1 || len(mockService.todos) != 1 {
+				t.Errorf("Expected 1 imported todo, got %d (%d stored)", resp.Imported, len(mockService.todos))
+			}
+		})
+	}
+}
+
+func TestImportTodos_ProgressEvents(t *testing.T) {
+	mockService := NewMockTodoService()
+	server := httptest.NewServer(NewTodoHandler(mockService).SetupHandler())
+	defer server.Close()
+	
+	body, bodyWriter := io.Pipe()
+	resume := make(chan struct{})
+	go func() {
+		io.WriteString(bodyWriter, importBody(1, 100))
+		// Hold back the rest until the first progress event has arrived
+		<-resume
+		io.WriteString(bodyWriter, importBody(101, 150))
+		bodyWriter.Close()
+	}()
+	
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/todos/import?progress_every=100", body)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Accept", "text/event-stream")
+	
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got Content-Type %q", ct)
+	}
+	events := bufio.NewReader(resp.Body)
+	
+	if event, status := readEvent(t, events); event != "progress" || status.Imported != 100 {
+		t.Fatalf("Expected progress at 100 while the body is still open, got %s %+v", event, status)
+	}
+	close(resume)
+	
+	if event, status := readEvent(t, events); event != "progress" || status.Imported != 200 {
+		t.Errorf("Expected progress at 200, got %s %+v", event, status)
+	}
+	if event, status := readEvent(t, events); event != "done" || status.Imported != 250 {
+		t.Errorf("Expected done at 250, got %s %+v", event, status)
+	}
+	if len(mockService.todos) != 250 {
+		t.Errorf("Expected 250 imported todos, got %d", len(mockService.todos))
+	}
+}
+
+func TestImportTodos_ErrorEvent(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	body := importBody(1, 2) + "{\"title\": \"\"}\n"
+	req := httptest.NewRequest(http.MethodPost, "/todos/import?progress_every=1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	events := bufio.NewReader(w.Body)
+	for want := 1; want <= 2; want++ {
+		if event, status := readEvent(t, events); event != "progress" || status.Imported != want {
+			t.Fatalf("Expected progress at %d, got %s %+v", want, event, status)
+		}
+	}
+	event, status := readEvent(t, events)
+	if event != "error" || status.Imported != 2 || !strings.Contains(status.Error, "record 3: validation failed") {
+		t.Errorf("Expected an error event for record 3, got %s %+v", event, status)
+	}
+}
+
+// disconnectingImportService cancels the request after a number of batches, as a client
+// disconnecting mid-import would
+type disconnectingImportService struct {
+	*MockTodoService
+	cancel func()
+	after  int
+	calls  int
+}
+
+func (s *disconnectingImportService) ImportTodos(todos []models.Todo) (int, error) {
+	s.calls++
+	if s.calls == s.after {
+		s.cancel()
+	}
+	return s.MockTodoService.ImportTodos(todos)
+}
+
+func TestImportTodos_ClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockService := &disconnectingImportService{MockTodoService: NewMockTodoService(), cancel: cancel, after: 1}
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos/import?progress_every=2", strings.NewReader(importBody(1, 5))).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if mockService.calls != 1 || len(mockService.todos) != 2 {
+		t.Errorf("Expected the import to stop after the first batch of 2 todos, got %d calls and %d todos", mockService.calls, len(mockService.todos))
+	}
+}
+
+// TestImportTodos_PastServerTimeouts tests that an import whose body arrives more slowly
+// than the server's read and write timeouts still completes
+func TestImportTodos_PastServerTimeouts(t *testing.T) {
+	mockService := NewMockTodoService()
+	server := httptest.NewUnstartedServer(NewTodoHandler(mockService).SetupHandler())
+	server.Config.ReadTimeout = 100 * time.Millisecond
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+	
+	body, bodyWriter := io.Pipe()
+	go func() {
+		for i := 1; i <= 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			io.WriteString(bodyWriter, importBody(i, 1))
+		}
+		bodyWriter.Close()
+	}()
+	
+	resp, err := http.Post(server.URL+"/todos/import", "application/x-ndjson", body)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	
+	var status ImportStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || status.Imported != 3 || len(mockService.todos) != 3 {
+		t.Errorf("Expected all 3 todos imported, got %d %+v (%d stored)", resp.StatusCode, status, len(mockService.todos))
+	}
+}
+
+func TestImportTodos_BodyLimit(t *testing.T) {
+	config := DefaultHandlerConfig()
+	config.MaxBodyBytes = 64
+	config.MaxImportBytes = 256
+	mockService := NewMockTodoService()
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	
+	// Imports are held to MaxImportBytes, not the MaxBodyBytes of other requests
+	body := importBody(1, 5)
+	if len(body) <= int(config.MaxBodyBytes) || len(body) > int(config.MaxImportBytes) {
+		t.Fatalf("Test body of %d bytes does not fall between the limits", len(body))
+	}
+	req := httptest.NewRequest(http.MethodPost, "/todos/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodPost, "/todos/import", strings.NewReader(importBody(6, 20)))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w = httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+	var resp ImportStatus
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Imported == 0 || resp.Imported != len(mockService.todos)-5 {
+		t.Errorf("Expected the records read before the limit reported, got %d (%d stored)", resp.Imported, len(mockService.todos))
+	}
+}
+
+func TestImportTodos_ContentTypeOnlyOnImport(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "Buy milk"}`))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "must be application/json") {
+		t.Errorf("Expected JSON Lines to be rejected outside imports, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestImportTodos_InvalidProgressEvery(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos/import?progress_every=0", strings.NewReader(importBody(1, 1)))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSON key naming styles for response bodies
+const (
+	// JSONCaseSnake renders keys as declared on the models, e.g. created_at
+	JSONCaseSnake = "snake"
+	// JSONCaseCamel renders keys in camelCase, e.g. createdAt
+	JSONCaseCamel = "camel"
+)
+
+// ValidateJSONCase checks that style names a supported JSON key naming style
+func ValidateJSONCase(style string) error {
+	switch style {
+	case JSONCaseSnake, JSONCaseCamel:
+		return nil
+	}
+	return fmt.Errorf("unsupported JSON case %q (supported: %s, %s)", style, JSONCaseSnake, JSONCaseCamel)
+}
+
+// camelCaseResponses reports whether response keys are rewritten to camelCase
+func (h *TodoHandler) camelCaseResponses() bool {
+	return h.config.JSONCase == JSONCaseCamel
+}
+
+// camelCaseKey converts a snake_case key to camelCase
+func camelCaseKey(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelCaseKeys walks a decoded JSON value and renames every object key to camelCase
+func camelCaseKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			renamed[camelCaseKey(key)] = camelCaseKeys(child)
+		}
+		return renamed
+	case []interface{}:
+		for i := range v {
+			v[i] = camelCaseKeys(v[i])
+		}
+	}
+	return value
+}
+
+// encodeResponseCase returns data with its keys renamed to camelCase
+func encodeResponseCase(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeJSONWithNumbers(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	return camelCaseKeys(value), nil
+}
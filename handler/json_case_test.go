@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCamelCaseKey(t *testing.T) {
+	tests := map[string]string{
+		"id":                    "id",
+		"created_at":            "createdAt",
+		"ids_not_below_next_id": "idsNotBelowNextId",
+	}
+	
+	for key, expected := range tests {
+		if got := camelCaseKey(key); got != expected {
+			t.Errorf("camelCaseKey(%q) = %q, expected %q", key, got, expected)
+		}
+	}
+}
+
+func TestJSONCase_CamelResponses(t *testing.T) {
+	mockService := NewMockTodoService()
+	due := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo", DueDate: &due})
+	config := DefaultHandlerConfig()
+	config.JSONCase = JSONCaseCamel
+	mux := NewTodoHandlerWithConfig(mockService, config).SetupRoutes()
+	
+	for _, path := range []string{"/todos/1", "/todos", "/todos/export"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", path, http.StatusOK, w.Code)
+		}
+		
+		var todo map[string]interface{}
+		if path == "/todos/1" {
+			if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+		} else {
+			var todos []map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&todos); err != nil || len(todos) != 1 {
+				t.Fatalf("%s: expected one todo, got %v (err %v)", path, todos, err)
+			}
+			todo = todos[0]
+		}
+		
+		for _, key := range []string{"createdAt", "updatedAt", "dueDate"} {
+			if _, ok := todo[key]; !ok {
+				t.Errorf("%s: expected key %q, got %v", path, key, todo)
+			}
+		}
+		if _, ok := todo["created_at"]; ok {
+			t.Errorf("%s: expected no snake_case keys, got %v", path, todo)
+		}
+	}
+}
+
+func TestJSONCase_SnakeByDefault(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo"})
+	mux := NewTodoHandler(mockService).SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	var todo map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if _, ok := todo["created_at"]; !ok {
+		t.Errorf("Expected snake_case keys by default, got %v", todo)
+	}
+}
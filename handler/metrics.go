@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"io"
+	"log"
+	"net/http"
+)
+
+// meterName identifies the instrumentation that records the request metrics
+const meterName = "go-crud-todo-list/handler"
+
+// requestMetrics holds the instruments recording request and response body sizes
+type requestMetrics struct {
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+}
+
+// newRequestMetrics creates the body size histograms from provider
+func newRequestMetrics(provider metric.MeterProvider) requestMetrics {
+	meter := provider.Meter(meterName)
+	// Instrument creation only fails for invalid names; the instruments returned are still usable
+	requestSize, _ := meter.Int64Histogram("http.server.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server request bodies as received"))
+	responseSize, _ := meter.Int64Histogram("http.server.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server response bodies as sent"))
+	return requestMetrics{requestSize: requestSize, responseSize: responseSize}
+}
+
+// record adds the body sizes of one request, tagged with its method and status
+func (m requestMetrics) record(ctx context.Context, method string, status int, requestBytes, responseBytes int64) {
+	attrs := metric.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.Int("http.response.status_code", status),
+	)
+	m.requestSize.Record(ctx, requestBytes, attrs)
+	m.responseSize.Record(ctx, responseBytes, attrs)
+}
+
+// countingReadCloser counts the bytes read from a request body
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read counts the bytes read before returning them
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// warnLargeRequest logs requests whose body exceeds the LargeRequestBytes soft threshold,
+// which are allowed but point at clients sending bloated payloads
+func (h *TodoHandler) warnLargeRequest(r *http.Request, size int64) {
+	if h.config.LargeRequestBytes <= 0 || size <= h.config.LargeRequestBytes {
+		return
+	}
+	log.Printf("warning: large request body: %s %s sent %d bytes (soft limit %d)",
+		r.Method, r.URL.RequestURI(), size, h.config.LargeRequestBytes)
+}
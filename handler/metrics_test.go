@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// histogramSum returns the total recorded by the named histogram
+func histogramSum(t *testing.T, rm metricdata.ResourceMetrics, name string) int64 {
+	t.Helper()
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != name {
+				continue
+			}
+			histogram, ok := m.Data.(metricdata.Histogram[int64])
+			if !ok {
+				t.Fatalf("Expected %s to be an int64 histogram, got %T", name, m.Data)
+			}
+			var sum int64
+			for _, point := range histogram.DataPoints {
+				sum += point.Sum
+			}
+			return sum
+		}
+	}
+	t.Fatalf("Expected a %s metric", name)
+	return 0
+}
+
+func TestLoggingMiddleware_BodySizeMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	config := DefaultHandlerConfig()
+	config.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	handler := NewTodoHandlerWithConfig(NewMockTodoService(), config).SetupHandler()
+	
+	body := `{"title": "Measured"}`
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.ServeHTTP(w, req)
+	
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+	if got := histogramSum(t, rm, "http.server.request.body.size"); got != int64(len(body)) {
+		t.Errorf("Expected request body size %d, got %d", len(body), got)
+	}
+	if got := histogramSum(t, rm, "http.server.response.body.size"); got != int64(w.Body.Len()) {
+		t.Errorf("Expected response body size %d, got %d", w.Body.Len(), got)
+	}
+}
+
+func TestLoggingMiddleware_LargeRequestWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		wantWarning bool
+	}{
+		{"under soft limit", "short", false},
+		{"over soft limit", strings.Repeat("x", 200), true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+			
+			config := DefaultHandlerConfig()
+			config.LargeRequestBytes = 128
+			handler := NewTodoHandlerWithConfig(NewMockTodoService(), config).SetupHandler()
+			
+			body := `{"title": "Payload", "description": "` + tt.description + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.ServeHTTP(w, req)
+			
+			// The soft limit only warns; the request itself is still served
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			warned := strings.Contains(logs.String(), "warning: large request body: POST /todos")
+			if warned != tt.wantWarning {
+				t.Errorf("Expected warning=%v, got logs: %s", tt.wantWarning, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package handler
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bodyMiddleware decodes content-encoded request bodies and enforces the body size limit.
+// The limit is applied to the decompressed stream so small gzip payloads cannot expand
+// into arbitrarily large bodies.
+func (h *TodoHandler) bodyMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return h.bodyMiddlewareWithLimit(h.config.MaxBodyBytes, next)
+}
+
+// bodyMiddlewareWithLimit is bodyMiddleware with its own body size limit in place of
+// MaxBodyBytes; zero disables the limit
+func (h *TodoHandler) bodyMiddlewareWithLimit(maxBytes int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+
+		switch encoding {
+		case "", "identity":
+			// Body is not encoded
+		case "gzip":
+			if !h.config.DecodeGzipRequests {
+				h.writeErrorResponse(w, http.StatusUnsupportedMediaType, "gzip request bodies are not accepted")
+				return
+			}
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				h.writeErrorResponse(w, http.StatusBadRequest, "Invalid gzip body")
+				return
+			}
+			defer gz.Close()
+
+			r.Body = gz
+			r.ContentLength = -1
+			r.Header.Del("Content-Encoding")
+		default:
+			h.writeErrorResponse(w, http.StatusUnsupportedMediaType, "Unsupported Content-Encoding: "+encoding)
+			return
+		}
+
+		if maxBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+
+		next(w, r)
+	}
+}
+
+// writeDecodeError maps a request body decoding failure to the matching error response
+func (h *TodoHandler) writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	var corruptErr flate.CorruptInputError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		h.writeErrorResponse(w, http.StatusRequestEntityTooLarge, "Request body too large")
+	case errors.Is(err, gzip.ErrChecksum), errors.Is(err, gzip.ErrHeader), errors.As(err, &corruptErr):
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid gzip body")
+	default:
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+	}
+}
+
+// cappedBuffer keeps at most limit bytes of what is written to it while reporting
+// every write as successful, so it can sit behind a tee without affecting the stream
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write stores the portion of p that fits under the limit
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := c.limit - c.buf.Len()
+	if remaining < len(p) {
+		c.truncated = true
+		if remaining > 0 {
+			c.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	c.buf.Write(p)
+	return len(p), nil
+}
+
+// String returns the captured content, marking truncation
+func (c *cappedBuffer) String() string {
+	if c.truncated {
+		return c.buf.String() + "...(truncated)"
+	}
+	return c.buf.String()
+}
+
+// teeReadCloser mirrors everything read from the body into a capture buffer
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder captures the status code, size and, optionally, the body of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+	body   *cappedBuffer
+}
+
+// WriteHeader records the status code before delegating
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write copies the response body into the capture buffer when enabled
+func (rr *responseRecorder) Write(p []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	if rr.body != nil {
+		rr.body.Write(p)
+	}
+	n, err := rr.ResponseWriter.Write(p)
+	rr.size += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// loggingMiddleware logs each request with its status and duration, records its body
+// sizes, and warns about request bodies over LargeRequestBytes. When DebugBodies is enabled
+// it also logs the (truncated) request and response bodies.
+func (h *TodoHandler) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		// Count the body as received, before any decompression by the handlers. A handler
+		// may reject a request without reading its body, so a declared length counts too.
+		declaredSize := r.ContentLength
+		var requestSize *countingReadCloser
+		if r.Body != nil {
+			requestSize = &countingReadCloser{ReadCloser: r.Body}
+			r.Body = requestSize
+		}
+
+		var requestBody *cappedBuffer
+		if h.config.DebugBodies {
+			requestBody = &cappedBuffer{limit: h.config.DebugBodyMaxBytes}
+			recorder.body = &cappedBuffer{limit: h.config.DebugBodyMaxBytes}
+			if r.Body != nil {
+				r.Body = teeReadCloser{Reader: io.TeeReader(r.Body, requestBody), Closer: r.Body}
+			}
+		}
+
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), status, time.Since(start))
+
+		requestBytes := max(declaredSize, 0)
+		if requestSize != nil {
+			requestBytes = max(requestBytes, requestSize.n)
+		}
+		h.metrics.record(r.Context(), r.Method, status, requestBytes, recorder.size)
+		h.warnLargeRequest(r, requestBytes)
+
+		if h.config.DebugBodies {
+			log.Printf("request body: %s", requestBody)
+			log.Printf("response body: %s", recorder.body)
+		}
+	})
+}
+
+// overridableMethods lists the methods a POST may be turned into via X-HTTP-Method-Override
+var overridableMethods = []string{http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// methodOverrideMiddleware applies the X-HTTP-Method-Override header of POST requests
+// before routing, for clients behind proxies that only allow GET and POST
+func (h *TodoHandler) methodOverrideMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		override := r.Header.Get("X-HTTP-Method-Override")
+		if r.Method == http.MethodPost && override != "" {
+			method := strings.ToUpper(strings.TrimSpace(override))
+			if !slices.Contains(overridableMethods, method) {
+				h.writeErrorResponse(w, http.StatusBadRequest,
+					fmt.Sprintf("X-HTTP-Method-Override must be one of %s", strings.Join(overridableMethods, ", ")))
+				return
+			}
+			r.Method = method
+			r.Header.Del("X-HTTP-Method-Override")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serverHeaderWriter removes the Server header just before the response header is sent
+type serverHeaderWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader strips the Server header before delegating
+func (sw *serverHeaderWriter) WriteHeader(status int) {
+	if !sw.wroteHeader {
+		sw.wroteHeader = true
+		sw.Header().Del("Server")
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends the header, without the Server field, on first write
+func (sw *serverHeaderWriter) Write(p []byte) (int, error) {
+	if !sw.wroteHeader {
+		sw.WriteHeader(http.StatusOK)
+	}
+	return sw.ResponseWriter.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (sw *serverHeaderWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// serverHeaderMiddleware keeps any Server header set by inner handlers out of responses
+func serverHeaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&serverHeaderWriter{ResponseWriter: w}, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header allows a gzip response
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		// An explicit q=0 means the client refuses gzip
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			return err == nil && q > 0
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response and gzips it only once it grows
+// past minBytes; smaller responses are written through unchanged when closed
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minBytes int
+	status   int
+	buf      []byte
+	gz       *gzip.Writer
+	started  bool
+}
+
+// WriteHeader defers the status until the compression decision is made
+func (gw *gzipResponseWriter) WriteHeader(status int) {
+	if gw.status == 0 {
+		gw.status = status
+	}
+}
+
+// Write buffers output until it exceeds the threshold, then streams it compressed
+func (gw *gzipResponseWriter) Write(p []byte) (int, error) {
+	if gw.started {
+		if gw.gz != nil {
+			return gw.gz.Write(p)
+		}
+		return gw.ResponseWriter.Write(p)
+	}
+
+	gw.buf = append(gw.buf, p...)
+	if len(gw.buf) > gw.minBytes {
+		if err := gw.start(gw.Header().Get("Content-Encoding") == ""); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// start sends the header and any buffered output, compressing from here on if requested
+func (gw *gzipResponseWriter) start(compress bool) error {
+	gw.started = true
+	if compress {
+		gw.Header().Set("Content-Encoding", "gzip")
+		gw.Header().Del("Content-Length")
+	}
+	if gw.status != 0 {
+		gw.ResponseWriter.WriteHeader(gw.status)
+	}
+	if compress {
+		gw.gz = gzip.NewWriter(gw.ResponseWriter)
+	}
+
+	buffered := gw.buf
+	gw.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	if gw.gz != nil {
+		_, err := gw.gz.Write(buffered)
+		return err
+	}
+	_, err := gw.ResponseWriter.Write(buffered)
+	return err
+}
+
+// Close writes a response that stayed under the threshold, or finishes the gzip stream
+func (gw *gzipResponseWriter) Close() error {
+	if !gw.started {
+		return gw.start(false)
+	}
+	if gw.gz != nil {
+		return gw.gz.Close()
+	}
+	return nil
+}
+
+// FlushError sends everything written so far, compressed, so streamed responses such as
+// server-sent events reach the client without waiting for the threshold
+func (gw *gzipResponseWriter) FlushError() error {
+	if !gw.started {
+		if err := gw.start(gw.Header().Get("Content-Encoding") == ""); err != nil {
+			return err
+		}
+	}
+	if gw.gz != nil {
+		if err := gw.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(gw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// compressionMiddleware gzips responses larger than GzipMinBytes for clients that accept it
+func (h *TodoHandler) compressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, minBytes: h.config.GzipMinBytes}
+		defer func() {
+			if err := gw.Close(); err != nil {
+				log.Printf("Failed to finish compressed response: %v", err)
+			}
+		}()
+		next.ServeHTTP(gw, r)
+	})
+}
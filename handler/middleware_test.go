@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gzipBody compresses the payload for use as a request body
+func gzipBody(t *testing.T, payload []byte) *bytes.Buffer {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(payload); err != nil {
+		t.Fatalf("Failed to gzip payload: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+	return &buf
+}
+
+func TestBodyMiddleware_GzipCreate(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	payload, _ := json.Marshal(CreateTodoRequest{Title: "Zipped", Description: "Sent compressed"})
+	req := httptest.NewRequest(http.MethodPost, "/todos", gzipBody(t, payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	
+	var todo models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if todo.Title != "Zipped" {
+		t.Errorf("Expected title Zipped, got %s", todo.Title)
+	}
+}
+
+func TestBodyMiddleware_GzipBomb(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.MaxBodyBytes = 1024
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	mux := handler.SetupRoutes()
+	
+	// Highly compressible payload that expands well past the limit
+	payload := []byte(`{"title": "Bomb", "description": "` + strings.Repeat("a", 64*1024) + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/todos", gzipBody(t, payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestBodyMiddleware_InvalidGzip(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "Plain"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBodyMiddleware_GzipDisabled(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.DecodeGzipRequests = false
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	mux := handler.SetupRoutes()
+	
+	payload, _ := json.Marshal(CreateTodoRequest{Title: "Zipped"})
+	req := httptest.NewRequest(http.MethodPost, "/todos", gzipBody(t, payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+}
+
+// captureLog redirects the standard logger for the duration of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	writer := log.Writer()
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(writer) })
+	return &buf
+}
+
+func TestLoggingMiddleware_DebugBodies(t *testing.T) {
+	tests := []struct {
+		name        string
+		debugBodies bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLog(t)
+			
+			mockService := NewMockTodoService()
+			config := DefaultHandlerConfig()
+			config.DebugBodies = tt.debugBodies
+			handler := NewTodoHandlerWithConfig(mockService, config).SetupHandler()
+			
+			req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "Secret plan"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.ServeHTTP(w, req)
+			
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), "Secret plan") {
+				t.Errorf("Expected handler to still produce the full response, got %s", w.Body.String())
+			}
+			
+			output := logs.String()
+			if !strings.Contains(output, "POST /todos 201") {
+				t.Errorf("Expected request line in logs, got %s", output)
+			}
+			
+			hasBodies := strings.Contains(output, `request body: {"title": "Secret plan"}`) &&
+				strings.Contains(output, "response body: {")
+			if hasBodies != tt.debugBodies {
+				t.Errorf("Expected bodies logged=%v, got logs: %s", tt.debugBodies, output)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddleware_TruncatesBodies(t *testing.T) {
+	logs := captureLog(t)
+	
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.DebugBodies = true
+	config.DebugBodyMaxBytes = 8
+	handler := NewTodoHandlerWithConfig(mockService, config).SetupHandler()
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{"title": "Long enough to truncate"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.ServeHTTP(w, req)
+	
+	if !strings.Contains(logs.String(), `request body: {"title"...(truncated)`) {
+		t.Errorf("Expected truncated request body in logs, got %s", logs.String())
+	}
+}
+
+func TestCompressionMiddleware_Threshold(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.CompressResponses = true
+	config.GzipMinBytes = 512
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Small"})
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		handler.SetupHandler().ServeHTTP(w, req)
+		return w
+	}
+	
+	small := get("/todos/1")
+	if small.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, small.Code)
+	}
+	if got := small.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected a small response not to be compressed, got Content-Encoding %q", got)
+	}
+	var todo models.Todo
+	if err := json.NewDecoder(small.Body).Decode(&todo); err != nil || todo.Title != "Small" {
+		t.Errorf("Expected the plain todo, got %+v (err %v)", todo, err)
+	}
+	
+	for i := 0; i < 20; i++ {
+		mockService.CreateTodo(service.CreateTodoInput{Title: "Padding", Description: strings.Repeat("x", 100)})
+	}
+	large := get("/todos")
+	if large.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, large.Code)
+	}
+	if got := large.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected a large response to be gzipped, got Content-Encoding %q", got)
+	}
+	gz, err := gzip.NewReader(large.Body)
+	if err != nil {
+		t.Fatalf("Failed to open gzip body: %v", err)
+	}
+	var todos []models.Todo
+	if err := json.NewDecoder(gz).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode gzipped response: %v", err)
+	}
+	if len(todos) != 21 {
+		t.Errorf("Expected 21 todos, got %d", len(todos))
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.8", true},
+		{"GZIP", true},
+		{"gzip;q=0", false},
+		{"br", false},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", tt.header)
+		if got := acceptsGzip(req); got != tt.expected {
+			t.Errorf("acceptsGzip(%q) = %v, expected %v", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestMethodOverrideMiddleware(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.AllowMethodOverride = true
+	handler := NewTodoHandlerWithConfig(mockService, config).SetupHandler()
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	
+	post := func(path, override, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-HTTP-Method-Override", override)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := post("/todos/1", "PUT", `{"title": "Updated", "version": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected overridden PUT to update, got %d: %s", w.Code, w.Body.String())
+	}
+	if todo, _ := mockService.GetTodoByID(1); todo == nil || todo.Title != "Updated" {
+		t.Errorf("Expected the todo to be updated, got %+v", todo)
+	}
+	
+	if w := post("/todos/1", "GET", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected a disallowed override to be rejected, got %d", w.Code)
+	}
+	
+	w = post("/todos/1", "delete", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected overridden DELETE to delete, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := mockService.GetTodoByID(1); err == nil {
+		t.Error("Expected the todo to be deleted")
+	}
+}
+
+func TestMethodOverrideMiddleware_Disabled(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService).SetupHandler()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Kept"})
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos/1", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-HTTP-Method-Override", "DELETE")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected the override to be ignored when disabled, got %d", w.Code)
+	}
+	if _, err := mockService.GetTodoByID(1); err != nil {
+		t.Error("Expected the todo to remain")
+	}
+}
+
+func TestServerHeaderMiddleware(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "go-crud-todo-list/1.0")
+		w.Write([]byte("ok"))
+	})
+	
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	serverHeaderMiddleware(inner).ServeHTTP(w, req)
+	
+	if got := w.Header().Get("Server"); got != "" {
+		t.Errorf("Expected no Server header, got %q", got)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("Expected the body to pass through, got %q", w.Body.String())
+	}
+	
+	config := DefaultHandlerConfig()
+	config.HideServerHeader = true
+	handler := NewTodoHandlerWithConfig(NewMockTodoService(), config).SetupHandler()
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Server") != "" {
+		t.Errorf("Expected a healthy response without a Server header, got %d %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"errors"
+	"go-crud-todo-list/service"
+	"net/http"
+)
+
+// nextTodo handles GET /todos/next - returns the single open todo to work on next
+func (h *TodoHandler) nextTodo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	todo, err := h.serviceFor(r).NextActionable()
+	if err != nil {
+		if errors.Is(err, service.ErrNoActionableTodo) {
+			h.writeErrorResponse(w, http.StatusNotFound, "No actionable todo")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve next todo")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todo)
+}
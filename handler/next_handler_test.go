@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNextTodo(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/todos/next", nil)
+		w := httptest.NewRecorder()
+		handler.SetupRoutes().ServeHTTP(w, req)
+		return w
+	}
+	
+	if w := get(); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d with nothing actionable, got %d", http.StatusNotFound, w.Code)
+	}
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	second, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Second"})
+	mockService.PinTodo(second.ID)
+	
+	w := get()
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var todo models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if todo.ID != second.ID {
+		t.Errorf("Expected the pinned todo %d, got %d", second.ID, todo.ID)
+	}
+}
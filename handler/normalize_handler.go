@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/service"
+	"net/http"
+	"time"
+)
+
+// NormalizedTodo holds the fields a todo would be stored with. Server-assigned fields such
+// as the ID and timestamps are only known once the todo is saved.
+type NormalizedTodo struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Pinned      bool       `json:"pinned"`
+	Source      string     `json:"source"`
+}
+
+// In returns a copy of the todo with its timestamps expressed in loc
+func (t NormalizedTodo) In(loc *time.Location) NormalizedTodo {
+	if t.DueDate != nil {
+		dueDate := t.DueDate.In(loc)
+		t.DueDate = &dueDate
+	}
+	if t.ExpiresAt != nil {
+		expiresAt := t.ExpiresAt.In(loc)
+		t.ExpiresAt = &expiresAt
+	}
+	return t
+}
+
+// NormalizeResult is the preview of one create request: the todo it would store, or the
+// error that would reject it
+type NormalizeResult struct {
+	Todo  *NormalizedTodo `json:"todo,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// NormalizeResponse represents the per-item previews of POST /todos/normalize
+type NormalizeResponse struct {
+	Results []NormalizeResult `json:"results"`
+}
+
+// normalizeTodos handles POST /todos/normalize - applies the create path's defaults,
+// trimming, tag normalization and validation to an array of create requests without
+// saving anything, so clients can preview what would be stored
+func (h *TodoHandler) normalizeTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	var reqs []CreateTodoRequest
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	if !h.checkBatchSize(w, len(reqs)) {
+		return
+	}
+
+	inputs := make([]service.CreateTodoInput, len(reqs))
+	for i, req := range reqs {
+		inputs[i] = req.toInput()
+	}
+
+	results := make([]NormalizeResult, 0, len(inputs))
+	for _, result := range h.serviceFor(r).NormalizeTodos(inputs) {
+		if result.Err != nil {
+			results = append(results, NormalizeResult{Error: result.Err.Error()})
+			continue
+		}
+		todo := result.Todo
+		results = append(results, NormalizeResult{Todo: &NormalizedTodo{
+			Title:       todo.Title,
+			Description: todo.Description,
+			DueDate:     todo.DueDate,
+			ExpiresAt:   todo.ExpiresAt,
+			Tags:        todo.Tags,
+			Pinned:      todo.Pinned,
+			Source:      todo.Source,
+		}})
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, NormalizeResponse{Results: results})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTodos(t *testing.T) {
+	// The real service runs the create path's normalization; with no repository, any
+	// attempt to save would panic
+	todoService := service.NewTodoServiceWithConfig(nil, service.ServiceConfig{
+		CreateDefaults: service.CreateDefaults{Description: "From defaults"},
+	})
+	handler := NewTodoHandler(todoService)
+	
+	body := `[
+		{"title": "  Padded title  ", "tags": ["Work", " work ", "urgent", "WORK"]},
+		{"title": "   "}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/todos/normalize", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp NormalizeResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", resp.Results)
+	}
+	
+	todo := resp.Results[0].Todo
+	if todo == nil {
+		t.Fatalf("Expected the first request to normalize, got error %q", resp.Results[0].Error)
+	}
+	if todo.Title != "Padded title" {
+		t.Errorf("Expected the title trimmed, got %q", todo.Title)
+	}
+	if !slices.Equal(todo.Tags, []string{"work", "urgent"}) {
+		t.Errorf("Expected tags lowercased and deduplicated, got %v", todo.Tags)
+	}
+	if todo.Description != "From defaults" {
+		t.Errorf("Expected the default description filled in, got %q", todo.Description)
+	}
+	
+	if resp.Results[1].Todo != nil || !strings.Contains(resp.Results[1].Error, "validation failed") {
+		t.Errorf("Expected the blank title rejected, got %+v", resp.Results[1])
+	}
+}
+
+func TestNormalizeTodos_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"not an array", http.MethodPost, `{"title": "One"}`, http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTodoHandler(NewMockTodoService())
+			
+			req := httptest.NewRequest(tt.method, "/todos/normalize", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
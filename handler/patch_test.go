@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// servePatch sends a JSON Patch document for the todo with ID 1 through the routes
+func servePatch(handler *TodoHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/todos/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	return w
+}
+
+func TestPatchTodo_Replace(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original", Description: "Keep me"})
+	
+	w := servePatch(handler, `[
+		{"op": "test", "path": "/title", "value": "Original"},
+		{"op": "replace", "path": "/title", "value": "Patched"},
+		{"op": "replace", "path": "/completed", "value": true}
+	]`)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	var todo models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if todo.Title != "Patched" || !todo.Completed || todo.Description != "Keep me" {
+		t.Errorf("Unexpected patched todo: %+v", todo)
+	}
+}
+
+func TestPatchTodo_FailingTest(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	
+	w := servePatch(handler, `[
+		{"op": "test", "path": "/title", "value": "Someone else's edit"},
+		{"op": "replace", "path": "/title", "value": "Patched"}
+	]`)
+	
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	
+	stored, _ := mockService.GetTodoByID(1)
+	if stored.Title != "Original" {
+		t.Errorf("Expected todo to be unchanged after failed test, got %q", stored.Title)
+	}
+}
+
+func TestPatchTodo_ConflictIncludesCurrent(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	mockService.UpdateTodo(1, service.UpdateTodoInput{Title: "Someone else's edit", Description: "Newer"})
+	
+	w := servePatch(handler, `[
+		{"op": "test", "path": "/title", "value": "Original"},
+		{"op": "replace", "path": "/title", "value": "Patched"}
+	]`)
+	
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	
+	var response ConflictResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Code != http.StatusConflict || response.Error == "" {
+		t.Errorf("Expected the conflict error fields, got %+v", response.ErrorResponse)
+	}
+	if response.Current == nil {
+		t.Fatal("Expected the current todo in the conflict response")
+	}
+	if response.Current.ID != 1 || response.Current.Title != "Someone else's edit" || response.Current.Description != "Newer" {
+		t.Errorf("Expected the up-to-date todo, got %+v", response.Current)
+	}
+}
+
+func TestPatchTodo_ConflictWithoutCurrent(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.ConflictIncludesCurrent = false
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	
+	w := servePatch(handler, `[{"op": "test", "path": "/title", "value": "Stale"}]`)
+	
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if strings.Contains(w.Body.String(), `"current"`) {
+		t.Errorf("Expected no current todo when disabled, got %s", w.Body.String())
+	}
+}
+
+func TestPatchTodo_TestVersion(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	mockService.todos[0].Version = 2
+	
+	w := servePatch(handler, `[
+		{"op": "test", "path": "/version", "value": 1},
+		{"op": "replace", "path": "/title", "value": "Patched"}
+	]`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d for a stale version, got %d", http.StatusConflict, w.Code)
+	}
+	
+	w = servePatch(handler, `[
+		{"op": "test", "path": "/version", "value": 2},
+		{"op": "replace", "path": "/title", "value": "Patched"}
+	]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for the current version, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+// interleavingRepository runs interleave once, right after the first GetByID, to stand in
+// for a request that writes between a read and the write that follows it
+type interleavingRepository struct {
+	repository.TodoRepository
+	interleave func()
+}
+
+func (r *interleavingRepository) GetByID(id int) (*models.Todo, error) {
+	todo, err := r.TodoRepository.GetByID(id)
+	if r.interleave != nil {
+		interleave := r.interleave
+		r.interleave = nil
+		interleave()
+	}
+	return todo, err
+}
+
+func TestPatchTodo_InterleavedUpdate(t *testing.T) {
+	repo := &interleavingRepository{TodoRepository: repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))}
+	todoService := service.NewTodoService(repo)
+	handler := NewTodoHandler(todoService)
+	if _, err := todoService.CreateTodo(service.CreateTodoInput{Title: "Original"}); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	
+	// A PUT lands after the patch has read version 1 and passed its test
+	repo.interleave = func() {
+		if _, err := todoService.UpdateTodo(1, service.UpdateTodoInput{Title: "Concurrent edit"}); err != nil {
+			t.Fatalf("Failed to update todo: %v", err)
+		}
+	}
+	w := servePatch(handler, `[
+		{"op": "test", "path": "/version", "value": 1},
+		{"op": "replace", "path": "/title", "value": "Patched"}
+	]`)
+	
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	stored, _ := todoService.GetTodoByID(1)
+	if stored.Title != "Concurrent edit" || stored.Version != 2 {
+		t.Errorf("Expected the concurrent edit to be kept, got %q at version %d", stored.Title, stored.Version)
+	}
+}
+
+func TestPatchTodo_InvalidPath(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	
+	w := servePatch(handler, `[{"op": "replace", "path": "/id", "value": 42}]`)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "unknown path") {
+		t.Errorf("Expected unknown path error, got %s", w.Body.String())
+	}
+}
+
+func TestPatchTodo_WrongContentType(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original"})
+	
+	req := httptest.NewRequest(http.MethodPatch, "/todos/1", strings.NewReader(`[]`))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+)
+
+// pinTodo handles POST /todos/{id}/pin and POST /todos/{id}/unpin
+func (h *TodoHandler) pinTodo(w http.ResponseWriter, r *http.Request, pinned bool) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	subresource := "pin"
+	if !pinned {
+		subresource = "unpin"
+	}
+	id, err := h.extractSubresourceID(r.URL.Path, subresource)
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+
+	action := h.serviceFor(r).PinTodo
+	if !pinned {
+		action = h.serviceFor(r).UnpinTodo
+	}
+	todo, err := action(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update pin")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todo)
+}
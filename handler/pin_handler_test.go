@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPinTodo(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Second"})
+	
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"pin", http.MethodPost, "/todos/2/pin", http.StatusOK},
+		{"pin unknown todo", http.MethodPost, "/todos/99/pin", http.StatusNotFound},
+		{"unpin missing ID", http.MethodPost, "/todos/unpin", http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/todos/2/pin", http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+	
+	// Pinned todos are listed first
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != 2 || !todos[0].Pinned {
+		t.Errorf("Expected pinned todo 2 first, got %+v", todos)
+	}
+	
+	// Unpinning restores the normal order
+	req = httptest.NewRequest(http.MethodPost, "/todos/2/unpin", nil)
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || mockService.todos[1].Pinned {
+		t.Errorf("Expected todo 2 to be unpinned, got status %d", w.Code)
+	}
+}
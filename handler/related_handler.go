@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// getRelatedTodos handles GET /todos/{id}/related - returns the todos sharing the most tags
+// with the given one, optionally including completed todos and capped by limit
+func (h *TodoHandler) getRelatedTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "limit", "include_completed") {
+		return
+	}
+
+	id, err := h.extractSubresourceID(r.URL.Path, "related")
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid limit: must be a positive integer")
+			return
+		}
+	}
+	includeCompleted := false
+	if value := query.Get("include_completed"); value != "" {
+		if includeCompleted, err = strconv.ParseBool(value); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid include_completed: must be true or false")
+			return
+		}
+	}
+
+	todos, err := h.serviceFor(r).RelatedTodos(id, limit, includeCompleted)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+			return
+		}
+		if strings.Contains(err.Error(), "invalid todo ID") {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve related todos")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todos)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRelatedTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Target", Tags: []string{"work", "urgent", "q3"}},
+		{ID: 2, Title: "One shared", Tags: []string{"work"}},
+		{ID: 3, Title: "Three shared", Tags: []string{"q3", "urgent", "work"}},
+		{ID: 4, Title: "Unrelated", Tags: []string{"home"}},
+		{ID: 5, Title: "Two shared", Tags: []string{"urgent", "work"}},
+		{ID: 6, Title: "Done", Tags: []string{"work", "urgent", "q3"}, Completed: true},
+	}
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	tests := []struct {
+		path     string
+		expected []int
+	}{
+		{"/todos/1/related", []int{3, 5, 2}},
+		{"/todos/1/related?limit=2", []int{3, 5}},
+		{"/todos/1/related?include_completed=true", []int{3, 6, 5, 2}},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", tt.path, http.StatusOK, w.Code)
+		}
+		
+		var todos []models.Todo
+		if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		ids := make([]int, len(todos))
+		for i, todo := range todos {
+			ids[i] = todo.ID
+		}
+		if len(ids) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.path, tt.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.path, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestGetRelatedTodos_Errors(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.todos = []models.Todo{{ID: 1, Title: "Untagged"}}
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/todos/1/related", http.StatusOK},
+		{"/todos/99/related", http.StatusNotFound},
+		{"/todos/abc/related", http.StatusBadRequest},
+		{"/todos/1/related?limit=0", http.StatusBadRequest},
+		{"/todos/1/related?include_completed=maybe", http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.expected, w.Code)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// ReopenResponse represents the response body for a bulk reopen
+type ReopenResponse struct {
+	Reopened int `json:"reopened"`
+}
+
+// reopenTodos handles POST /todos/reopen - reopens every todo completed before
+// ?completed_before=, in a single save
+func (h *TodoHandler) reopenTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "completed_before") {
+		return
+	}
+
+	before, err := parseQueryTime("completed_before", r.URL.Query().Get("completed_before"), responseLocation(w), false)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reopened, err := h.serviceFor(r).ReopenCompletedBefore(before)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to reopen todos")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, ReopenResponse{Reopened: reopened})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReopenTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	early := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Done early", Completed: true, CompletedAt: &early},
+		{ID: 2, Title: "Done late", Completed: true, CompletedAt: &late},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	tests := []struct {
+		name         string
+		method       string
+		query        string
+		wantStatus   int
+		wantReopened int
+	}{
+		{"missing date", http.MethodPost, "", http.StatusBadRequest, 0},
+		{"invalid date", http.MethodPost, "?completed_before=last-week", http.StatusBadRequest, 0},
+		{"wrong method", http.MethodGet, "?completed_before=2024-02-01", http.StatusMethodNotAllowed, 0},
+		{"completed before date", http.MethodPost, "?completed_before=2024-02-01", http.StatusOK, 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/todos/reopen"+tt.query, nil)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			
+			var resp ReopenResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Reopened != tt.wantReopened {
+				t.Errorf("Expected %d reopened, got %d", tt.wantReopened, resp.Reopened)
+			}
+		})
+	}
+	
+	if mockService.todos[0].Completed || !mockService.todos[1].Completed {
+		t.Errorf("Expected only the todo completed before the date to be reopened, got %+v", mockService.todos)
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnoozeRequest represents the request body for snoozing a todo
+type SnoozeRequest struct {
+	Duration string `json:"duration"`
+}
+
+// dayWeekSegment matches a leading day or week component such as "3d" or "1w"
+var dayWeekSegment = regexp.MustCompile(`^(\d+)([dw])`)
+
+// errDurationTooLong is returned for durations that don't fit in a time.Duration (about 292 years)
+var errDurationTooLong = errors.New("duration is too long")
+
+// parseHumanDuration parses a duration that may use d (days) and w (weeks) units in
+// addition to those accepted by time.ParseDuration, e.g. "1w2d" or "1d12h"
+func parseHumanDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("duration is required")
+	}
+
+	var total time.Duration
+	rest := value
+	for {
+		match := dayWeekSegment.FindStringSubmatch(rest)
+		if match == nil {
+			break
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		unit := 24 * time.Hour
+		if match[2] == "w" {
+			unit *= 7
+		}
+		if n > int(math.MaxInt64/unit) {
+			return 0, errDurationTooLong
+		}
+		segment := time.Duration(n) * unit
+		if total > math.MaxInt64-segment {
+			return 0, errDurationTooLong
+		}
+		total += segment
+		rest = rest[len(match[0]):]
+	}
+
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		if d > 0 && total > math.MaxInt64-d {
+			return 0, errDurationTooLong
+		}
+		total += d
+	}
+
+	if total <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return total, nil
+}
+
+// snoozeTodo handles POST /todos/{id}/snooze - defers a todo's due date
+func (h *TodoHandler) snoozeTodo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	id, err := h.extractSubresourceID(r.URL.Path, "snooze")
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+
+	var req SnoozeRequest
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	d, err := parseHumanDuration(req.Duration)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	todo, err := h.serviceFor(r).SnoozeTodo(id, d)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+			return
+		}
+		if strings.Contains(err.Error(), "validation failed") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to snooze todo")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todo)
+}
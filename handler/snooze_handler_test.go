@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHumanDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"1d", 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1w2d", 9 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"", 0, true},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"tomorrow", 0, true},
+		{"1d2x", 0, true},
+		{"40000w", 0, true},
+		{"15000w15000w", 0, true},
+		{"15249w2562047h", 0, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := parseHumanDuration(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %v", tt.input, d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if d != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestSnoozeTodo(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	dueDate := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	created, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Pay rent", DueDate: &dueDate})
+	
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"snooze by days", http.MethodPost, "/todos/1/snooze", `{"duration":"1d"}`, http.StatusOK},
+		{"invalid duration", http.MethodPost, "/todos/1/snooze", `{"duration":"soon"}`, http.StatusBadRequest},
+		{"missing duration", http.MethodPost, "/todos/1/snooze", `{}`, http.StatusBadRequest},
+		{"overflowing duration", http.MethodPost, "/todos/1/snooze", `{"duration":"40000w"}`, http.StatusBadRequest},
+		{"unknown todo", http.MethodPost, "/todos/99/snooze", `{"duration":"1w"}`, http.StatusNotFound},
+		{"invalid ID", http.MethodPost, "/todos/abc/snooze", `{"duration":"1d"}`, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/todos/1/snooze", "", http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+	
+	stored, _ := mockService.GetTodoByID(created.ID)
+	if stored.DueDate == nil || !stored.DueDate.Equal(dueDate.Add(24*time.Hour)) {
+		t.Errorf("Expected due date to move forward one day, got %v", stored.DueDate)
+	}
+	
+	var todo models.Todo
+	req := httptest.NewRequest(http.MethodPost, "/todos/1/snooze", strings.NewReader(`{"duration":"1w"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !todo.DueDate.Equal(dueDate.Add(8 * 24 * time.Hour)) {
+		t.Errorf("Expected due date %v, got %v", dueDate.Add(8*24*time.Hour), todo.DueDate)
+	}
+}
+
+func TestExtractSubresourceID(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	tests := []struct {
+		path       string
+		expectedID int
+		wantErr    error
+	}{
+		{"/todos/7/snooze", 7, nil},
+		{"/todos//snooze", 0, errMissingID},
+		{"/todos/snooze", 0, errMissingID},
+		{"/todos/abc/snooze", 0, errInvalidID},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			id, err := handler.extractSubresourceID(tt.path, "snooze")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || id != tt.expectedID {
+				t.Errorf("Expected ID %d, got %d (err %v)", tt.expectedID, id, err)
+			}
+		})
+	}
+}
+
+func TestSnoozeTodo_MissingID(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	for _, path := range []string{"/todos/snooze", "/todos//snooze"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"duration":"1d"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.snoozeTodo(w, req)
+			
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), "Missing todo ID") {
+				t.Errorf("Expected a missing ID message, got %s", w.Body.String())
+			}
+		})
+	}
+}
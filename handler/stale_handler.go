@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Stale age limits for GET /todos/stale, in days
+const (
+	DefaultStaleDays = 30
+	MaxStaleDays     = 3650
+)
+
+// staleTodos handles GET /todos/stale - lists open todos not updated within ?days=N
+// (default 30), least recently updated first
+func (h *TodoHandler) staleTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r, "days") {
+		return
+	}
+
+	days := DefaultStaleDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days <= 0 || days > MaxStaleDays {
+			h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid days: must be an integer between 1 and %d", MaxStaleDays))
+			return
+		}
+	}
+
+	todos, err := h.serviceFor(r).StaleTodos(time.Duration(days) * 24 * time.Hour)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve stale todos")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, todos)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaleTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	now := time.Now()
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Forgotten", UpdatedAt: now.AddDate(0, 0, -40)},
+		{ID: 2, Title: "Recent", UpdatedAt: now.AddDate(0, 0, -5)},
+		{ID: 3, Title: "Old but done", Completed: true, UpdatedAt: now.AddDate(0, 0, -40)},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantIDs    []int
+	}{
+		{"default 30 days", "", http.StatusOK, []int{1}},
+		{"shorter window", "?days=3", http.StatusOK, []int{1, 2}},
+		{"zero days", "?days=0", http.StatusBadRequest, nil},
+		{"not a number", "?days=month", http.StatusBadRequest, nil},
+		{"too many days", "?days=99999", http.StatusBadRequest, nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/todos/stale"+tt.query, nil)
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			
+			var todos []models.Todo
+			if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(todos) != len(tt.wantIDs) {
+				t.Fatalf("Expected todos %v, got %v", tt.wantIDs, todos)
+			}
+			for i, id := range tt.wantIDs {
+				if todos[i].ID != id {
+					t.Errorf("Expected todo %d at position %d, got %d", id, i, todos[i].ID)
+				}
+			}
+		})
+	}
+}
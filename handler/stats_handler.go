@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// tagStats handles GET /todos/stats/by-tag - returns per-tag totals and completed counts
+func (h *TodoHandler) tagStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	stats, err := h.serviceFor(r).TagStats()
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to compute tag stats")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, stats)
+}
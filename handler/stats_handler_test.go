@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTagStats(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "One", Tags: []string{"work", "urgent"}, Completed: true},
+		{ID: 2, Title: "Two", Tags: []string{"work"}},
+		{ID: 3, Title: "Three", Tags: []string{"work", "home"}, Completed: true},
+		{ID: 4, Title: "Four", Tags: []string{"urgent", "home"}},
+		{ID: 5, Title: "Untagged", Completed: true},
+	}
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/stats/by-tag", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var stats []models.TagStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	expected := []models.TagStats{
+		{Tag: "work", Total: 3, Completed: 2},
+		{Tag: "home", Total: 2, Completed: 1},
+		{Tag: "urgent", Total: 2, Completed: 1},
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, stats)
+	}
+	for i := range expected {
+		if stats[i] != expected[i] {
+			t.Errorf("Expected %+v at position %d, got %+v", expected[i], i, stats[i])
+		}
+	}
+}
+
+func TestTagStats_ServiceError(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.failGet = true
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/stats/by-tag", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// TagBatchRequest represents the request body for a bulk tag change
+type TagBatchRequest struct {
+	IDs    []int    `json:"ids"`
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// tagTodos handles POST /todos/tag - adds and removes tags across many todos at once
+func (h *TodoHandler) tagTodos(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	if !h.decodeBodyIDs(w, r) {
+		return
+	}
+
+	var req TagBatchRequest
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	if !h.checkBatchSize(w, len(req.IDs)) {
+		return
+	}
+
+	result, err := h.serviceFor(r).UpdateTagsBatch(req.IDs, req.Add, req.Remove)
+	if err != nil {
+		if strings.Contains(err.Error(), "validation failed") {
+			h.writeValidationError(w, err)
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update tags")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, result)
+}
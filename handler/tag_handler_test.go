@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTagTodos(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Second"})
+	mockService.todos[0].Tags = []string{"later"}
+	
+	body := `{"ids":[1,2,42],"add":["Urgent"],"remove":["later"]}`
+	req := httptest.NewRequest(http.MethodPost, "/todos/tag", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	
+	var result models.TagBatchResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(result.Updated) != 2 {
+		t.Errorf("Expected 2 updated todos, got %d", len(result.Updated))
+	}
+	if len(result.MissingIDs) != 1 || result.MissingIDs[0] != 42 {
+		t.Errorf("Expected missing IDs [42], got %v", result.MissingIDs)
+	}
+	for _, todo := range mockService.todos {
+		if len(todo.Tags) != 1 || todo.Tags[0] != "urgent" {
+			t.Errorf("Todo %d: expected tags [urgent], got %v", todo.ID, todo.Tags)
+		}
+	}
+}
+
+func TestTagTodos_Errors(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+	}{
+		{"no IDs", http.MethodPost, `{"add":["x"]}`, http.StatusBadRequest},
+		{"invalid JSON", http.MethodPost, `{"ids":`, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/todos/tag", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTagTodos_MaxBatchSize(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	config := DefaultHandlerConfig()
+	config.MaxBatchSize = 2
+	mux := NewTodoHandlerWithConfig(mockService, config).SetupRoutes()
+	
+	tests := []struct {
+		body     string
+		expected int
+	}{
+		{`{"ids":[1,2],"add":["first"]}`, http.StatusOK},
+		{`{"ids":[1,2,3],"add":["second"]}`, http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/todos/tag", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != tt.expected {
+			t.Errorf("Expected status %d, got %d: %s", tt.expected, w.Code, w.Body.String())
+		}
+	}
+	
+	if tags := mockService.todos[0].Tags; len(tags) != 1 || tags[0] != "first" {
+		t.Errorf("Expected the oversized batch to be rejected before any work, got tags %v", tags)
+	}
+}
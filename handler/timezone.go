@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/models"
+	"net/http"
+	"time"
+)
+
+// localizedResponseWriter carries the timezone that response timestamps are rendered in
+type localizedResponseWriter struct {
+	http.ResponseWriter
+	location *time.Location
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (lw *localizedResponseWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// parseTimezone resolves an X-Timezone header value (an IANA zone name), defaulting to UTC
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Timezone: %q is not a known IANA timezone", name)
+	}
+	return loc, nil
+}
+
+// responseLocation returns the timezone responses written to w should use
+func responseLocation(w http.ResponseWriter) *time.Location {
+	if lw, ok := w.(*localizedResponseWriter); ok {
+		return lw.location
+	}
+	return time.UTC
+}
+
+// localizeEntry converts the timestamps of an audit entry and its snapshots to loc
+func localizeEntry(entry audit.Entry, loc *time.Location) audit.Entry {
+	entry.Timestamp = entry.Timestamp.In(loc)
+	if entry.Before != nil {
+		before := entry.Before.In(loc)
+		entry.Before = &before
+	}
+	if entry.After != nil {
+		after := entry.After.In(loc)
+		entry.After = &after
+	}
+	return entry
+}
+
+// localizeResponse converts the timestamps of todo-bearing response bodies to loc,
+// returning other values unchanged
+func localizeResponse(data interface{}, loc *time.Location) interface{} {
+	switch v := data.(type) {
+	case models.Todo:
+		return v.In(loc)
+	case *models.Todo:
+		todo := v.In(loc)
+		return &todo
+	case []models.Todo:
+		todos := make([]models.Todo, len(v))
+		for i := range v {
+			todos[i] = v[i].In(loc)
+		}
+		return todos
+	case BulkUpdateResponse:
+		results := make([]models.BulkUpdateResult, len(v.Results))
+		for i, result := range v.Results {
+			if result.Todo != nil {
+				todo := result.Todo.In(loc)
+				result.Todo = &todo
+			}
+			results[i] = result
+		}
+		return BulkUpdateResponse{Results: results}
+	case *models.TagBatchResult:
+		updated := make([]models.Todo, len(v.Updated))
+		for i := range v.Updated {
+			updated[i] = v.Updated[i].In(loc)
+		}
+		return &models.TagBatchResult{Updated: updated, MissingIDs: v.MissingIDs}
+	case audit.Entry:
+		return localizeEntry(v, loc)
+	case []audit.Entry:
+		entries := make([]audit.Entry, len(v))
+		for i, entry := range v {
+			entries[i] = localizeEntry(entry, loc)
+		}
+		return entries
+	case *models.DiffResult:
+		added := make([]models.Todo, len(v.Added))
+		for i := range v.Added {
+			added[i] = v.Added[i].In(loc)
+		}
+		removed := make([]models.Todo, len(v.Removed))
+		for i := range v.Removed {
+			removed[i] = v.Removed[i].In(loc)
+		}
+		modified := make([]models.ModifiedTodo, len(v.Modified))
+		for i, todo := range v.Modified {
+			changes := make([]models.FieldChange, len(todo.Changes))
+			for j, change := range todo.Changes {
+				change.Old = localizeValue(change.Old, loc)
+				change.New = localizeValue(change.New, loc)
+				changes[j] = change
+			}
+			modified[i] = models.ModifiedTodo{ID: todo.ID, Changes: changes}
+		}
+		return &models.DiffResult{Added: added, Removed: removed, Modified: modified}
+	case NormalizeResponse:
+		results := make([]NormalizeResult, len(v.Results))
+		for i, result := range v.Results {
+			if result.Todo != nil {
+				todo := result.Todo.In(loc)
+				result.Todo = &todo
+			}
+			results[i] = result
+		}
+		return NormalizeResponse{Results: results}
+	case ConflictResponse:
+		v.Timestamp = v.Timestamp.In(loc)
+		if v.Current != nil {
+			current := v.Current.In(loc)
+			v.Current = &current
+		}
+		return v
+	case DryRunResponse:
+		changes := make([]models.FieldChange, len(v.Changes))
+		for i, change := range v.Changes {
+			change.Old = localizeValue(change.Old, loc)
+			change.New = localizeValue(change.New, loc)
+			changes[i] = change
+		}
+		return DryRunResponse{DryRun: v.DryRun, Changes: changes}
+	default:
+		return data
+	}
+}
+
+// localizeValue converts an optional timestamp held in an untyped field to loc
+func localizeValue(value interface{}, loc *time.Location) interface{} {
+	if t, ok := value.(*time.Time); ok && t != nil {
+		localized := t.In(loc)
+		return &localized
+	}
+	return value
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimezoneHeader(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	
+	mockService := NewMockTodoService()
+	mockService.todos = append(mockService.todos, models.Todo{
+		ID:        1,
+		Title:     "Localized",
+		CreatedAt: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+	})
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	req.Header.Set("X-Timezone", "America/New_York")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var raw map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&raw); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if raw["created_at"] != "2024-01-15T12:00:00-05:00" {
+		t.Errorf("Expected created_at in New York time, got %v", raw["created_at"])
+	}
+	
+	// Without the header timestamps are rendered in UTC
+	req = httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	w = httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	raw = nil
+	json.NewDecoder(w.Body).Decode(&raw)
+	if raw["created_at"] != "2024-01-15T17:00:00Z" {
+		t.Errorf("Expected created_at in UTC, got %v", raw["created_at"])
+	}
+}
+
+func TestTimezoneHeader_Invalid(t *testing.T) {
+	handler := NewTodoHandler(NewMockTodoService())
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("X-Timezone", "Mars/Olympus_Mons")
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
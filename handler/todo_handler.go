@@ -1,25 +1,138 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/models"
 	"go-crud-todo-list/service"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/sync/singleflight"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// HandlerConfig holds tunable options for the HTTP layer
+type HandlerConfig struct {
+	// MaxBodyBytes caps the size of a (decompressed) request body; zero disables the limit
+	MaxBodyBytes int64
+	// MaxImportBytes caps the request body of POST /todos/import in place of MaxBodyBytes,
+	// since imports are read one record at a time; zero disables the limit
+	MaxImportBytes int64
+	// DecodeGzipRequests enables transparent decoding of gzip-encoded request bodies
+	DecodeGzipRequests bool
+	// DebugBodies logs request and response bodies; off by default as bodies may be sensitive
+	DebugBodies bool
+	// DebugBodyMaxBytes truncates logged bodies to this many bytes
+	DebugBodyMaxBytes int
+	// StrictQuery rejects query parameters an endpoint doesn't recognize instead of ignoring them
+	StrictQuery bool
+	// CaseSensitiveSearch makes title filters match case exactly; they ignore case by default
+	CaseSensitiveSearch bool
+	// CompressResponses gzips responses for clients that send Accept-Encoding: gzip
+	CompressResponses bool
+	// GzipMinBytes is the response size a body must exceed before it is compressed
+	GzipMinBytes int
+	// AllowMethodOverride lets POST requests carry PUT, PATCH or DELETE semantics
+	// through the X-HTTP-Method-Override header
+	AllowMethodOverride bool
+	// IDEncoding selects how todo IDs appear in the API; empty or IDEncodingNone uses plain integers
+	IDEncoding string
+	// IDSalt seeds the hashid encoding so encoded IDs differ between deployments
+	IDSalt string
+	// HideServerHeader strips the Server response header so responses don't identify the software
+	HideServerHeader bool
+	// StrictIDs accepts only canonical decimal IDs in paths, rejecting signs and leading zeros
+	StrictIDs bool
+	// JSONCase selects the key style of response bodies; empty or JSONCaseSnake keeps snake_case
+	JSONCase string
+	// MaxBatchSize caps the number of items in a batch request; zero disables the limit
+	MaxBatchSize int
+	// DeepReadiness adds a storage round trip to /readyz, catching data that would fail to
+	// save or load; it encodes the whole dataset, so it is off by default
+	DeepReadiness bool
+	// CoalesceReads lets concurrent GET /todos requests with the same query string share
+	// one list computation instead of each doing the work
+	CoalesceReads bool
+	// TracerProvider records a span for every request; nil uses a no-op tracer. Wrap the
+	// service in a service.TracingService to record its calls as children of that span.
+	TracerProvider trace.TracerProvider
+	// MeterProvider records the size of every request and response body; nil uses a no-op meter
+	MeterProvider metric.MeterProvider
+	// LargeRequestBytes is a soft limit below MaxBodyBytes: larger request bodies are still
+	// accepted but logged as a warning; zero disables the warning
+	LargeRequestBytes int64
+	// ConflictIncludesCurrent adds the todo as currently stored to 409 responses for
+	// updates, so clients can merge their change instead of refetching
+	ConflictIncludesCurrent bool
+	// OverdueConflict selects how list filters combining overdue=true with completed=true are
+	// handled; empty or OverdueConflictEmpty returns no todos, OverdueConflictReject returns 400
+	OverdueConflict string
+	// ValidationDetails adds the field, limit and actual length to 400 responses for values
+	// over a length limit
+	ValidationDetails bool
+	// AuditBroker publishes recorded audit entries to GET /admin/audit/stream; nil disables
+	// the stream
+	AuditBroker *audit.Broker
+}
+
+// DefaultHandlerConfig returns the handler configuration used when none is supplied
+func DefaultHandlerConfig() HandlerConfig {
+	return HandlerConfig{
+		MaxBodyBytes:            1 << 20,
+		MaxImportBytes:          100 << 20,
+		DecodeGzipRequests:      true,
+		DebugBodyMaxBytes:       1024,
+		GzipMinBytes:            1024,
+		MaxBatchSize:            1000,
+		ConflictIncludesCurrent: true,
+		ValidationDetails:       true,
+	}
+}
+
 // TodoHandler handles HTTP requests for todo operations
 type TodoHandler struct {
 	service service.TodoService
+	config  HandlerConfig
+	// ids encodes and decodes todo IDs at the API boundary; nil exposes plain integers
+	ids *idCodec
+	// reads deduplicates concurrent identical list requests when CoalesceReads is set
+	reads singleflight.Group
+	// metrics records request and response body sizes
+	metrics requestMetrics
 }
 
 // NewTodoHandler creates a new TodoHandler with the given service
 func NewTodoHandler(service service.TodoService) *TodoHandler {
-	return &TodoHandler{
+	return NewTodoHandlerWithConfig(service, DefaultHandlerConfig())
+}
+
+// NewTodoHandlerWithConfig creates a new TodoHandler with the given service and configuration
+func NewTodoHandlerWithConfig(service service.TodoService, config HandlerConfig) *TodoHandler {
+	h := &TodoHandler{
 		service: service,
+		config:  config,
+	}
+	if config.IDEncoding == IDEncodingHashID {
+		h.ids = newIDCodec(config.IDSalt)
+	}
+	if h.config.TracerProvider == nil {
+		h.config.TracerProvider = noop.NewTracerProvider()
 	}
+	if h.config.MeterProvider == nil {
+		h.config.MeterProvider = metricnoop.NewMeterProvider()
+	}
+	h.metrics = newRequestMetrics(h.config.MeterProvider)
+	return h
 }
 
 // ErrorResponse represents an error response structure
@@ -27,19 +140,55 @@ type ErrorResponse struct {
 	Error     string    `json:"error"`
 	Code      int       `json:"code"`
 	Timestamp time.Time `json:"timestamp"`
+	// Limit details a length limit the request exceeded, when ValidationDetails is enabled
+	Limit *models.LimitError `json:"limit,omitempty"`
+}
+
+// ConflictResponse is the 409 body for an update that lost a concurrent edit
+type ConflictResponse struct {
+	ErrorResponse
+	// Current is the todo as stored now, for the client to merge its change into
+	Current *models.Todo `json:"current,omitempty"`
 }
 
 // CreateTodoRequest represents the request body for creating a todo
 type CreateTodoRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// toInput converts the request body into service create input
+func (req CreateTodoRequest) toInput() service.CreateTodoInput {
+	return service.CreateTodoInput{
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		ExpiresAt:   req.ExpiresAt,
+		Tags:        req.Tags,
+	}
 }
 
 // UpdateTodoRequest represents the request body for updating a todo
 type UpdateTodoRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// toInput converts the request body into service update input
+func (req UpdateTodoRequest) toInput() service.UpdateTodoInput {
+	return service.UpdateTodoInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Completed:   req.Completed,
+		DueDate:     req.DueDate,
+		ExpiresAt:   req.ExpiresAt,
+	}
 }
 
 // writeErrorResponse writes an error response with the specified status code and message
@@ -50,14 +199,63 @@ func (h *TodoHandler) writeErrorResponse(w http.ResponseWriter, statusCode int,
 	errorResp := ErrorResponse{
 		Error:     message,
 		Code:      statusCode,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().In(responseLocation(w)),
 	}
 	
 	json.NewEncoder(w).Encode(errorResp)
 }
 
+// writeValidationError writes a 400 for a failed validation, adding the exceeded limit's
+// field, maximum and actual length when the error carries one and details are enabled
+func (h *TodoHandler) writeValidationError(w http.ResponseWriter, err error) {
+	var limitErr *models.LimitError
+	if !h.config.ValidationDetails || !errors.As(err, &limitErr) {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error:     err.Error(),
+		Code:      http.StatusBadRequest,
+		Timestamp: time.Now().In(responseLocation(w)),
+		Limit:     limitErr,
+	})
+}
+
+// writeConflictResponse writes a 409 for the todo, including its current state when configured
+func (h *TodoHandler) writeConflictResponse(w http.ResponseWriter, r *http.Request, id int, message string) {
+	if !h.config.ConflictIncludesCurrent {
+		h.writeErrorResponse(w, http.StatusConflict, message)
+		return
+	}
+	
+	// The todo may have been deleted since the conflict; the error alone still applies
+	current, err := h.serviceFor(r).GetTodoByID(id)
+	if err != nil {
+		current = nil
+	}
+	h.writeJSONResponse(w, http.StatusConflict, ConflictResponse{
+		ErrorResponse: ErrorResponse{
+			Error:     message,
+			Code:      http.StatusConflict,
+			Timestamp: time.Now(),
+		},
+		Current: current,
+	})
+}
+
 // writeJSONResponse writes a JSON response with the specified status code and data
 func (h *TodoHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	if data != nil {
+		var err error
+		if data, err = h.presentResponse(w, data); err != nil {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to encode response")
+			return
+		}
+	}
+	
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	
@@ -66,6 +264,116 @@ func (h *TodoHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, d
 	}
 }
 
+// presentResponse applies the response timezone, ID encoding and key case to a response body
+func (h *TodoHandler) presentResponse(w http.ResponseWriter, data interface{}) (interface{}, error) {
+	data = localizeResponse(data, responseLocation(w))
+	if h.ids != nil {
+		encoded, err := h.ids.encodeResponseIDs(data)
+		if err != nil {
+			return nil, err
+		}
+		data = encoded
+	}
+	if h.camelCaseResponses() {
+		encoded, err := encodeResponseCase(data)
+		if err != nil {
+			return nil, err
+		}
+		data = encoded
+	}
+	return data, nil
+}
+
+// parseID converts a todo ID taken from the path, decoding it when an ID encoding is configured
+func (h *TodoHandler) parseID(value string) (int, error) {
+	if h.ids != nil {
+		return h.ids.decode(value)
+	}
+	if h.config.StrictIDs {
+		return parseCanonicalID(value)
+	}
+	return strconv.Atoi(value)
+}
+
+// idSyntaxError explains why a path ID is not in canonical form
+type idSyntaxError struct {
+	reason string
+}
+
+func (e *idSyntaxError) Error() string {
+	return e.reason
+}
+
+// parseCanonicalID parses a positive decimal ID, rejecting anything but its canonical
+// spelling so each todo has exactly one URL
+func parseCanonicalID(value string) (int, error) {
+	switch {
+	case value == "":
+		return 0, &idSyntaxError{reason: "ID must not be empty"}
+	case value[0] == '+' || value[0] == '-':
+		return 0, &idSyntaxError{reason: "ID must not have a sign"}
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return 0, &idSyntaxError{reason: "ID must contain only digits"}
+		}
+	}
+	if len(value) > 1 && value[0] == '0' {
+		return 0, &idSyntaxError{reason: "ID must not have leading zeros"}
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &idSyntaxError{reason: "ID is out of range"}
+	}
+	return id, nil
+}
+
+// decodeBodyIDs replaces the request body with one whose encoded todo IDs are plain
+// integers, writing a 400 response and returning false if an ID is malformed
+func (h *TodoHandler) decodeBodyIDs(w http.ResponseWriter, r *http.Request) bool {
+	if h.ids == nil {
+		return true
+	}
+	body, err := h.ids.decodeRequestIDs(r.Body)
+	if err != nil {
+		if errors.Is(err, errInvalidID) {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return false
+		}
+		h.writeDecodeError(w, err)
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
+
+// checkQueryParams rejects query parameters outside the endpoint's allow-list when strict
+// query mode is enabled, writing a 400 response and returning false in that case
+func (h *TodoHandler) checkQueryParams(w http.ResponseWriter, r *http.Request, allowed ...string) bool {
+	if !h.config.StrictQuery {
+		return true
+	}
+	
+	for param := range r.URL.Query() {
+		if !slices.Contains(allowed, param) {
+			h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown query parameter: %s", param))
+			return false
+		}
+	}
+	return true
+}
+
+// checkBatchSize rejects a batch request with more than MaxBatchSize items, writing a 400
+// response and returning false in that case
+func (h *TodoHandler) checkBatchSize(w http.ResponseWriter, size int) bool {
+	if h.config.MaxBatchSize > 0 && size > h.config.MaxBatchSize {
+		h.writeErrorResponse(w, http.StatusBadRequest,
+			fmt.Sprintf("Batch of %d items exceeds the maximum of %d", size, h.config.MaxBatchSize))
+		return false
+	}
+	return true
+}
+
 // extractIDFromPath extracts the ID parameter from the URL path
 func (h *TodoHandler) extractIDFromPath(path string) (int, error) {
 	// Expected path format: /todos/{id}
@@ -74,7 +382,7 @@ func (h *TodoHandler) extractIDFromPath(path string) (int, error) {
 		return 0, fmt.Errorf("invalid path format")
 	}
 	
-	id, err := strconv.Atoi(parts[1])
+	id, err := h.parseID(parts[1])
 	if err != nil {
 		return 0, fmt.Errorf("invalid ID format: %w", err)
 	}
@@ -82,28 +390,202 @@ func (h *TodoHandler) extractIDFromPath(path string) (int, error) {
 	return id, nil
 }
 
+// Errors returned when parsing a todo ID out of a subresource path
+var (
+	errMissingID = errors.New("missing todo ID")
+	errInvalidID = errors.New("invalid ID format")
+)
+
+// extractSubresourceID extracts the ID from a /todos/{id}/{subresource} path, distinguishing
+// an absent or empty ID segment (errMissingID) from a malformed one (errInvalidID)
+func (h *TodoHandler) extractSubresourceID(path, subresource string) (int, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) == 2 && parts[0] == "todos" && parts[1] == subresource {
+		// e.g. /todos/snooze: the ID segment is absent
+		return 0, errMissingID
+	}
+	if len(parts) != 3 || parts[0] != "todos" || parts[2] != subresource {
+		return 0, fmt.Errorf("invalid path format")
+	}
+	if parts[1] == "" {
+		return 0, errMissingID
+	}
+	
+	id, err := h.parseID(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errInvalidID, err)
+	}
+	
+	return id, nil
+}
+
+// writeIDError writes the 400 response for an ID that could not be taken from the path
+func (h *TodoHandler) writeIDError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errMissingID) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Missing todo ID in path")
+		return
+	}
+	var syntaxErr *idSyntaxError
+	if errors.As(err, &syntaxErr) {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format: "+syntaxErr.reason)
+		return
+	}
+	h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+}
+
+// APIVersion is the version reported by the API index
+const APIVersion = "1.0"
+
+// Endpoint describes a single route exposed by the API
+type Endpoint struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// APIIndex represents the discovery document served at the API root
+type APIIndex struct {
+	Version   string     `json:"version"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// apiEndpoints lists the routes registered in SetupRoutes and must be kept in sync with it
+var apiEndpoints = []Endpoint{
+	{Method: http.MethodGet, Path: "/", Description: "List available endpoints"},
+	{Method: http.MethodGet, Path: "/todos", Description: "List todos"},
+	{Method: http.MethodPost, Path: "/todos", Description: "Create a todo"},
+	{Method: http.MethodGet, Path: "/todos/{id}", Description: "Get a todo by ID"},
+	{Method: http.MethodPut, Path: "/todos/{id}", Description: "Update a todo"},
+	{Method: http.MethodPatch, Path: "/todos/{id}", Description: "Apply a JSON Patch (RFC 6902) to a todo"},
+	{Method: http.MethodDelete, Path: "/todos/{id}", Description: "Delete a todo"},
+	{Method: http.MethodPost, Path: "/todos/{id}/snooze", Description: "Defer a todo's due date"},
+	{Method: http.MethodGet, Path: "/todos/{id}/history", Description: "Get a todo's recorded change history"},
+	{Method: http.MethodGet, Path: "/todos/{id}/related", Description: "List open todos sharing the most tags with a todo"},
+	{Method: http.MethodGet, Path: "/todos/{id}/checklist", Description: "Get a todo's checklist and its completion ratio"},
+	{Method: http.MethodPost, Path: "/todos/{id}/checklist", Description: "Add an item to a todo's checklist"},
+	{Method: http.MethodPatch, Path: "/todos/{id}/checklist", Description: "Mark the checklist item at ?index= done or not done, or toggle it"},
+	{Method: http.MethodDelete, Path: "/todos/{id}/checklist", Description: "Remove the checklist item at ?index="},
+	{Method: http.MethodPost, Path: "/todos/{id}/pin", Description: "Pin a todo to the top of the list"},
+	{Method: http.MethodPost, Path: "/todos/{id}/unpin", Description: "Unpin a todo"},
+	{Method: http.MethodGet, Path: "/todos/export", Description: "Download todos matching the list filters as a JSON array"},
+	{Method: http.MethodGet, Path: "/todos/calendar.ics", Description: "Subscribe to due dates as an iCalendar feed of open todos"},
+	{Method: http.MethodPost, Path: "/todos/import", Description: "Import todos from a JSON Lines body, optionally streaming progress as server-sent events"},
+	{Method: http.MethodGet, Path: "/todos/next", Description: "Get the open todo to work on next"},
+	{Method: http.MethodGet, Path: "/todos/stale", Description: "List open todos not updated within a number of days"},
+	{Method: http.MethodGet, Path: "/todos/burndown", Description: "Count todos created and completed per day or week"},
+	{Method: http.MethodGet, Path: "/todos/stats/by-tag", Description: "Count todos and completed todos per tag"},
+	{Method: http.MethodPut, Path: "/todos/bulk", Description: "Update many todos at once with per-item version checks"},
+	{Method: http.MethodPost, Path: "/todos/tag", Description: "Add and remove tags across many todos at once"},
+	{Method: http.MethodPost, Path: "/todos/normalize", Description: "Preview how create requests would be normalized and validated, without saving"},
+	{Method: http.MethodPost, Path: "/todos/reopen", Description: "Reopen every todo completed before a date"},
+	{Method: http.MethodGet, Path: "/todos/archive", Description: "List completed todos moved to the archive file"},
+	{Method: http.MethodGet, Path: "/views", Description: "List saved filter views"},
+	{Method: http.MethodPost, Path: "/views", Description: "Save a named filter view for use with ?view="},
+	{Method: http.MethodGet, Path: "/views/{name}", Description: "Get a saved filter view"},
+	{Method: http.MethodDelete, Path: "/views/{name}", Description: "Delete a saved filter view"},
+	{Method: http.MethodGet, Path: "/healthz", Description: "Liveness check"},
+	{Method: http.MethodGet, Path: "/readyz", Description: "Readiness check"},
+	{Method: http.MethodGet, Path: "/admin/check", Description: "Run a read-only data consistency check"},
+	{Method: http.MethodPost, Path: "/admin/diff", Description: "Compare a previous data file snapshot with the current todos"},
+	{Method: http.MethodPost, Path: "/admin/purge", Description: "Permanently delete todos created or completed before a date"},
+	{Method: http.MethodGet, Path: "/admin/audit/stream", Description: "Stream audit entries as server-sent events as mutations happen"},
+}
+
 // SetupRoutes configures the HTTP routes and returns a ServeMux
 func (h *TodoHandler) SetupRoutes() *http.ServeMux {
 	mux := http.NewServeMux()
 	
 	// Apply JSON middleware to all routes
-	mux.HandleFunc("/todos", h.jsonMiddleware(h.todosHandler))
-	mux.HandleFunc("/todos/", h.jsonMiddleware(h.todoByIDHandler))
+	mux.HandleFunc("/", h.jsonMiddleware(h.indexHandler))
+	mux.HandleFunc("/todos", h.jsonMiddleware(h.bodyMiddleware(h.todosHandler)))
+	mux.HandleFunc("/todos/", h.jsonMiddleware(h.bodyMiddleware(h.todoByIDHandler)))
+	mux.HandleFunc("/todos/export", h.jsonMiddleware(h.exportTodos))
+	mux.HandleFunc("/todos/import", h.jsonMiddlewareAccepting(h.bodyMiddlewareWithLimit(h.config.MaxImportBytes, h.importTodos), "application/json", "application/x-ndjson"))
+	mux.HandleFunc("/todos/calendar.ics", h.jsonMiddleware(h.calendarFeed))
+	mux.HandleFunc("/todos/burndown", h.jsonMiddleware(h.burndown))
+	mux.HandleFunc("/todos/next", h.jsonMiddleware(h.nextTodo))
+	mux.HandleFunc("/todos/stale", h.jsonMiddleware(h.staleTodos))
+	mux.HandleFunc("/todos/stats/by-tag", h.jsonMiddleware(h.tagStats))
+	mux.HandleFunc("/todos/bulk", h.jsonMiddleware(h.bodyMiddleware(h.bulkUpdateTodos)))
+	mux.HandleFunc("/todos/tag", h.jsonMiddleware(h.bodyMiddleware(h.tagTodos)))
+	mux.HandleFunc("/todos/reopen", h.jsonMiddleware(h.reopenTodos))
+	mux.HandleFunc("/todos/normalize", h.jsonMiddleware(h.bodyMiddleware(h.normalizeTodos)))
+	mux.HandleFunc("/todos/archive", h.jsonMiddleware(h.archivedTodos))
+	mux.HandleFunc("/views", h.jsonMiddleware(h.bodyMiddleware(h.viewsHandler)))
+	mux.HandleFunc("/views/", h.jsonMiddleware(h.viewByNameHandler))
+	mux.HandleFunc("/healthz", h.jsonMiddleware(h.healthz))
+	mux.HandleFunc("/readyz", h.jsonMiddleware(h.readyz))
+	mux.HandleFunc("/admin/check", h.jsonMiddleware(h.checkConsistency))
+	mux.HandleFunc("/admin/diff", h.jsonMiddleware(h.bodyMiddleware(h.diffSnapshot)))
+	mux.HandleFunc("/admin/purge", h.jsonMiddleware(h.purgeTodos))
+	mux.HandleFunc("/admin/audit/stream", h.jsonMiddleware(h.auditStream))
 	
 	return mux
 }
 
+// SetupHandler configures the HTTP routes wrapped in the middleware applied to every request
+func (h *TodoHandler) SetupHandler() http.Handler {
+	handler := h.loggingMiddleware(h.SetupRoutes())
+	if h.config.AllowMethodOverride {
+		handler = h.methodOverrideMiddleware(handler)
+	}
+	if h.config.CompressResponses {
+		handler = h.compressionMiddleware(handler)
+	}
+	if h.config.HideServerHeader {
+		handler = serverHeaderMiddleware(handler)
+	}
+	return h.tracingMiddleware(handler)
+}
+
+// indexHandler handles GET / - returns the API discovery document
+func (h *TodoHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
+	// The root pattern matches every unregistered path, so only serve the exact root
+	if r.URL.Path != "/" {
+		h.writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+	
+	if r.Method != http.MethodGet {
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+	
+	h.writeJSONResponse(w, http.StatusOK, APIIndex{
+		Version:   APIVersion,
+		Endpoints: apiEndpoints,
+	})
+}
+
 // jsonMiddleware adds JSON content type handling to HTTP handlers
 func (h *TodoHandler) jsonMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return h.jsonMiddlewareAccepting(next, "application/json")
+}
+
+// jsonMiddlewareAccepting is jsonMiddleware for routes whose POST and PUT bodies may use any
+// of the given content types
+func (h *TodoHandler) jsonMiddlewareAccepting(next http.HandlerFunc, contentTypes ...string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set default content type for responses
 		w.Header().Set("Content-Type", "application/json")
 		
+		// Render timestamps in the client's requested timezone
+		loc, err := parseTimezone(r.Header.Get("X-Timezone"))
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w = &localizedResponseWriter{ResponseWriter: w, location: loc}
+		
 		// For POST and PUT requests, validate content type
 		if r.Method == http.MethodPost || r.Method == http.MethodPut {
 			contentType := r.Header.Get("Content-Type")
-			if !strings.Contains(contentType, "application/json") {
-				h.writeErrorResponse(w, http.StatusBadRequest, "Content-Type must be application/json")
+			if !slices.ContainsFunc(contentTypes, func(accepted string) bool { return strings.Contains(contentType, accepted) }) {
+				h.writeErrorResponse(w, http.StatusBadRequest, "Content-Type must be "+strings.Join(contentTypes, " or "))
 				return
 			}
 		}
@@ -126,11 +608,39 @@ func (h *TodoHandler) todosHandler(w http.ResponseWriter, r *http.Request) {
 
 // todoByIDHandler handles requests to /todos/{id} endpoint
 func (h *TodoHandler) todoByIDHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if strings.HasSuffix(path, "/snooze") {
+		h.snoozeTodo(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/history") {
+		h.getTodoHistory(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/related") {
+		h.getRelatedTodos(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/checklist") {
+		h.checklist(w, r)
+		return
+	}
+	if strings.HasSuffix(path, "/pin") {
+		h.pinTodo(w, r, true)
+		return
+	}
+	if strings.HasSuffix(path, "/unpin") {
+		h.pinTodo(w, r, false)
+		return
+	}
+	
 	switch r.Method {
 	case http.MethodGet:
 		h.getTodoByID(w, r)
 	case http.MethodPut:
 		h.updateTodo(w, r)
+	case http.MethodPatch:
+		h.patchTodo(w, r)
 	case http.MethodDelete:
 		h.deleteTodo(w, r)
 	default:
@@ -138,28 +648,157 @@ func (h *TodoHandler) todoByIDHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// getAllTodos handles GET /todos - returns all todos as JSON
+// listFilterParams are the query parameters parseListFilter understands
+var listFilterParams = []string{"completed", "overdue", "title_prefix", "title_suffix", "source", "q", "view"}
+
+// Policies for a list filter asking for todos that are both overdue and completed
+const (
+	// OverdueConflictEmpty treats overdue as implying open, so the combination matches nothing
+	OverdueConflictEmpty = "empty"
+	// OverdueConflictReject rejects the combination with 400
+	OverdueConflictReject = "reject"
+)
+
+// ValidateOverdueConflictPolicy checks that policy names a supported overdue conflict policy
+func ValidateOverdueConflictPolicy(policy string) error {
+	switch policy {
+	case OverdueConflictEmpty, OverdueConflictReject:
+		return nil
+	}
+	return fmt.Errorf("unsupported overdue conflict policy %q (supported: %s, %s)", policy, OverdueConflictEmpty, OverdueConflictReject)
+}
+
+// parseListFilter builds a list filter from the request query parameters. A view parameter
+// starts from that saved view's criteria, which the other parameters then override.
+func (h *TodoHandler) parseListFilter(r *http.Request) (models.ListFilter, error) {
+	var filter models.ListFilter
+	query := r.URL.Query()
+
+	if name := query.Get("view"); name != "" {
+		view, err := h.serviceFor(r).GetView(name)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return filter, fmt.Errorf("unknown view: %s", name)
+			}
+			return filter, fmt.Errorf("failed to load view: %s", name)
+		}
+		filter = view.Filter()
+	}
+
+	if value := query.Get("completed"); value != "" {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid completed filter: must be true or false")
+		}
+		filter.Completed = &completed
+	}
+
+	if value := query.Get("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid overdue filter: must be true or false")
+		}
+		// OverdueAt is left for the service to set from its clock
+		filter.Overdue = &overdue
+	}
+	// Completed todos are never overdue, so asking for both can only match nothing
+	if filter.Overdue != nil && *filter.Overdue && filter.Completed != nil && *filter.Completed &&
+		h.config.OverdueConflict == OverdueConflictReject {
+		return filter, errors.New("contradictory filters: overdue=true only matches open todos, so it cannot be combined with completed=true")
+	}
+
+	if value := query.Get("title_prefix"); value != "" {
+		filter.TitlePrefix = value
+	}
+	if value := query.Get("title_suffix"); value != "" {
+		filter.TitleSuffix = value
+	}
+	if value := strings.TrimSpace(query.Get("q")); value != "" {
+		filter.Query = value
+	}
+	filter.CaseSensitive = h.config.CaseSensitiveSearch
+
+	if value := query.Get("source"); value != "" {
+		if err := models.ValidateSource(value); err != nil {
+			return filter, fmt.Errorf("invalid source filter: %w", err)
+		}
+		filter.Source = value
+	}
+
+	return filter, nil
+}
+
+// getAllTodos handles GET /todos - returns all todos matching the query filters as JSON
 func (h *TodoHandler) getAllTodos(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.service.GetAllTodos()
+	if !h.checkQueryParams(w, r, listFilterParams...) {
+		return
+	}
+	
+	filter, err := h.parseListFilter(r)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	var page *todoPage
+	if h.config.CoalesceReads {
+		// Identical queries in flight share one result, which callers must treat as read-only
+		var shared interface{}
+		shared, err, _ = h.reads.Do(r.URL.RawQuery, func() (interface{}, error) {
+			return h.listTodoPage(r, filter)
+		})
+		page, _ = shared.(*todoPage)
+	} else {
+		page, err = h.listTodoPage(r, filter)
+	}
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve todos")
 		return
 	}
 	
+	// A search without matches is an empty list, never null or a 404, so clients can tell
+	// "no results" apart from a missing endpoint
+	todos := page.todos
+	if todos == nil {
+		todos = []models.Todo{}
+	}
+	
+	w.Header().Set("X-Total-Count", strconv.Itoa(page.total))
 	h.writeJSONResponse(w, http.StatusOK, todos)
 }
 
+// todoPage is the result of a list request: the matching todos and their count
+type todoPage struct {
+	todos []models.Todo
+	total int
+}
+
+// listTodoPage lists and counts the todos matching filter for r. Without pagination the page is
+// every match, so the total comes from the same read as the todos and always agrees with them.
+func (h *TodoHandler) listTodoPage(r *http.Request, filter models.ListFilter) (*todoPage, error) {
+	todos, err := h.serviceFor(r).ListTodos(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+	
+	return &todoPage{todos: todos, total: len(todos)}, nil
+}
+
 // getTodoByID handles GET /todos/{id} - returns a specific todo by ID
 func (h *TodoHandler) getTodoByID(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+	
 	// Extract ID from URL path
 	id, err := h.extractIDFromPath(r.URL.Path)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+		h.writeIDError(w, err)
 		return
 	}
 	
 	// Get todo from service
-	todo, err := h.service.GetTodoByID(id)
+	todo, err := h.serviceFor(r).GetTodoByID(id)
 	if err != nil {
 		// Check if it's a not found error
 		if strings.Contains(err.Error(), "not found") {
@@ -175,35 +814,73 @@ func (h *TodoHandler) getTodoByID(w http.ResponseWriter, r *http.Request) {
 
 // createTodo handles POST /todos - creates a new todo
 func (h *TodoHandler) createTodo(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+	
 	var req CreateTodoRequest
 	
 	// Parse JSON request body
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		h.writeDecodeError(w, err)
 		return
 	}
 	
 	// Create todo using service
-	todo, err := h.service.CreateTodo(req.Title, req.Description)
+	todo, err := h.serviceFor(r).CreateTodo(req.toInput())
 	if err != nil {
 		// Check if it's a validation error
 		if strings.Contains(err.Error(), "validation failed") {
-			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			h.writeValidationError(w, err)
 			return
 		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create todo")
 		return
 	}
 	
+	// Honor Prefer: return=minimal by replying with just the new ID
+	if preferReturn(r) == "minimal" {
+		w.Header().Set("Preference-Applied", "return=minimal")
+		h.writeJSONResponse(w, http.StatusCreated, CreatedIDResponse{ID: todo.ID})
+		return
+	}
+	
 	h.writeJSONResponse(w, http.StatusCreated, todo)
 }
 
+// CreatedIDResponse represents the minimal response body for a created todo
+type CreatedIDResponse struct {
+	ID int `json:"id"`
+}
+
+// preferReturn extracts the "return" preference from the Prefer header (RFC 7240),
+// defaulting to "representation" when absent or unrecognized
+func preferReturn(r *http.Request) string {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			name, value, found := strings.Cut(strings.TrimSpace(pref), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "return") {
+				continue
+			}
+			value = strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`))
+			if value == "minimal" || value == "representation" {
+				return value
+			}
+		}
+	}
+	return "representation"
+}
+
 // updateTodo handles PUT /todos/{id} - updates an existing todo
 func (h *TodoHandler) updateTodo(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r, "dry_run") {
+		return
+	}
+	
 	// Extract ID from URL path
 	id, err := h.extractIDFromPath(r.URL.Path)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+		h.writeIDError(w, err)
 		return
 	}
 	
@@ -211,12 +888,18 @@ func (h *TodoHandler) updateTodo(w http.ResponseWriter, r *http.Request) {
 	
 	// Parse JSON request body
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON format")
+		h.writeDecodeError(w, err)
+		return
+	}
+	
+	// A dry run reports the would-be changes without persisting them
+	if r.URL.Query().Get("dry_run") == "true" {
+		h.previewUpdate(w, r, id, req)
 		return
 	}
 	
 	// Update todo using service
-	todo, err := h.service.UpdateTodo(id, req.Title, req.Description, req.Completed)
+	todo, err := h.serviceFor(r).UpdateTodo(id, req.toInput())
 	if err != nil {
 		// Check error type and respond accordingly
 		if strings.Contains(err.Error(), "not found") {
@@ -224,7 +907,7 @@ func (h *TodoHandler) updateTodo(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if strings.Contains(err.Error(), "validation failed") {
-			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			h.writeValidationError(w, err)
 			return
 		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to update todo")
@@ -234,18 +917,108 @@ func (h *TodoHandler) updateTodo(w http.ResponseWriter, r *http.Request) {
 	h.writeJSONResponse(w, http.StatusOK, todo)
 }
 
+// patchTodo handles PATCH /todos/{id} - applies a JSON Patch document to a todo
+func (h *TodoHandler) patchTodo(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+	
+	// Extract ID from URL path
+	id, err := h.extractIDFromPath(r.URL.Path)
+	if err != nil {
+		h.writeIDError(w, err)
+		return
+	}
+	
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json-patch+json") {
+		h.writeErrorResponse(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json-patch+json")
+		return
+	}
+	
+	var ops []models.PatchOperation
+	
+	// Parse JSON Patch document
+	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+	
+	todo, err := h.serviceFor(r).PatchTodo(id, ops)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrPatchTestFailed), errors.Is(err, models.ErrVersionConflict):
+			h.writeConflictResponse(w, r, id, err.Error())
+		case errors.Is(err, models.ErrInvalidPatch):
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case strings.Contains(err.Error(), "not found"):
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+		case strings.Contains(err.Error(), "validation failed"):
+			h.writeValidationError(w, err)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to patch todo")
+		}
+		return
+	}
+	
+	h.writeJSONResponse(w, http.StatusOK, todo)
+}
+
+// DryRunResponse represents the result of a dry-run update
+type DryRunResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Changes []models.FieldChange `json:"changes"`
+}
+
+// previewUpdate handles PUT /todos/{id}?dry_run=true - reports the diff without persisting
+func (h *TodoHandler) previewUpdate(w http.ResponseWriter, r *http.Request, id int, req UpdateTodoRequest) {
+	changes, err := h.serviceFor(r).PreviewUpdate(id, req.toInput())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
+			return
+		}
+		if strings.Contains(err.Error(), "validation failed") {
+			h.writeValidationError(w, err)
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to preview update")
+		return
+	}
+	
+	h.writeJSONResponse(w, http.StatusOK, DryRunResponse{
+		DryRun:  true,
+		Changes: changes,
+	})
+}
+
 // deleteTodo handles DELETE /todos/{id} - deletes a todo by ID
 func (h *TodoHandler) deleteTodo(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r, "force") {
+		return
+	}
+	
 	// Extract ID from URL path
 	id, err := h.extractIDFromPath(r.URL.Path)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid ID format")
+		h.writeIDError(w, err)
 		return
 	}
 	
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		if force, err = strconv.ParseBool(value); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "Invalid force: must be true or false")
+			return
+		}
+	}
+	
 	// Delete todo using service
-	err = h.service.DeleteTodo(id)
+	err = h.serviceFor(r).DeleteTodo(id, force)
 	if err != nil {
+		if errors.Is(err, service.ErrDeleteIncomplete) {
+			h.writeErrorResponse(w, http.StatusConflict, "Todo is not completed: complete it first or delete with ?force=true")
+			return
+		}
 		// Check if it's a not found error
 		if strings.Contains(err.Error(), "not found") {
 			h.writeErrorResponse(w, http.StatusNotFound, "Todo not found")
@@ -4,10 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"go-crud-todo-list/audit"
 	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -17,6 +26,12 @@ type MockTodoService struct {
 	todos   []models.Todo
 	nextID  int
 	failGet bool
+	history []audit.Entry
+	views   []models.View
+	// archived holds todos moved out by ArchiveCompleted; nil means archiving is disabled
+	archived []models.Todo
+	// requireComplete mirrors ServiceConfig.RequireCompleteBeforeDelete
+	requireComplete bool
 }
 
 func NewMockTodoService() *MockTodoService {
@@ -33,6 +48,195 @@ func (m *MockTodoService) GetAllTodos() ([]models.Todo, error) {
 	return m.todos, nil
 }
 
+func (m *MockTodoService) ListTodos(filter models.ListFilter) ([]models.Todo, error) {
+	if m.failGet {
+		return nil, errors.New("service error")
+	}
+	if filter.Overdue != nil && filter.OverdueAt.IsZero() {
+		filter.OverdueAt = time.Now()
+	}
+	todos := make([]models.Todo, 0)
+	for i := range m.todos {
+		if filter.Matches(&m.todos[i]) {
+			todos = append(todos, m.todos[i])
+		}
+	}
+	models.SortPinnedFirst(todos)
+	return todos, nil
+}
+
+func (m *MockTodoService) CountTodos(filter models.ListFilter) (int, error) {
+	todos, err := m.ListTodos(filter)
+	if err != nil {
+		return 0, err
+	}
+	return len(todos), nil
+}
+
+func (m *MockTodoService) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	todos, err := m.ListTodos(filter)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(todos)
+}
+
+func (m *MockTodoService) VerifyStorage() error {
+	if m.failGet {
+		return errors.New("service error")
+	}
+	return nil
+}
+
+func (m *MockTodoService) DiffSnapshot(snapshot []models.Todo) (*models.DiffResult, error) {
+	result := models.DiffStorage(snapshot, m.todos)
+	return &result, nil
+}
+
+func (m *MockTodoService) ListViews() ([]models.View, error) {
+	return append([]models.View{}, m.views...), nil
+}
+
+func (m *MockTodoService) GetView(name string) (*models.View, error) {
+	for _, view := range m.views {
+		if view.Name == name {
+			return &view, nil
+		}
+	}
+	return nil, errors.New("view not found")
+}
+
+func (m *MockTodoService) CreateView(view models.View) (*models.View, error) {
+	if err := view.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if _, err := m.GetView(view.Name); err == nil {
+		return nil, fmt.Errorf("view %q already exists", view.Name)
+	}
+	m.views = append(m.views, view)
+	return &view, nil
+}
+
+func (m *MockTodoService) DeleteView(name string) error {
+	for i, view := range m.views {
+		if view.Name == name {
+			m.views = append(m.views[:i], m.views[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("view not found")
+}
+
+func (m *MockTodoService) RelatedTodos(id int, limit int, includeCompleted bool) ([]models.Todo, error) {
+	todo, err := m.GetTodoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]models.Todo, 0)
+	for _, candidate := range m.todos {
+		if includeCompleted || !candidate.Completed {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if limit <= 0 {
+		limit = service.DefaultRelatedLimit
+	}
+	return models.RankRelated(*todo, candidates, limit), nil
+}
+
+func (m *MockTodoService) TagStats() ([]models.TagStats, error) {
+	if m.failGet {
+		return nil, errors.New("service error")
+	}
+	return models.ComputeTagStats(m.todos), nil
+}
+
+func (m *MockTodoService) DeleteExpired() (int, error) {
+	return 0, nil
+}
+
+func (m *MockTodoService) ReopenCompletedBefore(before time.Time) (int, error) {
+	reopened := 0
+	for i := range m.todos {
+		if m.todos[i].CompletedAt != nil && m.todos[i].CompletedAt.Before(before) {
+			m.todos[i].Completed = false
+			m.todos[i].CompletedAt = nil
+			reopened++
+		}
+	}
+	return reopened, nil
+}
+
+func (m *MockTodoService) PurgeBefore(before time.Time, field string) (int, error) {
+	if err := models.ValidatePurgeField(field); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+	storage := models.TodoStorage{Todos: m.todos}
+	purged, err := storage.PurgeBefore(before, field)
+	if err != nil {
+		return 0, err
+	}
+	m.todos = storage.Todos
+	return len(purged), nil
+}
+
+func (m *MockTodoService) ArchiveCompleted() (int, error) {
+	if m.archived == nil {
+		return 0, nil
+	}
+	storage := models.TodoStorage{Todos: m.todos}
+	archived := storage.TakeCompletedBy(time.Now())
+	m.todos = storage.Todos
+	m.archived = append(m.archived, archived...)
+	return len(archived), nil
+}
+
+func (m *MockTodoService) ListArchived() ([]models.Todo, error) {
+	if m.archived == nil {
+		return nil, service.ErrArchiveUnavailable
+	}
+	if m.failGet {
+		return nil, errors.New("service error")
+	}
+	return m.archived, nil
+}
+
+func (m *MockTodoService) ImportTodos(todos []models.Todo) (int, error) {
+	for i, todo := range todos {
+		if _, err := m.CreateTodo(service.CreateTodoInput{Title: todo.Title, Description: todo.Description, Source: models.SourceImport}); err != nil {
+			return i, err
+		}
+	}
+	return len(todos), nil
+}
+
+func (m *MockTodoService) StaleTodos(olderThan time.Duration) ([]models.Todo, error) {
+	if m.failGet {
+		return nil, errors.New("service error")
+	}
+	cutoff := time.Now().Add(-olderThan)
+	stale := make([]models.Todo, 0)
+	for _, todo := range m.todos {
+		if !todo.Completed && todo.UpdatedAt.Before(cutoff) {
+			stale = append(stale, todo)
+		}
+	}
+	return stale, nil
+}
+
+func (m *MockTodoService) NextActionable() (*models.Todo, error) {
+	completed := false
+	todos, err := m.ListTodos(models.ListFilter{Completed: &completed})
+	if err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return nil, service.ErrNoActionableTodo
+	}
+	next := slices.MinFunc(todos, models.CompareActionable)
+	return &next, nil
+}
+
 func (m *MockTodoService) GetTodoByID(id int) (*models.Todo, error) {
 	if m.failGet {
 		return nil, errors.New("service error")
@@ -45,37 +249,54 @@ func (m *MockTodoService) GetTodoByID(id int) (*models.Todo, error) {
 	return nil, errors.New("todo not found")
 }
 
-func (m *MockTodoService) CreateTodo(title, description string) (*models.Todo, error) {
-	if strings.TrimSpace(title) == "" {
+func (m *MockTodoService) CreateTodo(input service.CreateTodoInput) (*models.Todo, error) {
+	if strings.TrimSpace(input.Title) == "" {
 		return nil, errors.New("validation failed: title is required")
 	}
-	if len(title) > 200 {
-		return nil, errors.New("validation failed: title too long")
+	if len(input.Title) > models.MaxTitleLength {
+		return nil, fmt.Errorf("validation failed: %w", models.TitleTooLongError(len(input.Title)))
 	}
 	
 	todo := models.Todo{
 		ID:          m.nextID,
-		Title:       title,
-		Description: description,
+		Title:       input.Title,
+		Description: input.Description,
 		Completed:   false,
+		DueDate:     input.DueDate,
+		Tags:        input.Tags,
+		Source:      models.SourceAPI,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		Version:     1,
 	}
 	m.nextID++
 	m.todos = append(m.todos, todo)
 	return &todo, nil
 }
 
-func (m *MockTodoService) UpdateTodo(id int, title, description string, completed bool) (*models.Todo, error) {
-	if strings.TrimSpace(title) == "" {
+func (m *MockTodoService) NormalizeTodos(inputs []service.CreateTodoInput) []service.NormalizeResult {
+	results := make([]service.NormalizeResult, len(inputs))
+	for i, input := range inputs {
+		if strings.TrimSpace(input.Title) == "" {
+			results[i].Err = errors.New("validation failed: title is required")
+			continue
+		}
+		results[i].Todo = &models.Todo{Title: strings.TrimSpace(input.Title), Description: input.Description, Tags: input.Tags}
+	}
+	return results
+}
+
+func (m *MockTodoService) UpdateTodo(id int, input service.UpdateTodoInput) (*models.Todo, error) {
+	if strings.TrimSpace(input.Title) == "" {
 		return nil, errors.New("validation failed: title is required")
 	}
 	
 	for i, todo := range m.todos {
 		if todo.ID == id {
-			m.todos[i].Title = title
-			m.todos[i].Description = description
-			m.todos[i].Completed = completed
+			m.todos[i].Title = input.Title
+			m.todos[i].Description = input.Description
+			m.todos[i].Completed = input.Completed
+			m.todos[i].DueDate = input.DueDate
 			m.todos[i].UpdatedAt = time.Now()
 			return &m.todos[i], nil
 		}
@@ -83,9 +304,165 @@ func (m *MockTodoService) UpdateTodo(id int, title, description string, complete
 	return nil, errors.New("todo not found")
 }
 
-func (m *MockTodoService) DeleteTodo(id int) error {
+func (m *MockTodoService) PreviewUpdate(id int, input service.UpdateTodoInput) ([]models.FieldChange, error) {
+	for _, todo := range m.todos {
+		if todo.ID == id {
+			proposed := todo
+			proposed.Title = input.Title
+			proposed.Description = input.Description
+			proposed.Completed = input.Completed
+			proposed.DueDate = input.DueDate
+			return models.DiffTodos(todo, proposed), nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *MockTodoService) SnoozeTodo(id int, d time.Duration) (*models.Todo, error) {
 	for i, todo := range m.todos {
 		if todo.ID == id {
+			base := time.Now()
+			if todo.DueDate != nil {
+				base = *todo.DueDate
+			}
+			dueDate := base.Add(d)
+			m.todos[i].DueDate = &dueDate
+			return &m.todos[i], nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *MockTodoService) PinTodo(id int) (*models.Todo, error) {
+	return m.setPinned(id, true)
+}
+
+func (m *MockTodoService) UnpinTodo(id int) (*models.Todo, error) {
+	return m.setPinned(id, false)
+}
+
+func (m *MockTodoService) setPinned(id int, pinned bool) (*models.Todo, error) {
+	for i := range m.todos {
+		if m.todos[i].ID == id {
+			m.todos[i].Pinned = pinned
+			return &m.todos[i], nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *MockTodoService) AddChecklistItem(id int, text string) (*models.Todo, error) {
+	if err := models.ValidateChecklistItemText(text); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return m.updateChecklist(id, -1, func(checklist []models.ChecklistItem) []models.ChecklistItem {
+		return append(checklist, models.ChecklistItem{Text: text})
+	})
+}
+
+func (m *MockTodoService) SetChecklistItemDone(id, index int, done *bool) (*models.Todo, error) {
+	return m.updateChecklist(id, index, func(checklist []models.ChecklistItem) []models.ChecklistItem {
+		if done == nil {
+			checklist[index].Done = !checklist[index].Done
+		} else {
+			checklist[index].Done = *done
+		}
+		return checklist
+	})
+}
+
+func (m *MockTodoService) RemoveChecklistItem(id, index int) (*models.Todo, error) {
+	return m.updateChecklist(id, index, func(checklist []models.ChecklistItem) []models.ChecklistItem {
+		return slices.Delete(checklist, index, index+1)
+	})
+}
+
+// updateChecklist applies change to a todo's checklist after checking index, unless it is -1
+func (m *MockTodoService) updateChecklist(id, index int, change func([]models.ChecklistItem) []models.ChecklistItem) (*models.Todo, error) {
+	for i := range m.todos {
+		if m.todos[i].ID == id {
+			if index != -1 && (index < 0 || index >= len(m.todos[i].Checklist)) {
+				return nil, service.ErrChecklistItemNotFound
+			}
+			m.todos[i].Checklist = change(m.todos[i].Checklist)
+			return &m.todos[i], nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *MockTodoService) PatchTodo(id int, ops []models.PatchOperation) (*models.Todo, error) {
+	for i, todo := range m.todos {
+		if todo.ID == id {
+			patched, err := models.ApplyJSONPatch(todo, ops)
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(patched.Title) == "" {
+				return nil, errors.New("validation failed: title is required")
+			}
+			m.todos[i] = patched
+			return &m.todos[i], nil
+		}
+	}
+	return nil, errors.New("todo not found")
+}
+
+func (m *MockTodoService) BulkUpdateTodos(items []models.BulkUpdateItem) ([]models.BulkUpdateResult, error) {
+	results := make([]models.BulkUpdateResult, 0, len(items))
+	for _, item := range items {
+		result := models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusNotFound}
+		for i := range m.todos {
+			if m.todos[i].ID != item.ID {
+				continue
+			}
+			if item.Version != 0 && item.Version != m.todos[i].Version {
+				result = models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusConflict, Version: m.todos[i].Version}
+				break
+			}
+			m.todos[i].Title = item.Title
+			m.todos[i].Description = item.Description
+			m.todos[i].Completed = item.Completed
+			m.todos[i].DueDate = item.DueDate
+			m.todos[i].Version++
+			todo := m.todos[i]
+			result = models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusUpdated, Version: todo.Version, Todo: &todo}
+			break
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (m *MockTodoService) UpdateTagsBatch(ids []int, add, remove []string) (*models.TagBatchResult, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("validation failed: at least one ID is required")
+	}
+	add, remove = models.NormalizeTags(add), models.NormalizeTags(remove)
+	result := &models.TagBatchResult{Updated: make([]models.Todo, 0), MissingIDs: make([]int, 0)}
+	for _, id := range ids {
+		found := false
+		for i := range m.todos {
+			if m.todos[i].ID == id {
+				m.todos[i].Tags = models.ApplyTagChanges(m.todos[i].Tags, add, remove)
+				result.Updated = append(result.Updated, m.todos[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTodoService) DeleteTodo(id int, force bool) error {
+	for i, todo := range m.todos {
+		if todo.ID == id {
+			if m.requireComplete && !todo.Completed && !force {
+				return service.ErrDeleteIncomplete
+			}
 			m.todos = append(m.todos[:i], m.todos[i+1:]...)
 			return nil
 		}
@@ -93,13 +470,46 @@ func (m *MockTodoService) DeleteTodo(id int) error {
 	return errors.New("todo not found")
 }
 
+func (m *MockTodoService) Burndown(from, to time.Time, bucket string) ([]models.BurndownBucket, error) {
+	todos, err := m.GetAllTodos()
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := models.ComputeBurndown(todos, from, to, bucket)
+	if err != nil {
+		return nil, errors.New("validation failed: " + err.Error())
+	}
+	return buckets, nil
+}
+
+func (m *MockTodoService) GetTodoHistory(id int) ([]audit.Entry, error) {
+	if m.history == nil {
+		return nil, service.ErrHistoryUnavailable
+	}
+	entries := make([]audit.Entry, 0)
+	for _, entry := range m.history {
+		if entry.TodoID == id {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockTodoService) CheckConsistency() (*models.ConsistencyReport, error) {
+	if m.failGet {
+		return nil, errors.New("service error")
+	}
+	storage := models.TodoStorage{Todos: m.todos, NextID: m.nextID}
+	return storage.CheckConsistency(models.DefaultMaxDescriptionLength), nil
+}
+
 func TestGetAllTodos(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
 	
 	// Add some test todos
-	mockService.CreateTodo("Test Todo 1", "Description 1")
-	mockService.CreateTodo("Test Todo 2", "Description 2")
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo 1", Description: "Description 1"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo 2", Description: "Description 2"})
 	
 	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
 	w := httptest.NewRecorder()
@@ -150,6 +560,61 @@ func TestCreateTodo(t *testing.T) {
 	}
 }
 
+func TestCreateTodo_PreferMinimal(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	body, _ := json.Marshal(CreateTodoRequest{Title: "Minimal", Description: "Only the ID comes back"})
+	req := httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=minimal")
+	w := httptest.NewRecorder()
+	
+	handler.createTodo(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if len(resp) != 1 || resp["id"] != float64(1) {
+		t.Errorf("Expected only {\"id\": 1}, got %v", resp)
+	}
+	if got := w.Header().Get("Preference-Applied"); got != "return=minimal" {
+		t.Errorf("Expected Preference-Applied return=minimal, got %q", got)
+	}
+}
+
+func TestCreateTodo_PreferRepresentation(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	body, _ := json.Marshal(CreateTodoRequest{Title: "Full", Description: "Whole todo comes back"})
+	req := httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	w := httptest.NewRecorder()
+	
+	handler.createTodo(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	
+	var todo models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if todo.Title != "Full" || todo.Description != "Whole todo comes back" {
+		t.Errorf("Expected full todo representation, got %+v", todo)
+	}
+}
+
 func TestSetupRoutes(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
@@ -161,6 +626,56 @@ func TestSetupRoutes(t *testing.T) {
 	}
 }
 
+func TestIndex(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var index APIIndex
+	if err := json.NewDecoder(w.Body).Decode(&index); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if index.Version != APIVersion {
+		t.Errorf("Expected version %s, got %s", APIVersion, index.Version)
+	}
+	
+	routes := make(map[string]bool)
+	for _, endpoint := range index.Endpoints {
+		routes[endpoint.Method+" "+endpoint.Path] = true
+	}
+	
+	for _, route := range []string{"GET /todos", "POST /todos", "GET /todos/{id}", "PUT /todos/{id}", "DELETE /todos/{id}"} {
+		if !routes[route] {
+			t.Errorf("Expected index to list %s", route)
+		}
+	}
+}
+
+func TestIndex_UnknownPath(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
 func TestGetAllTodos_ServiceError(t *testing.T) {
 	mockService := NewMockTodoService()
 	mockService.failGet = true
@@ -176,12 +691,372 @@ func TestGetAllTodos_ServiceError(t *testing.T) {
 	}
 }
 
+// slowListService counts ListTodos calls and holds each one long enough for requests to overlap
+type slowListService struct {
+	*MockTodoService
+	calls atomic.Int32
+}
+
+func (s *slowListService) ListTodos(filter models.ListFilter) ([]models.Todo, error) {
+	s.calls.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	return s.MockTodoService.ListTodos(filter)
+}
+
+func TestGetAllTodos_CoalesceReads(t *testing.T) {
+	for _, coalesce := range []bool{true, false} {
+		mockService := &slowListService{MockTodoService: NewMockTodoService()}
+		mockService.CreateTodo(service.CreateTodoInput{Title: "Shared"})
+		config := DefaultHandlerConfig()
+		config.CoalesceReads = coalesce
+		handler := NewTodoHandlerWithConfig(mockService, config)
+		
+		const requests = 50
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				req := httptest.NewRequest(http.MethodGet, "/todos?completed=false", nil)
+				w := httptest.NewRecorder()
+				handler.getAllTodos(w, req)
+				if w.Code != http.StatusOK || w.Header().Get("X-Total-Count") != "1" {
+					t.Errorf("Expected status %d with one todo, got %d (count %q)", http.StatusOK, w.Code, w.Header().Get("X-Total-Count"))
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		
+		calls := int(mockService.calls.Load())
+		if coalesce && calls > requests/5 {
+			t.Errorf("Expected concurrent identical requests to share work, got %d ListTodos calls for %d requests", calls, requests)
+		}
+		if !coalesce && calls != requests {
+			t.Errorf("Expected one ListTodos call per request without coalescing, got %d", calls)
+		}
+	}
+}
+
+func TestGetAllTodos_CompletedFilter(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Open Todo"})
+	done, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Done Todo"})
+	mockService.UpdateTodo(done.ID, service.UpdateTodoInput{Title: done.Title, Completed: true})
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos?completed=true", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getAllTodos(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if len(todos) != 1 || todos[0].Title != "Done Todo" {
+		t.Errorf("Expected only the completed todo, got %+v", todos)
+	}
+	
+	if got := w.Header().Get("X-Total-Count"); got != "1" {
+		t.Errorf("Expected X-Total-Count 1, got %q", got)
+	}
+}
+
+// nilListService returns a nil slice for every list, as a repository with no matches may
+type nilListService struct {
+	*MockTodoService
+}
+
+func (nilListService) ListTodos(filter models.ListFilter) ([]models.Todo, error) {
+	return nil, nil
+}
+
+func TestGetAllTodos_NoMatches(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Quarterly report"})
+	
+	tests := []struct {
+		name    string
+		service service.TodoService
+	}{
+		{"no todo matches", mockService},
+		{"service returns nil", nilListService{mockService}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTodoHandler(tt.service)
+			
+			req := httptest.NewRequest(http.MethodGet, "/todos?q=nothing-matches-this", nil)
+			w := httptest.NewRecorder()
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d for a search without matches, got %d", http.StatusOK, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != "[]" {
+				t.Errorf("Expected an empty array, got %s", got)
+			}
+			if got := w.Header().Get("X-Total-Count"); got != "0" {
+				t.Errorf("Expected X-Total-Count 0, got %q", got)
+			}
+		})
+	}
+}
+
+// shiftingCountService counts one more todo than it lists, as if a todo were created
+// between a list and a separate count
+type shiftingCountService struct {
+	*MockTodoService
+}
+
+func (s shiftingCountService) CountTodos(filter models.ListFilter) (int, error) {
+	count, err := s.MockTodoService.CountTodos(filter)
+	return count + 1, err
+}
+
+func TestGetAllTodos_TotalCountMatchesBody(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "First"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Second"})
+	handler := NewTodoHandler(shiftingCountService{mockService})
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	w := httptest.NewRecorder()
+	handler.SetupRoutes().ServeHTTP(w, req)
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if got := w.Header().Get("X-Total-Count"); got != strconv.Itoa(len(todos)) {
+		t.Errorf("Expected X-Total-Count to match the %d todos listed, got %q", len(todos), got)
+	}
+}
+
+func TestGetAllTodos_OverdueFilter(t *testing.T) {
+	past := time.Now().Add(-24 * time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	
+	tests := []struct {
+		name           string
+		policy         string
+		query          string
+		expectedStatus int
+		expectedTitles []string
+	}{
+		{"overdue", OverdueConflictEmpty, "overdue=true", http.StatusOK, []string{"Late"}},
+		{"not overdue", OverdueConflictEmpty, "overdue=false", http.StatusOK, []string{"Upcoming", "Done late", "Undated"}},
+		{"overdue and open", OverdueConflictEmpty, "overdue=true&completed=false", http.StatusOK, []string{"Late"}},
+		{"contradiction matches nothing", OverdueConflictEmpty, "overdue=true&completed=true", http.StatusOK, []string{}},
+		{"contradiction rejected", OverdueConflictReject, "overdue=true&completed=true", http.StatusBadRequest, nil},
+		{"no contradiction under reject", OverdueConflictReject, "overdue=false&completed=true", http.StatusOK, []string{"Done late"}},
+		{"invalid overdue", OverdueConflictEmpty, "overdue=soon", http.StatusBadRequest, nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			mockService.todos = []models.Todo{
+				{ID: 1, Title: "Late", DueDate: &past},
+				{ID: 2, Title: "Upcoming", DueDate: &future},
+				{ID: 3, Title: "Done late", DueDate: &past, Completed: true},
+				{ID: 4, Title: "Undated"},
+			}
+			config := DefaultHandlerConfig()
+			config.OverdueConflict = tt.policy
+			handler := NewTodoHandlerWithConfig(mockService, config)
+			
+			req := httptest.NewRequest(http.MethodGet, "/todos?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.getAllTodos(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedTitles == nil {
+				return
+			}
+			var todos []models.Todo
+			if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			titles := make([]string, 0, len(todos))
+			for _, todo := range todos {
+				titles = append(titles, todo.Title)
+			}
+			if !slices.Equal(titles, tt.expectedTitles) {
+				t.Errorf("Expected %v, got %v", tt.expectedTitles, titles)
+			}
+		})
+	}
+}
+
+func TestGetAllTodos_InvalidCompletedFilter(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos?completed=maybe", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getAllTodos(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetAllTodos_SourceFilter(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Manual"})
+	mockService.todos = append(mockService.todos, models.Todo{ID: 2, Title: "Imported", Source: models.SourceImport})
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos?source=import", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getAllTodos(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 1 || todos[0].Title != "Imported" {
+		t.Errorf("Expected only the imported todo, got %+v", todos)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/todos?source=fax", nil)
+	w = httptest.NewRecorder()
+	
+	handler.getAllTodos(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown source, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGetAllTodos_SearchWithStatus(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	done, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Write report"})
+	mockService.UpdateTodo(done.ID, service.UpdateTodoInput{Title: done.Title, Completed: true})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Email team", Description: "Attach the report"})
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Book flights"})
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos?q=REPORT&completed=false", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getAllTodos(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 1 || todos[0].Title != "Email team" {
+		t.Errorf("Expected only the open matching todo, got %+v", todos)
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "1" {
+		t.Errorf("Expected X-Total-Count 1, got %q", got)
+	}
+}
+
+func TestGetAllTodos_CaseSensitiveSearch(t *testing.T) {
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		url           string
+		expected      int
+	}{
+		{"insensitive prefix", false, "/todos?title_prefix=buy", 2},
+		{"insensitive suffix", false, "/todos?title_suffix=MILK", 2},
+		{"sensitive prefix", true, "/todos?title_prefix=buy", 1},
+		{"sensitive suffix", true, "/todos?title_suffix=MILK", 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			mockService.CreateTodo(service.CreateTodoInput{Title: "Buy milk"})
+			mockService.CreateTodo(service.CreateTodoInput{Title: "buy MILK"})
+			config := DefaultHandlerConfig()
+			config.CaseSensitiveSearch = tt.caseSensitive
+			handler := NewTodoHandlerWithConfig(mockService, config)
+			
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			
+			handler.getAllTodos(w, req)
+			
+			var todos []models.Todo
+			if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(todos) != tt.expected {
+				t.Errorf("Expected %d todos, got %d", tt.expected, len(todos))
+			}
+		})
+	}
+}
+
+func TestStrictQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         bool
+		url            string
+		expectedStatus int
+	}{
+		{"strict rejects typo", true, "/todos?complteed=true", http.StatusBadRequest},
+		{"strict allows known params", true, "/todos?completed=true&title_prefix=a", http.StatusOK},
+		{"lenient ignores typo", false, "/todos?complteed=true", http.StatusOK},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := NewMockTodoService()
+			config := DefaultHandlerConfig()
+			config.StrictQuery = tt.strict
+			handler := NewTodoHandlerWithConfig(mockService, config)
+			
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			
+			handler.SetupRoutes().ServeHTTP(w, req)
+			
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus == http.StatusBadRequest && !strings.Contains(w.Body.String(), "complteed") {
+				t.Errorf("Expected error to name the unknown parameter, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
 func TestGetTodoByID(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
 	
 	// Create a test todo
-	createdTodo, _ := mockService.CreateTodo("Test Todo", "Test Description")
+	createdTodo, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo", Description: "Test Description"})
 	
 	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
 	w := httptest.NewRecorder()
@@ -230,6 +1105,88 @@ func TestGetTodoByID_InvalidID(t *testing.T) {
 	}
 }
 
+func TestGetTodoByID_StrictIDs(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo"})
+	config := DefaultHandlerConfig()
+	config.StrictIDs = true
+	handler := NewTodoHandlerWithConfig(mockService, config)
+	
+	tests := []struct {
+		path    string
+		message string
+	}{
+		{"/todos/+5", "Invalid ID format: ID must not have a sign"},
+		{"/todos/05", "Invalid ID format: ID must not have leading zeros"},
+		{"/todos/5%20", "Invalid ID format: ID must contain only digits"},
+		{"/todos/5.0", "Invalid ID format: ID must contain only digits"},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		
+		handler.getTodoByID(w, req)
+		
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.path, http.StatusBadRequest, w.Code)
+			continue
+		}
+		
+		var response ErrorResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Error != tt.message {
+			t.Errorf("%s: expected error %q, got %q", tt.path, tt.message, response.Error)
+		}
+	}
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getTodoByID(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected canonical ID to be accepted, got status %d", w.Code)
+	}
+}
+
+func TestStrictIDs_Subresource(t *testing.T) {
+	mockService := NewMockTodoService()
+	config := DefaultHandlerConfig()
+	config.StrictIDs = true
+	mux := NewTodoHandlerWithConfig(mockService, config).SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodPost, "/todos/+1/pin", nil)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "ID must not have a sign") {
+		t.Errorf("Expected reason in error, got %s", w.Body.String())
+	}
+}
+
+func TestGetTodoByID_LenientIDs(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo"})
+	handler := NewTodoHandler(mockService)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/+1", nil)
+	w := httptest.NewRecorder()
+	
+	handler.getTodoByID(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d without StrictIDs, got %d", http.StatusOK, w.Code)
+	}
+}
+
 func TestCreateTodo_InvalidJSON(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
@@ -266,12 +1223,52 @@ func TestCreateTodo_ValidationError(t *testing.T) {
 	}
 }
 
+func TestCreateTodo_LimitDetails(t *testing.T) {
+	testCases := []struct {
+		name          string
+		details       bool
+		expectedLimit *models.LimitError
+	}{
+		{name: "details enabled", details: true, expectedLimit: &models.LimitError{Field: "title", Unit: "characters", Max: 200, Actual: 250}},
+		{name: "details disabled", details: false},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultHandlerConfig()
+			config.ValidationDetails = tc.details
+			handler := NewTodoHandlerWithConfig(NewMockTodoService(), config)
+			
+			body, _ := json.Marshal(CreateTodoRequest{Title: strings.Repeat("a", 250)})
+			req := httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			
+			handler.createTodo(w, req)
+			
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !strings.Contains(resp.Error, "title must be 200 characters or less") {
+				t.Errorf("Expected the limit in the message, got %q", resp.Error)
+			}
+			if !reflect.DeepEqual(resp.Limit, tc.expectedLimit) {
+				t.Errorf("Expected limit %+v, got %+v", tc.expectedLimit, resp.Limit)
+			}
+		})
+	}
+}
+
 func TestUpdateTodo(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
 	
 	// Create a todo first
-	mockService.CreateTodo("Original Title", "Original Description")
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Original Title", Description: "Original Description"})
 	
 	reqBody := UpdateTodoRequest{
 		Title:       "Updated Title",
@@ -303,6 +1300,42 @@ func TestUpdateTodo(t *testing.T) {
 	}
 }
 
+func TestUpdateTodo_DryRun(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	
+	created, _ := mockService.CreateTodo(service.CreateTodoInput{Title: "Original", Description: "Unchanged"})
+	
+	body, _ := json.Marshal(UpdateTodoRequest{Title: "Edited", Description: "Unchanged", Completed: true})
+	req := httptest.NewRequest(http.MethodPut, "/todos/1?dry_run=true", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	handler.updateTodo(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	
+	var resp DryRunResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	
+	if !resp.DryRun || len(resp.Changes) != 2 {
+		t.Fatalf("Expected 2 changes in dry run, got %+v", resp)
+	}
+	if resp.Changes[0].Field != "title" || resp.Changes[1].Field != "completed" {
+		t.Errorf("Expected title and completed to change, got %+v", resp.Changes)
+	}
+	
+	// Nothing should have been persisted
+	stored, _ := mockService.GetTodoByID(created.ID)
+	if stored.Title != "Original" || stored.Completed {
+		t.Errorf("Expected dry run not to persist, got %+v", stored)
+	}
+}
+
 func TestUpdateTodo_NotFound(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
@@ -330,7 +1363,7 @@ func TestDeleteTodo(t *testing.T) {
 	handler := NewTodoHandler(mockService)
 	
 	// Create a todo first
-	mockService.CreateTodo("Test Todo", "Test Description")
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Test Todo", Description: "Test Description"})
 	
 	req := httptest.NewRequest(http.MethodDelete, "/todos/1", nil)
 	w := httptest.NewRecorder()
@@ -361,6 +1394,44 @@ func TestDeleteTodo_NotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteTodo_RequireComplete(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.requireComplete = true
+	handler := NewTodoHandler(mockService)
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Open Todo"})
+	
+	req := httptest.NewRequest(http.MethodDelete, "/todos/1", nil)
+	w := httptest.NewRecorder()
+	handler.deleteTodo(w, req)
+	
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d for an open todo, got %d", http.StatusConflict, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "force=true") {
+		t.Errorf("Expected the conflict to mention force=true, got %s", w.Body.String())
+	}
+	if len(mockService.todos) != 1 {
+		t.Fatalf("Expected the open todo to be kept, got %d todos", len(mockService.todos))
+	}
+	
+	req = httptest.NewRequest(http.MethodDelete, "/todos/1?force=yes-please", nil)
+	w = httptest.NewRecorder()
+	handler.deleteTodo(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid force value, got %d", http.StatusBadRequest, w.Code)
+	}
+	
+	req = httptest.NewRequest(http.MethodDelete, "/todos/1?force=true", nil)
+	w = httptest.NewRecorder()
+	handler.deleteTodo(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d for a forced delete, got %d", http.StatusNoContent, w.Code)
+	}
+	if len(mockService.todos) != 0 {
+		t.Errorf("Expected the todo to be deleted, got %d todos", len(mockService.todos))
+	}
+}
+
 func TestJSONMiddleware(t *testing.T) {
 	mockService := NewMockTodoService()
 	handler := NewTodoHandler(mockService)
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"go-crud-todo-list/service"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// tracerName identifies the instrumentation that creates the request spans
+const tracerName = "go-crud-todo-list/handler"
+
+// tracingMiddleware records a server span for each request. A W3C traceparent header
+// continues the caller's trace, and the span is named after the matched route so IDs in
+// paths don't make every name unique. Handlers receive the span in the request context.
+func (h *TodoHandler) tracingMiddleware(next http.Handler) http.Handler {
+	tracer := h.config.TracerProvider.Tracer(tracerName)
+	propagator := propagation.TraceContext{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			))
+		defer span.End()
+		if id := r.Header.Get("X-Request-ID"); id != "" {
+			span.SetAttributes(attribute.String("http.request.id", id))
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(recorder, r)
+
+		// The mux records the pattern it matched on the request it was given
+		if r.Pattern != "" {
+			span.SetName(r.Method + " " + r.Pattern)
+			span.SetAttributes(attribute.String("http.route", r.Pattern))
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// contextService is implemented by services that can attribute their work to a request,
+// such as service.TracingService
+type contextService interface {
+	WithContext(ctx context.Context) service.TodoService
+}
+
+// serviceFor returns the service to handle r with, bound to the request context when the
+// service supports it so its spans are children of the request span
+func (h *TodoHandler) serviceFor(r *http.Request) service.TodoService {
+	if s, ok := h.service.(contextService); ok {
+		return s.WithContext(r.Context())
+	}
+	return h.service
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"go-crud-todo-list/service"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	mockService := NewMockTodoService()
+	mockService.CreateTodo(service.CreateTodoInput{Title: "Traced"})
+	config := DefaultHandlerConfig()
+	config.TracerProvider = provider
+	handler := NewTodoHandlerWithConfig(service.NewTracingService(mockService, provider), config)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	
+	handler.SetupHandler().ServeHTTP(w, req)
+	
+	// The service call's span ends first, inside the request span
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a request span and a service span, got %d spans", len(spans))
+	}
+	child, span := spans[0], spans[1]
+	if child.Name != "TodoService.GetTodoByID" {
+		t.Errorf("Expected a span for the service call, got %q", child.Name)
+	}
+	if child.Parent.SpanID() != span.SpanContext.SpanID() || child.SpanContext.TraceID() != span.SpanContext.TraceID() {
+		t.Errorf("Expected the service span to be a child of the request span")
+	}
+	if span.Name != "GET /todos/" {
+		t.Errorf("Expected span named after the route, got %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("Expected a server span, got %v", span.SpanKind)
+	}
+	if got := span.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the trace from traceparent to continue, got trace %s", got)
+	}
+	if got := span.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Expected the caller's span as parent, got %s", got)
+	}
+	
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	expected := map[string]string{
+		"http.request.method":       "GET",
+		"url.path":                  "/todos/1",
+		"http.route":                "/todos/",
+		"http.request.id":           "req-42",
+		"http.response.status_code": "200",
+	}
+	for key, want := range expected {
+		if attrs[key] != want {
+			t.Errorf("Expected attribute %s = %q, got %q", key, want, attrs[key])
+		}
+	}
+	if span.Status.Code == codes.Error {
+		t.Errorf("Expected a successful span, got status %v", span.Status)
+	}
+}
+
+func TestTracingMiddleware_ServerError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	mockService := NewMockTodoService()
+	mockService.failGet = true
+	config := DefaultHandlerConfig()
+	config.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	handler := NewTodoHandlerWithConfig(service.NewTracingService(mockService, config.TracerProvider), config)
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	w := httptest.NewRecorder()
+	
+	handler.SetupHandler().ServeHTTP(w, req)
+	
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Status.Code != codes.Error || spans[1].Status.Code != codes.Error {
+		t.Fatalf("Expected a failed service span and request span, got %+v", spans)
+	}
+	if spans[0].Name != "TodoService.ListTodos" {
+		t.Errorf("Expected a span for the failed service call, got %q", spans[0].Name)
+	}
+	if spans[1].Name != "GET /todos" {
+		t.Errorf("Expected span named after the route, got %q", spans[1].Name)
+	}
+}
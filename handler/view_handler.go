@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"strings"
+)
+
+// viewsHandler handles requests to the /views endpoint
+func (h *TodoHandler) viewsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listViews(w, r)
+	case http.MethodPost:
+		h.createView(w, r)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// viewByNameHandler handles requests to the /views/{name} endpoint
+func (h *TodoHandler) viewByNameHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getView(w, r)
+	case http.MethodDelete:
+		h.deleteView(w, r)
+	default:
+		h.writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// extractViewName extracts the view name from a /views/{name} path
+func extractViewName(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, "/views/"), "/")
+}
+
+// listViews handles GET /views - returns the saved views
+func (h *TodoHandler) listViews(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	views, err := h.serviceFor(r).ListViews()
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve views")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, views)
+}
+
+// createView handles POST /views - saves a named filter combination
+func (h *TodoHandler) createView(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	var view models.View
+
+	// Parse JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+		h.writeDecodeError(w, err)
+		return
+	}
+
+	created, err := h.serviceFor(r).CreateView(view)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "validation failed"):
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case strings.Contains(err.Error(), "already exists"):
+			h.writeErrorResponse(w, http.StatusConflict, "View already exists")
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create view")
+		}
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusCreated, created)
+}
+
+// getView handles GET /views/{name} - returns a saved view
+func (h *TodoHandler) getView(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	view, err := h.serviceFor(r).GetView(extractViewName(r.URL.Path))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve view")
+		return
+	}
+
+	h.writeJSONResponse(w, http.StatusOK, view)
+}
+
+// deleteView handles DELETE /views/{name} - removes a saved view
+func (h *TodoHandler) deleteView(w http.ResponseWriter, r *http.Request) {
+	if !h.checkQueryParams(w, r) {
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteView(extractViewName(r.URL.Path)); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			h.writeErrorResponse(w, http.StatusNotFound, "View not found")
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete view")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"go-crud-todo-list/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestViews_CreateAndApply(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.todos = []models.Todo{
+		{ID: 1, Title: "Sprint: fix login"},
+		{ID: 2, Title: "Sprint: write docs", Completed: true},
+		{ID: 3, Title: "Backlog: refactor"},
+	}
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	body := `{"name":"sprint","completed":false,"title_prefix":"Sprint:"}`
+	req := httptest.NewRequest(http.MethodPost, "/views", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/todos?view=sprint", nil)
+	w = httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	var todos []models.Todo
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 1 {
+		t.Errorf("Expected only todo 1 to match the view, got %+v", todos)
+	}
+	
+	// Explicit parameters override the view's criteria
+	req = httptest.NewRequest(http.MethodGet, "/todos?view=sprint&completed=true", nil)
+	w = httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	todos = nil
+	if err := json.NewDecoder(w.Body).Decode(&todos); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != 2 {
+		t.Errorf("Expected completed=true to override the view, got %+v", todos)
+	}
+}
+
+func TestViews_UnknownView(t *testing.T) {
+	mockService := NewMockTodoService()
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodGet, "/todos?view=missing", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestViews_CreateErrors(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.views = []models.View{{Name: "sprint"}}
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	tests := []struct {
+		body     string
+		expected int
+	}{
+		{`{"name":"sprint"}`, http.StatusConflict},
+		{`{"name":"has space"}`, http.StatusBadRequest},
+		{`{"name":"imports","source":"email"}`, http.StatusBadRequest},
+	}
+	
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/views", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		
+		mux.ServeHTTP(w, req)
+		
+		if w.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.body, tt.expected, w.Code)
+		}
+	}
+}
+
+func TestViews_GetAndDelete(t *testing.T) {
+	mockService := NewMockTodoService()
+	mockService.views = []models.View{{Name: "sprint", TitlePrefix: "Sprint:"}}
+	handler := NewTodoHandler(mockService)
+	mux := handler.SetupRoutes()
+	
+	req := httptest.NewRequest(http.MethodGet, "/views/sprint", nil)
+	w := httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	var view models.View
+	if err := json.NewDecoder(w.Body).Decode(&view); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if view.TitlePrefix != "Sprint:" {
+		t.Errorf("Expected the saved view, got %+v", view)
+	}
+	
+	req = httptest.NewRequest(http.MethodDelete, "/views/sprint", nil)
+	w = httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/views/sprint", nil)
+	w = httptest.NewRecorder()
+	
+	mux.ServeHTTP(w, req)
+	
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after deletion, got %d", http.StatusNotFound, w.Code)
+	}
+}
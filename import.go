@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/service"
+	"os"
+)
+
+// readImportFile decodes the todos in an import file, which may be either a JSON array
+// (as produced by GET /todos/export) or a data file with a top-level "todos" field
+func readImportFile(path string) ([]models.Todo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	var todos []models.Todo
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &todos)
+	} else {
+		var storage models.TodoStorage
+		err = json.Unmarshal(data, &storage)
+		todos = storage.Todos
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse import file: %w", err)
+	}
+	return todos, nil
+}
+
+// importTodos creates a new todo for every entry in the import file through the service
+// layer, so validation and auditing apply and IDs are freshly assigned, saving them once.
+// It returns how many todos were imported before any error.
+func importTodos(todoService service.TodoService, path string) (int, error) {
+	todos, err := readImportFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	imported, err := todoService.ImportTodos(todos)
+	if err != nil {
+		return imported, fmt.Errorf("todo %d: %w", imported, err)
+	}
+	return imported, nil
+}
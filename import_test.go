@@ -0,0 +1,70 @@
+package main
+
+import (
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunApplication_ImportMode(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "todos.json")
+	importFile := filepath.Join(dir, "import.json")
+	
+	data := `[
+  {"id": 40, "title": "Imported open", "description": "From a batch job", "tags": ["batch"]},
+  {"id": 41, "title": "Imported done", "completed": true, "pinned": true}
+]`
+	if err := os.WriteFile(importFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write import file: %v", err)
+	}
+	
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", dataFile)
+	t.Setenv("IMPORT_FILE", importFile)
+	
+	// Import mode returns instead of serving, so this completes without a server
+	if err := runApplication(); err != nil {
+		t.Fatalf("Expected import to succeed, got %v", err)
+	}
+	
+	repo := repository.NewFileBasedTodoRepository(dataFile)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load data file: %v", err)
+	}
+	todos, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("Failed to read todos: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 imported todos, got %d", len(todos))
+	}
+	
+	open, done := todos[0], todos[1]
+	if open.ID != 1 || open.Title != "Imported open" || open.Source != models.SourceImport || len(open.Tags) != 1 || open.Tags[0] != "batch" {
+		t.Errorf("Unexpected first todo: %+v", open)
+	}
+	if done.ID != 2 || !done.Completed || !done.Pinned || done.Source != models.SourceImport {
+		t.Errorf("Unexpected second todo: %+v", done)
+	}
+}
+
+func TestRunApplication_ImportModeFailure(t *testing.T) {
+	dir := t.TempDir()
+	importFile := filepath.Join(dir, "import.json")
+	if err := os.WriteFile(importFile, []byte(`{"todos": [{"title": "Valid"}, {"title": ""}]}`), 0644); err != nil {
+		t.Fatalf("Failed to write import file: %v", err)
+	}
+	
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(dir, "todos.json"))
+	t.Setenv("IMPORT_FILE", importFile)
+	
+	err := runApplication()
+	if err == nil || !strings.Contains(err.Error(), "after 1 todos") {
+		t.Errorf("Expected import error after the first todo, got %v", err)
+	}
+}
@@ -3,13 +3,22 @@ package main
 import (
 	"context"
 	"fmt"
+	"go-crud-todo-list/audit"
 	"go-crud-todo-list/handler"
+	"go-crud-todo-list/models"
 	"go-crud-todo-list/repository"
 	"go-crud-todo-list/service"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -23,96 +32,650 @@ func main() {
 
 // runApplication initializes and runs the todo application
 func runApplication() error {
-	fmt.Println("Go CRUD Todo List API")
-	log.Println("Starting application initialization...")
-
 	// Load configuration
 	config, err := loadConfiguration()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	log.Printf("Configuration loaded: port=%s, dataFile=%s", config.Port, config.DataFilePath)
+	if !config.Quiet {
+		fmt.Println("Go CRUD Todo List API")
+	}
+	config.logStartup("Configuration loaded: port=%s, dataFile=%s", config.Port, config.DataFilePath)
 
 	// Initialize data file if it doesn't exist
-	if err := initializeDataFile(config.DataFilePath); err != nil {
+	if err := initializeDataFile(config.DataFilePath, config.IDStart, config.DataDirMode); err != nil {
 		return fmt.Errorf("failed to initialize data file: %w", err)
 	}
 
 	// Initialize repository layer
-	todoRepo := repository.NewFileBasedTodoRepository(config.DataFilePath)
+	var todoRepo repository.TodoRepository = repository.NewFileBasedTodoRepositoryWithConfig(config.DataFilePath, repository.RepositoryConfig{
+		IDStart:               config.IDStart,
+		MaxDescriptionLength:  config.MaxDescriptionLength,
+		DirMode:               config.DataDirMode,
+		RecreateDataDir:       config.RecreateDataDir,
+		RejectSymlinks:        !config.FollowSymlinks,
+		DuplicateIDPolicy:     config.DuplicateIDPolicy,
+		MaxDataFileAge:        config.MaxDataFileAge,
+		MonotonicUpdatedAt:    config.MonotonicUpdatedAt,
+		StorageFormat:         config.StorageFormat,
+		FutureCreatedAtPolicy: config.FutureCreatedAtPolicy,
+		ArchiveFilePath:       config.ArchiveFilePath,
+	})
 	
 	// Load existing data from file
 	if err := todoRepo.Load(); err != nil {
 		return fmt.Errorf("failed to load data from file: %w", err)
 	}
-	log.Println("Data loaded successfully")
+	config.logStartup("Data loaded successfully")
+
+	// Record repository calls for later replay when configured
+	if config.RecordFile != "" {
+		recordFile, err := os.OpenFile(config.RecordFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open record file: %w", err)
+		}
+		defer recordFile.Close()
+		todoRepo = repository.NewRecordingRepository(todoRepo, recordFile)
+		config.logStartup("Recording repository calls to %s", config.RecordFile)
+	}
 
 	// Initialize service layer with repository dependency
-	todoService := service.NewTodoService(todoRepo)
-	log.Println("Service layer initialized")
+	serviceConfig := service.ServiceConfig{
+		CompletionRequiredFields:      config.CompletionRequiredFields,
+		MaxDescriptionLength:          config.MaxDescriptionLength,
+		MaxDescriptionLines:           config.MaxDescriptionLines,
+		MaxTodoBytes:                  config.MaxTodoBytes,
+		MaxChecklistItems:             config.MaxChecklistItems,
+		AutoCompleteChecklist:         config.AutoCompleteChecklist,
+		PreserveDescriptionWhitespace: !config.TrimDescription,
+		RejectTitleControlChars:       config.RejectTitleControlChars,
+		TitleScript:                   config.TitleScript,
+		CreateDefaults:                config.CreateDefaults,
+		ForbiddenWords:                config.ForbiddenWords,
+		ArchiveCompleted:              config.ArchiveFilePath != "",
+		ArchiveAfter:                  config.ArchiveAfter,
+		RequireCompleteBeforeDelete:   config.RequireCompleteBeforeDelete,
+		AutoTitle:                     config.AutoTitle,
+		AutoTag:                       config.AutoTag,
+		StripHashtags:                 config.AutoTagStrip,
+	}
+	var auditBroker *audit.Broker
+	if config.AuditLogPath != "" {
+		serviceConfig.AuditLogger = audit.NewFileLogger(config.AuditLogPath)
+		config.logStartup("Audit logging enabled: %s", config.AuditLogPath)
+		if config.AuditStream {
+			auditBroker = audit.NewBroker()
+			serviceConfig.AuditLogger = audit.NewPublishingLogger(serviceConfig.AuditLogger, auditBroker)
+			config.logStartup("Audit streaming enabled at /admin/audit/stream")
+		}
+	}
+	todoService := service.NewTodoServiceWithConfig(todoRepo, serviceConfig)
+	config.logStartup("Service layer initialized")
+
+	// In import mode, load the file into the data store and exit without serving
+	if config.ImportFile != "" {
+		imported, err := importTodos(todoService, config.ImportFile)
+		if err != nil {
+			return fmt.Errorf("failed to import %s after %d todos: %w", config.ImportFile, imported, err)
+		}
+		log.Printf("Imported %d todos from %s", imported, config.ImportFile)
+		return nil
+	}
+
+	// Record a span per request and per service call when tracing is enabled
+	var tracerProvider trace.TracerProvider
+	if provider := newTracerProvider(config.Tracing); provider != nil {
+		defer func() {
+			if err := provider.Shutdown(context.Background()); err != nil {
+				log.Printf("Failed to flush spans: %v", err)
+			}
+		}()
+		tracerProvider = provider
+		todoService = service.NewTracingService(todoService, provider)
+		config.logStartup("Tracing enabled: %s", config.Tracing)
+	}
+
+	// Record request and response body sizes when metrics are enabled
+	var meterProvider metric.MeterProvider
+	if provider := newMeterProvider(config.Metrics); provider != nil {
+		defer func() {
+			if err := provider.Shutdown(context.Background()); err != nil {
+				log.Printf("Failed to flush metrics: %v", err)
+			}
+		}()
+		meterProvider = provider
+		config.logStartup("Metrics enabled: %s", config.Metrics)
+	}
 
 	// Initialize handler layer with service dependency
-	todoHandler := handler.NewTodoHandler(todoService)
-	log.Println("Handler layer initialized")
+	todoHandler := handler.NewTodoHandlerWithConfig(todoService, handler.HandlerConfig{
+		MaxBodyBytes:            config.MaxBodyBytes,
+		MaxImportBytes:          config.MaxImportBytes,
+		DecodeGzipRequests:      config.DecodeGzipRequests,
+		DebugBodies:             config.DebugBodies,
+		DebugBodyMaxBytes:       config.DebugBodyMaxBytes,
+		StrictQuery:             config.StrictQuery,
+		StrictIDs:               config.StrictIDs,
+		JSONCase:                config.JSONCase,
+		MaxBatchSize:            config.MaxBatchSize,
+		CoalesceReads:           config.CoalesceReads,
+		DeepReadiness:           config.DeepReadiness,
+		ConflictIncludesCurrent: config.ConflictIncludesCurrent,
+		ValidationDetails:       config.ValidationDetails,
+		OverdueConflict:         config.OverdueConflict,
+		CaseSensitiveSearch:     config.CaseSensitiveSearch,
+		CompressResponses:       config.CompressResponses,
+		GzipMinBytes:            config.GzipMinBytes,
+		AllowMethodOverride:     config.AllowMethodOverride,
+		IDEncoding:              config.IDEncoding,
+		IDSalt:                  config.IDSalt,
+		HideServerHeader:        config.Quiet,
+		TracerProvider:          tracerProvider,
+		MeterProvider:           meterProvider,
+		LargeRequestBytes:       config.LargeRequestBytes,
+		AuditBroker:             auditBroker,
+	})
+	config.logStartup("Handler layer initialized")
 
 	// Setup HTTP routes
-	mux := todoHandler.SetupRoutes()
-	log.Println("HTTP routes configured")
+	routes := todoHandler.SetupHandler()
+	config.logStartup("HTTP routes configured")
 
 	// Configure HTTP server with proper timeouts
-	server := &http.Server{
-		Addr:         ":" + config.Port,
-		Handler:      mux,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	server := newServer(config, routes)
+	registerShutdownHooks(server, auditBroker)
+	if !config.KeepAlive {
+		config.logStartup("HTTP keep-alives disabled: every connection is closed after one request")
+	}
+
+	// Bind the listener up front so address errors are reported before startup completes
+	listener, err := createListener(config)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Server listening on port %s", config.Port)
-		log.Printf("API endpoints available at http://localhost:%s/todos", config.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if config.ListenSocket != "" {
+			config.logStartup("Server listening on unix socket %s", config.ListenSocket)
+		} else {
+			config.logStartup("Server listening on port %s", config.Port)
+			config.logStartup("API endpoints available at http://localhost:%s/todos", config.Port)
+		}
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
 
-	log.Println("Application started successfully")
+	config.logStartup("Application started successfully")
+
+	// Periodically delete expired todos until shutdown
+	stopSweeper := startExpirySweeper(todoService, config.ExpirySweepInterval)
 
 	// Setup graceful shutdown
 	setupGracefulShutdown(server, todoRepo)
+	stopSweeper()
+
+	// Remove the socket file so the next start can bind the same path
+	if config.ListenSocket != "" {
+		if err := os.Remove(config.ListenSocket); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove socket file: %v", err)
+		}
+	}
 	return nil
 }
 
+// newServer configures the HTTP server's timeouts and keep-alive behavior. IdleTimeout
+// bounds how long a kept-alive connection waits for its next request; with keep-alives
+// disabled connections close after each response and it has no effect.
+func newServer(config *Config, handler http.Handler) *http.Server {
+	server := &http.Server{
+		Addr:         ":" + config.Port,
+		Handler:      handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  config.IdleTimeout,
+	}
+	server.SetKeepAlivesEnabled(config.KeepAlive)
+	return server
+}
+
+// registerShutdownHooks ends long-lived responses when shutdown starts. server.Shutdown
+// waits for open requests without cancelling their contexts, so an open audit stream would
+// otherwise hold up shutdown until its timeout.
+func registerShutdownHooks(server *http.Server, auditBroker *audit.Broker) {
+	if auditBroker != nil {
+		server.RegisterOnShutdown(auditBroker.Close)
+	}
+}
+
+// createListener opens a Unix domain socket listener when configured, otherwise a TCP listener on the port
+func createListener(config *Config) (net.Listener, error) {
+	if config.ListenSocket == "" {
+		return net.Listen("tcp", ":"+config.Port)
+	}
+
+	// Clear a stale socket left behind by an unclean exit, but never delete other files
+	if info, err := os.Lstat(config.ListenSocket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", config.ListenSocket)
+		}
+		if err := os.Remove(config.ListenSocket); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	return net.Listen("unix", config.ListenSocket)
+}
+
 // Config holds application configuration
 type Config struct {
-	Port         string
-	DataFilePath string
+	Port                        string
+	DataFilePath                string
+	MaxBodyBytes                int64
+	MaxImportBytes              int64
+	DecodeGzipRequests          bool
+	CompressResponses           bool
+	GzipMinBytes                int
+	AllowMethodOverride         bool
+	IDEncoding                  string
+	IDSalt                      string
+	AuditLogPath                string
+	IDStart                     int
+	DebugBodies                 bool
+	DebugBodyMaxBytes           int
+	CompletionRequiredFields    []string
+	CreateDefaults              service.CreateDefaults
+	ForbiddenWords              []string
+	ArchiveFilePath             string
+	ArchiveAfter                time.Duration
+	ListenSocket                string
+	StrictQuery                 bool
+	StrictIDs                   bool
+	JSONCase                    string
+	MaxBatchSize                int
+	CoalesceReads               bool
+	DeepReadiness               bool
+	ConflictIncludesCurrent     bool
+	CaseSensitiveSearch         bool
+	MaxDescriptionLength        int
+	MaxDescriptionLines         int
+	DataDirMode                 os.FileMode
+	RecreateDataDir             bool
+	TrimDescription             bool
+	RejectTitleControlChars     bool
+	TitleScript                 string
+	RequireCompleteBeforeDelete bool
+	AutoTitle                   bool
+	AuditStream                 bool
+	AutoTag                     bool
+	AutoTagStrip                bool
+	ValidationDetails           bool
+	MaxTodoBytes                int
+	OverdueConflict             string
+	MaxChecklistItems           int
+	AutoCompleteChecklist       bool
+	FollowSymlinks              bool
+	DuplicateIDPolicy           string
+	MaxDataFileAge              time.Duration
+	MonotonicUpdatedAt          bool
+	StorageFormat               string
+	FutureCreatedAtPolicy       string
+	ImportFile                  string
+	Tracing                     string
+	Metrics                     string
+	LargeRequestBytes           int64
+	RecordFile                  string
+	Quiet                       bool
+	ExpirySweepInterval         time.Duration
+	KeepAlive                   bool
+	IdleTimeout                 time.Duration
+}
+
+// logStartup logs an informational startup message unless quiet mode is enabled
+func (c *Config) logStartup(format string, args ...interface{}) {
+	if !c.Quiet {
+		log.Printf(format, args...)
+	}
 }
 
 // loadConfiguration loads application configuration from environment variables
 func loadConfiguration() (*Config, error) {
 	config := &Config{
-		Port:         getEnvOrDefault("PORT", "8080"),
-		DataFilePath: getEnvOrDefault("DATA_FILE", "todos.json"),
+		Port:            getEnvOrDefault("PORT", "8080"),
+		DataFilePath:    getEnvOrDefault("DATA_FILE", "todos.json"),
+		AuditLogPath:    os.Getenv("AUDIT_LOG"),
+		ImportFile:      os.Getenv("IMPORT_FILE"),
+		Tracing:         os.Getenv("TRACING"),
+		Metrics:         os.Getenv("METRICS"),
+		RecordFile:      os.Getenv("RECORD_FILE"),
+		ListenSocket:    os.Getenv("LISTEN_SOCKET"),
+		ArchiveFilePath: os.Getenv("ARCHIVE_FILE"),
+	}
+
+	defaults := handler.DefaultHandlerConfig()
+	var err error
+
+	if config.MaxBodyBytes, err = getEnvInt64OrDefault("MAX_BODY_BYTES", defaults.MaxBodyBytes); err != nil {
+		return nil, err
+	}
+	if config.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("invalid MAX_BODY_BYTES %d: must not be negative", config.MaxBodyBytes)
+	}
+	if config.MaxImportBytes, err = getEnvInt64OrDefault("MAX_IMPORT_BYTES", defaults.MaxImportBytes); err != nil {
+		return nil, err
+	}
+	if config.MaxImportBytes < 0 {
+		return nil, fmt.Errorf("invalid MAX_IMPORT_BYTES %d: must not be negative", config.MaxImportBytes)
+	}
+	if config.LargeRequestBytes, err = getEnvInt64OrDefault("LARGE_REQUEST_BYTES", 0); err != nil {
+		return nil, err
+	}
+	if config.LargeRequestBytes < 0 || (config.MaxBodyBytes > 0 && config.LargeRequestBytes >= config.MaxBodyBytes) {
+		return nil, fmt.Errorf("invalid LARGE_REQUEST_BYTES %d: must be between 0 and MAX_BODY_BYTES (%d)", config.LargeRequestBytes, config.MaxBodyBytes)
+	}
+	if config.DecodeGzipRequests, err = getEnvBoolOrDefault("GZIP_REQUESTS", defaults.DecodeGzipRequests); err != nil {
+		return nil, err
+	}
+	if config.CompressResponses, err = getEnvBoolOrDefault("GZIP_RESPONSES", defaults.CompressResponses); err != nil {
+		return nil, err
+	}
+	gzipMinBytes, err := getEnvInt64OrDefault("GZIP_MIN_BYTES", int64(defaults.GzipMinBytes))
+	if err != nil {
+		return nil, err
+	}
+	if gzipMinBytes < 0 || gzipMinBytes > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid GZIP_MIN_BYTES %d: must be between 0 and %d", gzipMinBytes, math.MaxInt32)
+	}
+	config.GzipMinBytes = int(gzipMinBytes)
+	maxBatchSize, err := getEnvInt64OrDefault("MAX_BATCH_SIZE", int64(defaults.MaxBatchSize))
+	if err != nil {
+		return nil, err
+	}
+	if maxBatchSize < 0 || maxBatchSize > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid MAX_BATCH_SIZE %d: must be between 0 and %d", maxBatchSize, math.MaxInt32)
+	}
+	config.MaxBatchSize = int(maxBatchSize)
+	if config.CoalesceReads, err = getEnvBoolOrDefault("COALESCE_READS", defaults.CoalesceReads); err != nil {
+		return nil, err
+	}
+	if config.DeepReadiness, err = getEnvBoolOrDefault("DEEP_READINESS", defaults.DeepReadiness); err != nil {
+		return nil, err
+	}
+	if config.ConflictIncludesCurrent, err = getEnvBoolOrDefault("CONFLICT_INCLUDES_CURRENT", defaults.ConflictIncludesCurrent); err != nil {
+		return nil, err
+	}
+	if config.AllowMethodOverride, err = getEnvBoolOrDefault("METHOD_OVERRIDE", defaults.AllowMethodOverride); err != nil {
+		return nil, err
+	}
+	if config.Quiet, err = getEnvBoolOrDefault("QUIET", false); err != nil {
+		return nil, err
+	}
+	if config.ExpirySweepInterval, err = getEnvDurationOrDefault("EXPIRY_SWEEP_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if config.KeepAlive, err = getEnvBoolOrDefault("KEEPALIVE", true); err != nil {
+		return nil, err
+	}
+	if config.IdleTimeout, err = getEnvDurationOrDefault("IDLE_TIMEOUT", 60*time.Second); err != nil {
+		return nil, err
+	}
+	// net/http treats a zero idle timeout as the read timeout, so zero can't mean "never"
+	if config.IdleTimeout == 0 {
+		return nil, fmt.Errorf("invalid IDLE_TIMEOUT %q: must be positive; set KEEPALIVE=false to disable keep-alives", os.Getenv("IDLE_TIMEOUT"))
+	}
+	if config.ArchiveAfter, err = getEnvDurationOrDefault("ARCHIVE_AFTER", 0); err != nil {
+		return nil, err
+	}
+	if config.ArchiveFilePath != "" && filepath.Clean(config.ArchiveFilePath) == filepath.Clean(config.DataFilePath) {
+		return nil, fmt.Errorf("invalid ARCHIVE_FILE %q: must differ from DATA_FILE", config.ArchiveFilePath)
+	}
+	if config.ArchiveAfter > 0 {
+		if config.ArchiveFilePath == "" {
+			return nil, fmt.Errorf("invalid ARCHIVE_AFTER %q: requires ARCHIVE_FILE", os.Getenv("ARCHIVE_AFTER"))
+		}
+		// Delayed archiving happens on the expiry sweep, so without it nothing is archived
+		if config.ExpirySweepInterval <= 0 {
+			return nil, fmt.Errorf("invalid ARCHIVE_AFTER %q: requires a positive EXPIRY_SWEEP_INTERVAL", os.Getenv("ARCHIVE_AFTER"))
+		}
+	}
+	config.IDEncoding = getEnvOrDefault("ID_ENCODING", handler.IDEncodingNone)
+	config.IDSalt = os.Getenv("ID_SALT")
+	if err := handler.ValidateIDEncoding(config.IDEncoding, config.IDSalt); err != nil {
+		return nil, fmt.Errorf("invalid ID_ENCODING: %w", err)
+	}
+
+	idStart, err := getEnvInt64OrDefault("ID_START", int64(repository.DefaultRepositoryConfig().IDStart))
+	if err != nil {
+		return nil, err
+	}
+	if idStart < 1 || idStart > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid ID_START %d: must be between 1 and %d", idStart, math.MaxInt32)
+	}
+	config.IDStart = int(idStart)
+
+	if config.DebugBodies, err = getEnvBoolOrDefault("DEBUG_BODIES", defaults.DebugBodies); err != nil {
+		return nil, err
+	}
+	debugBodyMaxBytes, err := getEnvInt64OrDefault("DEBUG_BODY_MAX_BYTES", int64(defaults.DebugBodyMaxBytes))
+	if err != nil {
+		return nil, err
+	}
+	if debugBodyMaxBytes < 0 || debugBodyMaxBytes > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid DEBUG_BODY_MAX_BYTES %d: must be between 0 and %d", debugBodyMaxBytes, math.MaxInt32)
+	}
+	config.DebugBodyMaxBytes = int(debugBodyMaxBytes)
+
+	if config.StrictQuery, err = getEnvBoolOrDefault("STRICT_QUERY", defaults.StrictQuery); err != nil {
+		return nil, err
+	}
+	if config.StrictIDs, err = getEnvBoolOrDefault("STRICT_IDS", defaults.StrictIDs); err != nil {
+		return nil, err
+	}
+	config.OverdueConflict = getEnvOrDefault("OVERDUE_CONFLICT", handler.OverdueConflictEmpty)
+	if err := handler.ValidateOverdueConflictPolicy(config.OverdueConflict); err != nil {
+		return nil, fmt.Errorf("invalid OVERDUE_CONFLICT: %w", err)
+	}
+	config.JSONCase = getEnvOrDefault("JSON_CASE", handler.JSONCaseSnake)
+	if err := handler.ValidateJSONCase(config.JSONCase); err != nil {
+		return nil, fmt.Errorf("invalid JSON_CASE: %w", err)
+	}
+	if config.CaseSensitiveSearch, err = getEnvBoolOrDefault("CASE_SENSITIVE_SEARCH", defaults.CaseSensitiveSearch); err != nil {
+		return nil, err
+	}
+
+	maxDescLen, err := getEnvInt64OrDefault("MAX_DESC_LEN", models.DefaultMaxDescriptionLength)
+	if err != nil {
+		return nil, err
+	}
+	if maxDescLen < 1 || maxDescLen > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid MAX_DESC_LEN %d: must be between 1 and %d", maxDescLen, math.MaxInt32)
+	}
+	config.MaxDescriptionLength = int(maxDescLen)
+
+	maxDescLines, err := getEnvInt64OrDefault("MAX_DESC_LINES", 0)
+	if err != nil {
+		return nil, err
+	}
+	if maxDescLines < 0 || maxDescLines > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid MAX_DESC_LINES %d: must be between 0 and %d", maxDescLines, math.MaxInt32)
+	}
+	config.MaxDescriptionLines = int(maxDescLines)
+
+	maxChecklistItems, err := getEnvInt64OrDefault("MAX_CHECKLIST_ITEMS", models.DefaultMaxChecklistItems)
+	if err != nil {
+		return nil, err
+	}
+	if maxChecklistItems < 1 || maxChecklistItems > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid MAX_CHECKLIST_ITEMS %d: must be between 1 and %d", maxChecklistItems, math.MaxInt32)
+	}
+	config.MaxChecklistItems = int(maxChecklistItems)
+
+	autoCompleteChecklist, err := getEnvBoolOrDefault("AUTO_COMPLETE_CHECKLIST", false)
+	if err != nil {
+		return nil, err
+	}
+	config.AutoCompleteChecklist = autoCompleteChecklist
+
+	maxTodoBytes, err := getEnvInt64OrDefault("MAX_TODO_BYTES", 0)
+	if err != nil {
+		return nil, err
+	}
+	if maxTodoBytes < 0 || maxTodoBytes > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid MAX_TODO_BYTES %d: must be between 0 and %d", maxTodoBytes, math.MaxInt32)
+	}
+	config.MaxTodoBytes = int(maxTodoBytes)
+
+	if config.DataDirMode, err = getEnvFileModeOrDefault("DATA_DIR_MODE", repository.DefaultDirMode); err != nil {
+		return nil, err
+	}
+
+	if config.TrimDescription, err = getEnvBoolOrDefault("TRIM_DESCRIPTION", true); err != nil {
+		return nil, err
+	}
+	if config.RejectTitleControlChars, err = getEnvBoolOrDefault("REJECT_TITLE_CONTROL_CHARS", false); err != nil {
+		return nil, err
+	}
+	config.TitleScript = getEnvOrDefault("TITLE_SCRIPT", models.TitleScriptAny)
+	if err := models.ValidateTitleScriptPolicy(config.TitleScript); err != nil {
+		return nil, fmt.Errorf("invalid TITLE_SCRIPT: %w", err)
+	}
+	if config.FollowSymlinks, err = getEnvBoolOrDefault("FOLLOW_SYMLINKS", true); err != nil {
+		return nil, err
+	}
+	if config.RecreateDataDir, err = getEnvBoolOrDefault("RECREATE_DATA_DIR", true); err != nil {
+		return nil, err
+	}
+	if config.RequireCompleteBeforeDelete, err = getEnvBoolOrDefault("REQUIRE_COMPLETE_BEFORE_DELETE", false); err != nil {
+		return nil, err
+	}
+	if config.AutoTitle, err = getEnvBoolOrDefault("AUTO_TITLE", false); err != nil {
+		return nil, err
+	}
+	if config.AutoTag, err = getEnvBoolOrDefault("AUTO_TAG", false); err != nil {
+		return nil, err
+	}
+	if config.AutoTagStrip, err = getEnvBoolOrDefault("AUTO_TAG_STRIP", false); err != nil {
+		return nil, err
+	}
+	if config.AutoTagStrip && !config.AutoTag {
+		return nil, fmt.Errorf("invalid AUTO_TAG_STRIP %q: requires AUTO_TAG=true", os.Getenv("AUTO_TAG_STRIP"))
+	}
+	if config.ValidationDetails, err = getEnvBoolOrDefault("VALIDATION_DETAILS", true); err != nil {
+		return nil, err
+	}
+	if config.AuditStream, err = getEnvBoolOrDefault("AUDIT_STREAM", false); err != nil {
+		return nil, err
+	}
+	if config.AuditStream && config.AuditLogPath == "" {
+		return nil, fmt.Errorf("invalid AUDIT_STREAM %q: requires AUDIT_LOG", os.Getenv("AUDIT_STREAM"))
+	}
+
+	config.DuplicateIDPolicy = getEnvOrDefault("DUPLICATE_IDS", repository.DuplicateIDsAllow)
+	if err := repository.ValidateDuplicateIDPolicy(config.DuplicateIDPolicy); err != nil {
+		return nil, fmt.Errorf("invalid DUPLICATE_IDS: %w", err)
+	}
+	config.FutureCreatedAtPolicy = getEnvOrDefault("FUTURE_CREATED_AT", repository.FutureCreatedAtAllow)
+	if err := repository.ValidateFutureCreatedAtPolicy(config.FutureCreatedAtPolicy); err != nil {
+		return nil, fmt.Errorf("invalid FUTURE_CREATED_AT: %w", err)
+	}
+	if config.MaxDataFileAge, err = getEnvDurationOrDefault("MAX_DATA_FILE_AGE", 0); err != nil {
+		return nil, err
+	}
+	config.StorageFormat = getEnvOrDefault("STORAGE_FORMAT", repository.StorageFormatJSON)
+	if err := repository.ValidateStorageFormat(config.StorageFormat); err != nil {
+		return nil, fmt.Errorf("invalid STORAGE_FORMAT: %w", err)
+	}
+	if config.MonotonicUpdatedAt, err = getEnvBoolOrDefault("MONOTONIC_UPDATED_AT", repository.DefaultRepositoryConfig().MonotonicUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	config.CompletionRequiredFields = getEnvList("COMPLETION_REQUIRED_FIELDS")
+	if err := service.ValidateCompletionRequiredFields(config.CompletionRequiredFields); err != nil {
+		return nil, fmt.Errorf("invalid COMPLETION_REQUIRED_FIELDS: %w", err)
+	}
+	config.ForbiddenWords = getEnvList("FORBIDDEN_WORDS")
+	if path := os.Getenv("FORBIDDEN_WORDS_FILE"); path != "" {
+		words, err := readWordListFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FORBIDDEN_WORDS_FILE: %w", err)
+		}
+		config.ForbiddenWords = append(config.ForbiddenWords, words...)
+	}
+	if err := service.ValidateForbiddenWords(config.ForbiddenWords); err != nil {
+		return nil, fmt.Errorf("invalid forbidden word: %w", err)
+	}
+	if err := validateTracing(config.Tracing); err != nil {
+		return nil, fmt.Errorf("invalid TRACING %q: %w", config.Tracing, err)
+	}
+	if err := validateMetrics(config.Metrics); err != nil {
+		return nil, fmt.Errorf("invalid METRICS %q: %w", config.Metrics, err)
+	}
+	if config.CreateDefaults, err = service.ParseCreateDefaults(os.Getenv("CREATE_DEFAULTS")); err != nil {
+		return nil, fmt.Errorf("invalid CREATE_DEFAULTS: %w", err)
 	}
 
 	// Validate port
 	if config.Port == "" {
 		return nil, fmt.Errorf("port cannot be empty")
 	}
+	if err := validatePort(config.Port); err != nil {
+		return nil, err
+	}
 
 	// Validate data file path
 	if config.DataFilePath == "" {
 		return nil, fmt.Errorf("data file path cannot be empty")
 	}
+	if err := validateDataDirWritable(config.DataFilePath); err != nil {
+		return nil, err
+	}
 
 	return config, nil
 }
 
-// initializeDataFile creates the data file if it doesn't exist
-func initializeDataFile(filePath string) error {
+// validatePort ensures the port is a number within the valid TCP port range
+func validatePort(port string) error {
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("invalid PORT %q: must be a number", port)
+	}
+	if value < 1 || value > 65535 {
+		return fmt.Errorf("invalid PORT %d: must be between 1 and 65535", value)
+	}
+	return nil
+}
+
+// validateDataDirWritable ensures the directory holding the data file accepts new files.
+// A directory that doesn't exist yet is checked through its nearest existing ancestor,
+// where it will be created.
+func validateDataDirWritable(filePath string) error {
+	dir := filepath.Dir(filePath)
+	for {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("data file directory %q is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
+
+// initializeDataFile creates the data file if it doesn't exist, starting IDs at idStart.
+// Missing parent directories are created with dirMode.
+func initializeDataFile(filePath string, idStart int, dirMode os.FileMode) error {
 	// Check if file already exists
 	if _, err := os.Stat(filePath); err == nil {
 		log.Printf("Data file already exists: %s", filePath)
@@ -122,10 +685,14 @@ func initializeDataFile(filePath string) error {
 	}
 
 	// Create empty JSON structure for new file
-	emptyStorage := `{
+	emptyStorage := fmt.Sprintf(`{
   "todos": [],
-  "next_id": 1
-}`
+  "next_id": %d
+}`, idStart)
+
+	if err := os.MkdirAll(filepath.Dir(filePath), dirMode); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
 
 	// Create the file with initial empty structure
 	if err := os.WriteFile(filePath, []byte(emptyStorage), 0644); err != nil {
@@ -144,6 +711,130 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList parses a comma-separated environment variable into its non-empty trimmed items
+func getEnvList(key string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readWordListFile reads one word per line, skipping blank lines and # comments
+func readWordListFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, nil
+}
+
+// getEnvInt64OrDefault parses an integer environment variable or returns a default value
+func getEnvInt64OrDefault(key string, defaultValue int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be an integer", key, value)
+	}
+	return parsed, nil
+}
+
+// getEnvDurationOrDefault parses a duration environment variable (e.g. "30s") or returns a default value
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration such as 30s or 5m", key, value)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", key, value)
+	}
+	return parsed, nil
+}
+
+// getEnvFileModeOrDefault parses an octal permission mode environment variable or returns a default value
+func getEnvFileModeOrDefault(key string, defaultValue os.FileMode) (os.FileMode, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || parsed > 0777 {
+		return 0, fmt.Errorf("invalid %s %q: must be an octal permission mode such as 0755", key, value)
+	}
+	return os.FileMode(parsed), nil
+}
+
+// getEnvBoolOrDefault parses a boolean environment variable or returns a default value
+func getEnvBoolOrDefault(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", key, value)
+	}
+	return parsed, nil
+}
+
+// startExpirySweeper deletes expired todos, and archives completed ones when archiving is
+// enabled, every interval in the background and returns a function that stops it; a zero
+// interval disables sweeping
+func startExpirySweeper(todoService service.TodoService, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				deleted, err := todoService.DeleteExpired()
+				if err != nil {
+					log.Printf("Failed to delete expired todos: %v", err)
+				}
+				if deleted > 0 {
+					log.Printf("Deleted %d expired todos", deleted)
+				}
+				archived, err := todoService.ArchiveCompleted()
+				if err != nil {
+					log.Printf("Failed to archive completed todos: %v", err)
+				}
+				if archived > 0 {
+					log.Printf("Archived %d completed todos", archived)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
 // setupGracefulShutdown handles graceful server shutdown on interrupt signals
 func setupGracefulShutdown(server *http.Server, repo repository.TodoRepository) {
 	// Create a channel to receive OS signals
@@ -0,0 +1,718 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/handler"
+	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"go-crud-todo-list/service"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfiguration_Defaults(t *testing.T) {
+	t.Setenv("PORT", "")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.Port != "8080" {
+		t.Errorf("Expected default port 8080, got %s", config.Port)
+	}
+}
+
+func TestLoadConfiguration_InvalidPort(t *testing.T) {
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+
+	tests := []struct {
+		port    string
+		message string
+	}{
+		{"http", "must be a number"},
+		{"0", "must be between 1 and 65535"},
+		{"65536", "must be between 1 and 65535"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("PORT", tt.port)
+
+		_, err := loadConfiguration()
+		if err == nil {
+			t.Errorf("Expected error for port %q, got nil", tt.port)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Expected error for port %q to contain %q, got %v", tt.port, tt.message, err)
+		}
+	}
+}
+
+func TestLoadConfiguration_UnwritableDirectory(t *testing.T) {
+	// A regular file cannot act as a directory, regardless of privileges
+	parent := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(parent, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(parent, "todos.json"))
+
+	_, err := loadConfiguration()
+	if err == nil {
+		t.Fatal("Expected error for unwritable data directory, got nil")
+	}
+	if !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("Expected not writable error, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidNumeric(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("MAX_BODY_BYTES", "1MB")
+
+	_, err := loadConfiguration()
+	if err == nil {
+		t.Fatal("Expected error for non-numeric MAX_BODY_BYTES, got nil")
+	}
+	if !strings.Contains(err.Error(), "MAX_BODY_BYTES") {
+		t.Errorf("Expected error to name MAX_BODY_BYTES, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_MaxDescLen(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+
+	t.Setenv("MAX_DESC_LEN", "500")
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.MaxDescriptionLength != 500 {
+		t.Errorf("Expected MaxDescriptionLength 500, got %d", config.MaxDescriptionLength)
+	}
+
+	t.Setenv("MAX_DESC_LEN", "0")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "MAX_DESC_LEN") {
+		t.Errorf("Expected error naming MAX_DESC_LEN, got %v", err)
+	}
+	t.Setenv("MAX_DESC_LEN", "500")
+
+	t.Setenv("MAX_DESC_LINES", "-1")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "MAX_DESC_LINES") {
+		t.Errorf("Expected error naming MAX_DESC_LINES, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_MaxTodoBytes(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+
+	t.Setenv("MAX_TODO_BYTES", "4096")
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.MaxTodoBytes != 4096 {
+		t.Errorf("Expected MaxTodoBytes 4096, got %d", config.MaxTodoBytes)
+	}
+
+	t.Setenv("MAX_TODO_BYTES", "-1")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "MAX_TODO_BYTES") {
+		t.Errorf("Expected error naming MAX_TODO_BYTES, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_MaxChecklistItems(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.MaxChecklistItems != models.DefaultMaxChecklistItems {
+		t.Errorf("Expected the default of %d checklist items, got %d", models.DefaultMaxChecklistItems, config.MaxChecklistItems)
+	}
+
+	t.Setenv("MAX_CHECKLIST_ITEMS", "0")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "MAX_CHECKLIST_ITEMS") {
+		t.Errorf("Expected error naming MAX_CHECKLIST_ITEMS, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_AutoCompleteChecklist(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("AUTO_COMPLETE_CHECKLIST", "true")
+
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !config.AutoCompleteChecklist {
+		t.Error("Expected AutoCompleteChecklist to be enabled")
+	}
+
+	t.Setenv("AUTO_COMPLETE_CHECKLIST", "sometimes")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "AUTO_COMPLETE_CHECKLIST") {
+		t.Errorf("Expected error naming AUTO_COMPLETE_CHECKLIST, got %v", err)
+	}
+}
+
+func TestInitializeDataFile_IDStart(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "todos.json")
+
+	if err := initializeDataFile(filePath, 1000, repository.DefaultDirMode); err != nil {
+		t.Fatalf("Failed to initialize data file: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if !strings.Contains(string(data), `"next_id": 1000`) {
+		t.Errorf("Expected next_id 1000 in new data file, got %s", data)
+	}
+}
+
+func TestInitializeDataFile_CreatesNestedDirectory(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "data", "nested", "todos.json"))
+	t.Setenv("DATA_DIR_MODE", "0700")
+
+	// A data file in a directory that doesn't exist yet passes validation
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := initializeDataFile(config.DataFilePath, config.IDStart, config.DataDirMode); err != nil {
+		t.Fatalf("Failed to initialize data file: %v", err)
+	}
+
+	if _, err := os.Stat(config.DataFilePath); err != nil {
+		t.Fatalf("Expected data file to be created, got %v", err)
+	}
+	info, err := os.Stat(filepath.Dir(config.DataFilePath))
+	if err != nil {
+		t.Fatalf("Expected data directory to be created, got %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("Expected directory mode 0700, got %o", perm)
+	}
+}
+
+func TestLoadConfiguration_InvalidDataDirMode(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("DATA_DIR_MODE", "rwx")
+
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "DATA_DIR_MODE") {
+		t.Errorf("Expected error naming DATA_DIR_MODE, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidDuplicateIDs(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("DUPLICATE_IDS", "ignore")
+
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "DUPLICATE_IDS") {
+		t.Errorf("Expected error naming DUPLICATE_IDS, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidFutureCreatedAt(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("FUTURE_CREATED_AT", "ignore")
+
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "FUTURE_CREATED_AT") {
+		t.Errorf("Expected error naming FUTURE_CREATED_AT, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_CreateDefaults(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("CREATE_DEFAULTS", "tags=Inbox, triage;due_in=72h")
+	
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected valid create defaults, got %v", err)
+	}
+	if !slices.Equal(config.CreateDefaults.Tags, []string{"inbox", "triage"}) || config.CreateDefaults.DueIn != 72*time.Hour {
+		t.Errorf("Unexpected create defaults: %+v", config.CreateDefaults)
+	}
+	
+	t.Setenv("CREATE_DEFAULTS", "priority=medium")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "CREATE_DEFAULTS") {
+		t.Errorf("Expected error naming CREATE_DEFAULTS, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_ForbiddenWords(t *testing.T) {
+	dir := t.TempDir()
+	wordsFile := filepath.Join(dir, "words.txt")
+	if err := os.WriteFile(wordsFile, []byte("# moderation list\nspam\n\nScam\n"), 0644); err != nil {
+		t.Fatalf("Failed to write words file: %v", err)
+	}
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(dir, "todos.json"))
+	t.Setenv("FORBIDDEN_WORDS", "darn, heck")
+	t.Setenv("FORBIDDEN_WORDS_FILE", wordsFile)
+	
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected valid forbidden words, got %v", err)
+	}
+	if !slices.Equal(config.ForbiddenWords, []string{"darn", "heck", "spam", "Scam"}) {
+		t.Errorf("Unexpected forbidden words: %v", config.ForbiddenWords)
+	}
+	
+	t.Setenv("FORBIDDEN_WORDS", "two words")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "forbidden word") {
+		t.Errorf("Expected error for a multi-word entry, got %v", err)
+	}
+	
+	t.Setenv("FORBIDDEN_WORDS", "")
+	t.Setenv("FORBIDDEN_WORDS_FILE", filepath.Join(dir, "missing.txt"))
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "FORBIDDEN_WORDS_FILE") {
+		t.Errorf("Expected error naming FORBIDDEN_WORDS_FILE, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidTracing(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("TRACING", "jaeger")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "TRACING") {
+		t.Errorf("Expected error naming TRACING, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidMetrics(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("METRICS", "prometheus")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "METRICS") {
+		t.Errorf("Expected error naming METRICS, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_MaxImportBytes(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("MAX_BODY_BYTES", "1024")
+	t.Setenv("MAX_IMPORT_BYTES", "0")
+
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.MaxImportBytes != 0 || config.MaxBodyBytes != 1024 {
+		t.Errorf("Expected an unlimited import alongside a 1024 byte body limit, got %d and %d", config.MaxImportBytes, config.MaxBodyBytes)
+	}
+
+	t.Setenv("MAX_IMPORT_BYTES", "-1")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "MAX_IMPORT_BYTES") {
+		t.Errorf("Expected error naming MAX_IMPORT_BYTES, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_LargeRequestBytes(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("MAX_BODY_BYTES", "1024")
+	
+	for _, value := range []string{"-1", "1024", "big"} {
+		t.Setenv("LARGE_REQUEST_BYTES", value)
+		if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "LARGE_REQUEST_BYTES") {
+			t.Errorf("Expected error naming LARGE_REQUEST_BYTES for %q, got %v", value, err)
+		}
+	}
+	
+	t.Setenv("LARGE_REQUEST_BYTES", "512")
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.LargeRequestBytes != 512 {
+		t.Errorf("Expected a 512 byte soft limit, got %d", config.LargeRequestBytes)
+	}
+}
+
+func TestLoadConfiguration_InvalidTitleScript(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("TITLE_SCRIPT", "klingon")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "TITLE_SCRIPT") {
+		t.Errorf("Expected error naming TITLE_SCRIPT, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidRecreateDataDir(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("RECREATE_DATA_DIR", "sometimes")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "RECREATE_DATA_DIR") {
+		t.Errorf("Expected error naming RECREATE_DATA_DIR, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidRequireCompleteBeforeDelete(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("REQUIRE_COMPLETE_BEFORE_DELETE", "maybe")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "REQUIRE_COMPLETE_BEFORE_DELETE") {
+		t.Errorf("Expected error naming REQUIRE_COMPLETE_BEFORE_DELETE, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidAutoTitle(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("AUTO_TITLE", "sure")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "AUTO_TITLE") {
+		t.Errorf("Expected error naming AUTO_TITLE, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_AuditStreamRequiresAuditLog(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("AUDIT_STREAM", "true")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "AUDIT_LOG") {
+		t.Errorf("Expected AUDIT_STREAM without AUDIT_LOG to be rejected, got %v", err)
+	}
+	
+	t.Setenv("AUDIT_LOG", filepath.Join(t.TempDir(), "audit.jsonl"))
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !config.AuditStream {
+		t.Error("Expected audit streaming to be enabled")
+	}
+}
+
+func TestLoadConfiguration_AutoTagStripRequiresAutoTag(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("AUTO_TAG_STRIP", "true")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "AUTO_TAG") {
+		t.Errorf("Expected AUTO_TAG_STRIP without AUTO_TAG to be rejected, got %v", err)
+	}
+	
+	t.Setenv("AUTO_TAG", "true")
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !config.AutoTag || !config.AutoTagStrip {
+		t.Errorf("Expected hashtag tagging with stripping, got AutoTag=%v AutoTagStrip=%v", config.AutoTag, config.AutoTagStrip)
+	}
+}
+
+func TestLoadConfiguration_InvalidValidationDetails(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("VALIDATION_DETAILS", "verbose")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "VALIDATION_DETAILS") {
+		t.Errorf("Expected error naming VALIDATION_DETAILS, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_InvalidOverdueConflict(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("OVERDUE_CONFLICT", "ignore")
+	
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "OVERDUE_CONFLICT") {
+		t.Errorf("Expected error naming OVERDUE_CONFLICT, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_ConflictIncludesCurrent(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !config.ConflictIncludesCurrent {
+		t.Error("Expected conflict responses to include the current todo by default")
+	}
+	
+	t.Setenv("CONFLICT_INCLUDES_CURRENT", "sometimes")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "CONFLICT_INCLUDES_CURRENT") {
+		t.Errorf("Expected error naming CONFLICT_INCLUDES_CURRENT, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_Archive(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "todos.json")
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", dataFile)
+	
+	t.Setenv("ARCHIVE_AFTER", "soon")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "ARCHIVE_AFTER") {
+		t.Errorf("Expected error naming ARCHIVE_AFTER, got %v", err)
+	}
+	
+	// A delay without an archive file, or without the sweep that archives, would never archive
+	t.Setenv("ARCHIVE_AFTER", "24h")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "requires ARCHIVE_FILE") {
+		t.Errorf("Expected ARCHIVE_AFTER without ARCHIVE_FILE to be rejected, got %v", err)
+	}
+	t.Setenv("ARCHIVE_FILE", filepath.Join(filepath.Dir(dataFile), "archive.json"))
+	t.Setenv("EXPIRY_SWEEP_INTERVAL", "0")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "EXPIRY_SWEEP_INTERVAL") {
+		t.Errorf("Expected ARCHIVE_AFTER without the expiry sweep to be rejected, got %v", err)
+	}
+	t.Setenv("EXPIRY_SWEEP_INTERVAL", "1m")
+	
+	t.Setenv("ARCHIVE_FILE", dataFile)
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "ARCHIVE_FILE") {
+		t.Errorf("Expected error naming ARCHIVE_FILE, got %v", err)
+	}
+	
+	t.Setenv("ARCHIVE_FILE", filepath.Join(filepath.Dir(dataFile), "archive.json"))
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.ArchiveAfter != 24*time.Hour {
+		t.Errorf("Expected a 24h archive delay, got %v", config.ArchiveAfter)
+	}
+}
+
+func TestLoadConfiguration_IDEncoding(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("ID_ENCODING", "hashid")
+
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "ID_ENCODING") {
+		t.Errorf("Expected error naming ID_ENCODING without a salt, got %v", err)
+	}
+
+	t.Setenv("ID_SALT", "pepper")
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.IDEncoding != handler.IDEncodingHashID || config.IDSalt != "pepper" {
+		t.Errorf("Expected hashid encoding with salt, got %q/%q", config.IDEncoding, config.IDSalt)
+	}
+}
+
+func TestLoadConfiguration_InvalidJSONCase(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("JSON_CASE", "kebab")
+
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "JSON_CASE") {
+		t.Errorf("Expected error naming JSON_CASE, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_ExpirySweepInterval(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	t.Setenv("EXPIRY_SWEEP_INTERVAL", "30s")
+
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.ExpirySweepInterval.Seconds() != 30 {
+		t.Errorf("Expected a 30s sweep interval, got %v", config.ExpirySweepInterval)
+	}
+
+	t.Setenv("EXPIRY_SWEEP_INTERVAL", "soon")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "EXPIRY_SWEEP_INTERVAL") {
+		t.Errorf("Expected error naming EXPIRY_SWEEP_INTERVAL, got %v", err)
+	}
+}
+
+func TestLoadConfiguration_KeepAlive(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATA_FILE", filepath.Join(t.TempDir(), "todos.json"))
+	
+	config, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !config.KeepAlive || config.IdleTimeout != 60*time.Second {
+		t.Errorf("Expected keep-alives on with a 60s idle timeout by default, got %v and %v", config.KeepAlive, config.IdleTimeout)
+	}
+	
+	t.Setenv("KEEPALIVE", "maybe")
+	if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "KEEPALIVE") {
+		t.Errorf("Expected error naming KEEPALIVE, got %v", err)
+	}
+	t.Setenv("KEEPALIVE", "false")
+	
+	for _, value := range []string{"0", "0s", "-5s", "forever"} {
+		t.Setenv("IDLE_TIMEOUT", value)
+		if _, err := loadConfiguration(); err == nil || !strings.Contains(err.Error(), "IDLE_TIMEOUT") {
+			t.Errorf("Expected error naming IDLE_TIMEOUT for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestNewServer_KeepAlive(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepAlive bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))
+			todoHandler := handler.NewTodoHandler(service.NewTodoService(repo))
+			server := newServer(&Config{KeepAlive: tt.keepAlive, IdleTimeout: time.Minute}, todoHandler.SetupHandler())
+			
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			go server.Serve(listener)
+			defer server.Close()
+			
+			resp, err := http.Get("http://" + listener.Addr().String() + "/healthz")
+			if err != nil {
+				t.Fatalf("Failed to request: %v", err)
+			}
+			resp.Body.Close()
+			
+			// A server with keep-alives disabled asks the client to close every connection
+			if resp.Close == tt.keepAlive {
+				t.Errorf("Expected Connection: close=%v, got %v", !tt.keepAlive, resp.Close)
+			}
+		})
+	}
+}
+
+func TestRegisterShutdownHooks_AuditStream(t *testing.T) {
+	repo := repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))
+	broker := audit.NewBroker()
+	config := handler.DefaultHandlerConfig()
+	config.AuditBroker = broker
+	todoHandler := handler.NewTodoHandlerWithConfig(service.NewTodoService(repo), config)
+	server := newServer(&Config{KeepAlive: true, IdleTimeout: time.Minute}, todoHandler.SetupHandler())
+	registerShutdownHooks(server, broker)
+	
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer server.Close()
+	
+	resp, err := http.Get("http://" + listener.Addr().String() + "/admin/audit/stream")
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	// The heartbeat sent when the stream opens confirms the subscription
+	if _, err := bufio.NewReader(resp.Body).ReadString('\n'); err != nil {
+		t.Fatalf("Failed to read heartbeat: %v", err)
+	}
+	
+	// An open stream must not hold shutdown until its deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Errorf("Expected shutdown to finish with a subscriber connected, got %v", err)
+	}
+}
+
+func TestCreateListener_UnixSocket(t *testing.T) {
+	// Socket paths are length-limited, so avoid the long t.TempDir() path
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "todo.sock")
+
+	listener, err := createListener(&Config{ListenSocket: socketPath})
+	if err != nil {
+		t.Fatalf("Failed to create listener: %v", err)
+	}
+
+	repo := repository.NewFileBasedTodoRepository(filepath.Join(t.TempDir(), "todos.json"))
+	todoHandler := handler.NewTodoHandler(service.NewTodoService(repo))
+	server := &http.Server{Handler: todoHandler.SetupHandler()}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	resp, err := client.Get("http://unix/healthz")
+	if err != nil {
+		t.Fatalf("Failed to request over socket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var health handler.HealthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if health.Status != "ok" {
+		t.Errorf("Expected status ok, got %q", health.Status)
+	}
+}
+
+func TestCreateListener_RefusesNonSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regular-file")
+	if err := os.WriteFile(path, []byte("keep me"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if _, err := createListener(&Config{ListenSocket: path}); err == nil {
+		t.Fatal("Expected error when the socket path is a regular file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected regular file to be left in place, got %v", err)
+	}
+}
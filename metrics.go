@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"log"
+	"time"
+)
+
+// Metrics modes accepted by the METRICS environment variable
+const (
+	MetricsOff = "off"
+	MetricsLog = "log"
+)
+
+// metricsLogInterval is how often METRICS=log writes the collected metrics
+const metricsLogInterval = time.Minute
+
+// validateMetrics reports whether mode is a supported METRICS value
+func validateMetrics(mode string) error {
+	switch mode {
+	case "", MetricsOff, MetricsLog:
+		return nil
+	default:
+		return fmt.Errorf("must be %q or %q", MetricsOff, MetricsLog)
+	}
+}
+
+// logMetricExporter writes each collected histogram data point as a log line
+type logMetricExporter struct{}
+
+// Temporality uses the SDK default, cumulative since startup
+func (logMetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+// Aggregation uses the SDK default for each instrument kind
+func (logMetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export logs the histograms in a collection
+func (logMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			histogram, ok := m.Data.(metricdata.Histogram[int64])
+			if !ok {
+				continue
+			}
+			for _, point := range histogram.DataPoints {
+				attrs := make([]string, 0, point.Attributes.Len())
+				for _, kv := range point.Attributes.ToSlice() {
+					attrs = append(attrs, fmt.Sprintf("%s=%s", kv.Key, kv.Value.Emit()))
+				}
+				maxValue, _ := point.Max.Value()
+				log.Printf("metric %q count=%d sum=%d%s max=%d %v",
+					m.Name, point.Count, point.Sum, m.Unit, maxValue, attrs)
+			}
+		}
+	}
+	return nil
+}
+
+// ForceFlush has nothing buffered
+func (logMetricExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// Shutdown has nothing to release
+func (logMetricExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// newMeterProvider returns the meter provider for a METRICS mode, or nil when metrics are off
+func newMeterProvider(mode string) *sdkmetric.MeterProvider {
+	if mode != MetricsLog {
+		return nil
+	}
+	reader := sdkmetric.NewPeriodicReader(logMetricExporter{}, sdkmetric.WithInterval(metricsLogInterval))
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Burndown bucket granularities
+const (
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// MaxBurndownBuckets bounds the number of buckets a single burndown can span
+const MaxBurndownBuckets = 1000
+
+// BurndownBucket counts the todos created and completed within one time bucket
+type BurndownBucket struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// bucketStart truncates t to the start of its day or ISO week (Monday) in t's location
+func bucketStart(t time.Time, bucket string) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if bucket == BucketWeek {
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+// nextBucket returns the start of the bucket following start
+func nextBucket(start time.Time, bucket string) time.Time {
+	if bucket == BucketWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// ComputeBurndown counts, per bucket between from and to (inclusive, in from's location),
+// how many todos were created and how many were completed
+func ComputeBurndown(todos []Todo, from, to time.Time, bucket string) ([]BurndownBucket, error) {
+	if bucket != BucketDay && bucket != BucketWeek {
+		return nil, fmt.Errorf("bucket must be %q or %q", BucketDay, BucketWeek)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	loc := from.Location()
+	first := bucketStart(from, bucket)
+	last := bucketStart(to.In(loc), bucket)
+
+	buckets := make([]BurndownBucket, 0)
+	index := make(map[time.Time]int)
+	for start := first; !start.After(last); start = nextBucket(start, bucket) {
+		if len(buckets) == MaxBurndownBuckets {
+			return nil, fmt.Errorf("range spans more than %d buckets", MaxBurndownBuckets)
+		}
+		index[start] = len(buckets)
+		buckets = append(buckets, BurndownBucket{Start: start})
+	}
+
+	inRange := func(t time.Time) (int, bool) {
+		if t.Before(from) || t.After(to) {
+			return 0, false
+		}
+		i, ok := index[bucketStart(t.In(loc), bucket)]
+		return i, ok
+	}
+
+	for i := range todos {
+		if b, ok := inRange(todos[i].CreatedAt); ok {
+			buckets[b].Created++
+		}
+		if todos[i].CompletedAt != nil {
+			if b, ok := inRange(*todos[i].CompletedAt); ok {
+				buckets[b].Completed++
+			}
+		}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ChecklistItem is one step of a todo's checklist
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Checklist limits
+const (
+	// MaxChecklistItemLength is the maximum length of a checklist item's text
+	MaxChecklistItemLength = 200
+	// DefaultMaxChecklistItems is the checklist size limit used when none is configured
+	DefaultMaxChecklistItems = 50
+)
+
+// ValidateChecklistItemText checks that checklist item text is present and within
+// MaxChecklistItemLength
+func ValidateChecklistItemText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("checklist item text is required")
+	}
+	if len(text) > MaxChecklistItemLength {
+		return &LimitError{Field: "checklist item", Unit: LimitUnitCharacters, Max: MaxChecklistItemLength, Actual: len(text)}
+	}
+	return nil
+}
+
+// ChecklistProgress returns how many checklist items are done, how many there are, and the
+// ratio of the two; the ratio of an empty checklist is 0
+func (t *Todo) ChecklistProgress() (done, total int, ratio float64) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	total = len(t.Checklist)
+	if total > 0 {
+		ratio = float64(done) / float64(total)
+	}
+	return done, total, ratio
+}
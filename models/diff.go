@@ -0,0 +1,70 @@
+package models
+
+import (
+	"slices"
+	"sort"
+)
+
+// ModifiedTodo lists the field changes of a todo present in both snapshots
+type ModifiedTodo struct {
+	ID      int           `json:"id"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// DiffResult describes how a set of todos changed relative to an earlier snapshot
+type DiffResult struct {
+	Added    []Todo         `json:"added"`
+	Removed  []Todo         `json:"removed"`
+	Modified []ModifiedTodo `json:"modified"`
+}
+
+// DiffStorage compares the todos of an earlier snapshot with the current todos by ID.
+// Modified todos report changes to the editable fields, tags, checklist and pinning;
+// results are ordered by ID.
+func DiffStorage(old, current []Todo) DiffResult {
+	result := DiffResult{
+		Added:    make([]Todo, 0),
+		Removed:  make([]Todo, 0),
+		Modified: make([]ModifiedTodo, 0),
+	}
+
+	previous := make(map[int]Todo, len(old))
+	for _, todo := range old {
+		previous[todo.ID] = todo
+	}
+
+	seen := make(map[int]bool, len(current))
+	for _, todo := range current {
+		seen[todo.ID] = true
+		before, ok := previous[todo.ID]
+		if !ok {
+			result.Added = append(result.Added, todo)
+			continue
+		}
+
+		changes := DiffTodos(before, todo)
+		if !slices.Equal(before.Tags, todo.Tags) {
+			changes = append(changes, FieldChange{Field: "tags", Old: before.Tags, New: todo.Tags})
+		}
+		if !slices.Equal(before.Checklist, todo.Checklist) {
+			changes = append(changes, FieldChange{Field: "checklist", Old: before.Checklist, New: todo.Checklist})
+		}
+		if before.Pinned != todo.Pinned {
+			changes = append(changes, FieldChange{Field: "pinned", Old: before.Pinned, New: todo.Pinned})
+		}
+		if len(changes) > 0 {
+			result.Modified = append(result.Modified, ModifiedTodo{ID: todo.ID, Changes: changes})
+		}
+	}
+
+	for _, todo := range old {
+		if !seen[todo.ID] {
+			result.Removed = append(result.Removed, todo)
+		}
+	}
+
+	sort.Slice(result.Added, func(i, j int) bool { return result.Added[i].ID < result.Added[j].ID })
+	sort.Slice(result.Removed, func(i, j int) bool { return result.Removed[i].ID < result.Removed[j].ID })
+	sort.Slice(result.Modified, func(i, j int) bool { return result.Modified[i].ID < result.Modified[j].ID })
+	return result
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// WordList is a set of lowercase words matched against whole words of text, ignoring case
+type WordList map[string]struct{}
+
+// isWordSeparator reports whether r separates words; letters, digits and apostrophes don't
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+}
+
+// NewWordList builds a WordList from words, which must each be a single word
+func NewWordList(words []string) (WordList, error) {
+	list := make(WordList, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		if fields := strings.FieldsFunc(word, isWordSeparator); len(fields) != 1 || fields[0] != word {
+			return nil, fmt.Errorf("%q is not a single word", word)
+		}
+		list[word] = struct{}{}
+	}
+	return list, nil
+}
+
+// Find returns the first word of text on the list, so "Class" matches "class" but
+// "classic" and "subclass" don't
+func (l WordList) Find(text string) (string, bool) {
+	if len(l) == 0 {
+		return "", false
+	}
+	for _, word := range strings.FieldsFunc(text, isWordSeparator) {
+		if _, ok := l[strings.ToLower(word)]; ok {
+			return word, true
+		}
+	}
+	return "", false
+}
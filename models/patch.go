@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPatch is returned when a JSON Patch document is malformed or targets an unknown path
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// ErrPatchTestFailed is returned when a JSON Patch "test" operation does not match the current value
+var ErrPatchTestFailed = errors.New("patch test failed")
+
+// PatchOperation represents a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies the subset of RFC 6902 supported for todos (add, replace,
+// remove, test) to the user-editable fields and returns the patched copy. Operations
+// are applied in order and the whole patch fails if any operation fails.
+func ApplyJSONPatch(todo Todo, ops []PatchOperation) (Todo, error) {
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case "add", "replace":
+			err = setPatchValue(&todo, op)
+		case "remove":
+			err = removePatchValue(&todo, op.Path)
+		case "test":
+			err = testPatchValue(&todo, op)
+		default:
+			err = fmt.Errorf("%w: unsupported op %q", ErrInvalidPatch, op.Op)
+		}
+		if err != nil {
+			return todo, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return todo, nil
+}
+
+// setPatchValue assigns the operation value to the field addressed by its path
+func setPatchValue(todo *Todo, op PatchOperation) error {
+	if len(op.Value) == 0 {
+		return fmt.Errorf("%w: %s requires a value", ErrInvalidPatch, op.Op)
+	}
+	switch op.Path {
+	case "/title":
+		return decodePatchValue(op, &todo.Title)
+	case "/description":
+		return decodePatchValue(op, &todo.Description)
+	case "/completed":
+		return decodePatchValue(op, &todo.Completed)
+	default:
+		return fmt.Errorf("%w: unknown path %q", ErrInvalidPatch, op.Path)
+	}
+}
+
+// removePatchValue resets the field addressed by the path to its zero value
+func removePatchValue(todo *Todo, path string) error {
+	switch path {
+	case "/title":
+		todo.Title = ""
+	case "/description":
+		todo.Description = ""
+	case "/completed":
+		todo.Completed = false
+	default:
+		return fmt.Errorf("%w: unknown path %q", ErrInvalidPatch, path)
+	}
+	return nil
+}
+
+// testPatchValue checks that the field addressed by the path equals the operation value
+func testPatchValue(todo *Todo, op PatchOperation) error {
+	if len(op.Value) == 0 {
+		return fmt.Errorf("%w: test requires a value", ErrInvalidPatch)
+	}
+
+	var matches bool
+	switch op.Path {
+	case "/title":
+		var expected string
+		if err := decodePatchValue(op, &expected); err != nil {
+			return err
+		}
+		matches = todo.Title == expected
+	case "/description":
+		var expected string
+		if err := decodePatchValue(op, &expected); err != nil {
+			return err
+		}
+		matches = todo.Description == expected
+	case "/completed":
+		var expected bool
+		if err := decodePatchValue(op, &expected); err != nil {
+			return err
+		}
+		matches = todo.Completed == expected
+	case "/version":
+		var expected int
+		if err := decodePatchValue(op, &expected); err != nil {
+			return err
+		}
+		matches = todo.Version == expected
+	default:
+		return fmt.Errorf("%w: unknown path %q", ErrInvalidPatch, op.Path)
+	}
+
+	if !matches {
+		return fmt.Errorf("%w: %s does not match", ErrPatchTestFailed, op.Path)
+	}
+	return nil
+}
+
+// decodePatchValue unmarshals the operation value into the target, rejecting mismatched types
+func decodePatchValue(op PatchOperation, target interface{}) error {
+	if err := json.Unmarshal(op.Value, target); err != nil {
+		return fmt.Errorf("%w: invalid value for %s", ErrInvalidPatch, op.Path)
+	}
+	return nil
+}
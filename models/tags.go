@@ -0,0 +1,164 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MaxTagLength is the maximum length of a single tag
+const MaxTagLength = 50
+
+// TagBatchResult reports the outcome of a bulk tag change
+type TagBatchResult struct {
+	Updated    []Todo `json:"updated"`
+	MissingIDs []int  `json:"missing_ids"`
+}
+
+// NormalizeTags trims and lowercases tags, dropping empty values and duplicates
+// while keeping the order in which tags first appear
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// ValidateTags ensures every tag fits within MaxTagLength
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if len(tag) > MaxTagLength {
+			return &LimitError{Field: "tag", Value: tag, Unit: LimitUnitCharacters, Max: MaxTagLength, Actual: len(tag)}
+		}
+	}
+	return nil
+}
+
+// ExtractHashtags finds the #hashtags in a title and returns them normalized, along with
+// the title with them removed and its remaining words single-spaced. A hashtag is a # at the
+// start of a word followed by letters, digits, '-' or '_', so "C#" and a lone "#" are text.
+func ExtractHashtags(title string) (tags []string, stripped string) {
+	words := strings.Fields(title)
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if tag, ok := strings.CutPrefix(word, "#"); ok && isHashtag(tag) {
+			tags = append(tags, tag)
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return NormalizeTags(tags), strings.Join(kept, " ")
+}
+
+// isHashtag reports whether tag, without its #, is a non-empty run of hashtag characters
+func isHashtag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyTagChanges returns the normalized tag set after adding and removing the given tags
+func ApplyTagChanges(tags, add, remove []string) []string {
+	result := make([]string, 0, len(tags)+len(add))
+	for _, tag := range NormalizeTags(append(slices.Clone(tags), add...)) {
+		if !slices.Contains(remove, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// sharedTagCount returns how many tags a and b have in common
+func sharedTagCount(a, b []string) int {
+	count := 0
+	for _, tag := range a {
+		if slices.Contains(b, tag) {
+			count++
+		}
+	}
+	return count
+}
+
+// RankRelated returns up to limit candidates sharing at least one tag with target, most shared
+// tags first and then by ID. The target itself is never included.
+func RankRelated(target Todo, candidates []Todo, limit int) []Todo {
+	type ranked struct {
+		todo   Todo
+		shared int
+	}
+
+	matches := make([]ranked, 0)
+	for _, todo := range candidates {
+		if todo.ID == target.ID {
+			continue
+		}
+		if shared := sharedTagCount(target.Tags, todo.Tags); shared > 0 {
+			matches = append(matches, ranked{todo: todo, shared: shared})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b ranked) int {
+		if a.shared != b.shared {
+			return b.shared - a.shared
+		}
+		return cmp.Compare(a.todo.ID, b.todo.ID)
+	})
+
+	related := make([]Todo, 0, min(limit, len(matches)))
+	for _, match := range matches {
+		if len(related) == limit {
+			break
+		}
+		related = append(related, match.todo)
+	}
+	return related
+}
+
+// TagStats counts the todos carrying a tag and how many of them are completed
+type TagStats struct {
+	Tag       string `json:"tag"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+}
+
+// ComputeTagStats aggregates per-tag totals and completed counts, most used tags first
+// and then alphabetically. Untagged todos are not counted.
+func ComputeTagStats(todos []Todo) []TagStats {
+	byTag := make(map[string]*TagStats)
+	for _, todo := range todos {
+		for _, tag := range todo.Tags {
+			stats, ok := byTag[tag]
+			if !ok {
+				stats = &TagStats{Tag: tag}
+				byTag[tag] = stats
+			}
+			stats.Total++
+			if todo.Completed {
+				stats.Completed++
+			}
+		}
+	}
+
+	result := make([]TagStats, 0, len(byTag))
+	for _, stats := range byTag {
+		result = append(result, *stats)
+	}
+	slices.SortFunc(result, func(a, b TagStats) int {
+		if a.Total != b.Total {
+			return b.Total - a.Total
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return result
+}
@@ -2,18 +2,49 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 )
 
 // Todo represents a todo item with all required fields
 type Todo struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Completed   bool            `json:"completed"`
+	DueDate     *time.Time      `json:"due_date,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Checklist   []ChecklistItem `json:"checklist,omitempty"`
+	Pinned      bool            `json:"pinned"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Source      string          `json:"source,omitempty"`
+	ExpiresAt   *time.Time      `json:"expires_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Version     int             `json:"version"`
+}
+
+// In returns a copy of the todo with its timestamps expressed in loc
+func (t Todo) In(loc *time.Location) Todo {
+	t.CreatedAt = t.CreatedAt.In(loc)
+	t.UpdatedAt = t.UpdatedAt.In(loc)
+	if t.DueDate != nil {
+		dueDate := t.DueDate.In(loc)
+		t.DueDate = &dueDate
+	}
+	if t.CompletedAt != nil {
+		completedAt := t.CompletedAt.In(loc)
+		t.CompletedAt = &completedAt
+	}
+	if t.ExpiresAt != nil {
+		expiresAt := t.ExpiresAt.In(loc)
+		t.ExpiresAt = &expiresAt
+	}
+	return t
 }
 
 // ValidateTitle validates the todo title according to requirements
@@ -21,26 +52,168 @@ func (t *Todo) ValidateTitle() error {
 	if strings.TrimSpace(t.Title) == "" {
 		return errors.New("title is required")
 	}
-	if len(t.Title) > 200 {
-		return errors.New("title must be 200 characters or less")
+	if len(t.Title) > MaxTitleLength {
+		return TitleTooLongError(len(t.Title))
+	}
+	return nil
+}
+
+// ValidateTitleCharacters rejects control characters such as newlines and tabs inside the
+// title, which break single-line displays and logs. Leading and trailing whitespace is
+// ignored since titles are trimmed.
+func (t *Todo) ValidateTitleCharacters() error {
+	for _, r := range strings.TrimSpace(t.Title) {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("title must not contain control characters (found %U)", r)
+		}
+	}
+	return nil
+}
+
+// AutoTitleMaxLength caps titles derived from descriptions, in characters
+const AutoTitleMaxLength = 80
+
+// TitleFromDescription derives a title from the first non-blank line of a description,
+// collapsing runs of whitespace. Lines longer than AutoTitleMaxLength are cut at the last
+// word boundary that fits and marked with an ellipsis. It returns "" for a blank description.
+func TitleFromDescription(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		title := strings.Join(strings.Fields(line), " ")
+		if title == "" {
+			continue
+		}
+		runes := []rune(title)
+		if len(runes) <= AutoTitleMaxLength {
+			return title
+		}
+		cut := string(runes[:AutoTitleMaxLength-1])
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		return strings.TrimRightFunc(cut, unicode.IsPunct) + "…"
+	}
+	return ""
+}
+
+// Title script policies restricting which characters a title may use
+const (
+	// TitleScriptAny accepts characters from every script
+	TitleScriptAny = "any"
+	// TitleScriptLatin accepts Latin letters plus the digits, punctuation, symbols and
+	// combining marks shared by all scripts
+	TitleScriptLatin = "latin"
+	// TitleScriptASCII accepts only ASCII characters
+	TitleScriptASCII = "ascii"
+)
+
+// ValidateTitleScriptPolicy checks that policy names a supported title script policy
+func ValidateTitleScriptPolicy(policy string) error {
+	switch policy {
+	case TitleScriptAny, TitleScriptLatin, TitleScriptASCII:
+		return nil
+	}
+	return fmt.Errorf("unsupported title script %q (supported: %s, %s, %s)",
+		policy, TitleScriptAny, TitleScriptLatin, TitleScriptASCII)
+}
+
+// ValidateTitleScript rejects titles containing characters outside the scripts the policy
+// allows; an empty policy allows any script
+func (t *Todo) ValidateTitleScript(policy string) error {
+	for _, r := range t.Title {
+		switch policy {
+		case TitleScriptLatin:
+			if !unicode.In(r, unicode.Latin, unicode.Common, unicode.Inherited) {
+				return fmt.Errorf("title must use only Latin script characters (found %U %q)", r, r)
+			}
+		case TitleScriptASCII:
+			if r > unicode.MaxASCII {
+				return fmt.Errorf("title must use only ASCII characters (found %U %q)", r, r)
+			}
+		}
+	}
+	return nil
+}
+
+// LimitError reports a value over a length limit, with the numbers clients need to
+// correct it without parsing the message
+type LimitError struct {
+	Field string `json:"field"`
+	// Value identifies which of several values broke the limit, such as the offending tag
+	Value  string `json:"value,omitempty"`
+	Unit   string `json:"unit"`
+	Max    int    `json:"max"`
+	Actual int    `json:"actual"`
+}
+
+// Error describes the limit, e.g. "title must be 200 characters or less"
+func (e *LimitError) Error() string {
+	if e.Value != "" {
+		return fmt.Sprintf("%s %q must be %d %s or less", e.Field, e.Value, e.Max, e.Unit)
+	}
+	return fmt.Sprintf("%s must be %d %s or less", e.Field, e.Max, e.Unit)
+}
+
+// Units of the lengths a LimitError compares
+const (
+	LimitUnitCharacters = "characters"
+	LimitUnitLines      = "lines"
+	LimitUnitBytes      = "bytes"
+	LimitUnitItems      = "items"
+)
+
+// MaxTitleLength is the maximum length of a title
+const MaxTitleLength = 200
+
+// TitleTooLongError builds the validation error for a title of actual length over MaxTitleLength
+func TitleTooLongError(actual int) error {
+	return &LimitError{Field: "title", Unit: LimitUnitCharacters, Max: MaxTitleLength, Actual: actual}
+}
+
+// DefaultMaxDescriptionLength is the description length limit used when none is configured
+const DefaultMaxDescriptionLength = 1000
+
+// DescriptionTooLongError builds the validation error for a description of actual length over maxLength
+func DescriptionTooLongError(maxLength, actual int) error {
+	return &LimitError{Field: "description", Unit: LimitUnitCharacters, Max: maxLength, Actual: actual}
+}
+
+// DescriptionTooManyLinesError builds the validation error for a description of actual lines over maxLines
+func DescriptionTooManyLinesError(maxLines, actual int) error {
+	return &LimitError{Field: "description", Unit: LimitUnitLines, Max: maxLines, Actual: actual}
+}
+
+// ValidateDescriptionLines validates the number of lines in the description; zero means unlimited
+func (t *Todo) ValidateDescriptionLines(maxLines int) error {
+	if lines := strings.Count(t.Description, "\n") + 1; maxLines > 0 && lines > maxLines {
+		return DescriptionTooManyLinesError(maxLines, lines)
 	}
 	return nil
 }
 
 // ValidateDescription validates the todo description according to requirements
 func (t *Todo) ValidateDescription() error {
-	if len(t.Description) > 1000 {
-		return errors.New("description must be 1000 characters or less")
+	return t.ValidateDescriptionLength(DefaultMaxDescriptionLength)
+}
+
+// ValidateDescriptionLength validates the todo description against the given length limit
+func (t *Todo) ValidateDescriptionLength(maxLength int) error {
+	if len(t.Description) > maxLength {
+		return DescriptionTooLongError(maxLength, len(t.Description))
 	}
 	return nil
 }
 
 // Validate performs full validation of the todo item
 func (t *Todo) Validate() error {
+	return t.ValidateWithMaxDescription(DefaultMaxDescriptionLength)
+}
+
+// ValidateWithMaxDescription performs full validation using the given description length limit
+func (t *Todo) ValidateWithMaxDescription(maxDescriptionLength int) error {
 	if err := t.ValidateTitle(); err != nil {
 		return err
 	}
-	if err := t.ValidateDescription(); err != nil {
+	if err := t.ValidateDescriptionLength(maxDescriptionLength); err != nil {
 		return err
 	}
 	return nil
@@ -55,35 +228,347 @@ func (t *Todo) SetTimestamps() {
 	t.UpdatedAt = now
 }
 
+// FieldChange describes a single field whose value differs between two todos
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffTodos compares the user-editable fields of two todos and returns the changes
+// needed to turn before into after, using the JSON field names
+func DiffTodos(before, after Todo) []FieldChange {
+	changes := make([]FieldChange, 0)
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", Old: before.Title, New: after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, FieldChange{Field: "description", Old: before.Description, New: after.Description})
+	}
+	if before.Completed != after.Completed {
+		changes = append(changes, FieldChange{Field: "completed", Old: before.Completed, New: after.Completed})
+	}
+	if !equalTimes(before.DueDate, after.DueDate) {
+		changes = append(changes, FieldChange{Field: "due_date", Old: before.DueDate, New: after.DueDate})
+	}
+	if !equalTimes(before.ExpiresAt, after.ExpiresAt) {
+		changes = append(changes, FieldChange{Field: "expires_at", Old: before.ExpiresAt, New: after.ExpiresAt})
+	}
+	return changes
+}
+
+// Bulk update result statuses
+const (
+	BulkStatusUpdated  = "updated"
+	BulkStatusConflict = "conflict"
+	BulkStatusNotFound = "not_found"
+	BulkStatusInvalid  = "invalid"
+)
+
+// BulkUpdateItem is a single full-replacement update within a bulk request
+type BulkUpdateItem struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	// Version is the version the client last saw; zero skips the optimistic-concurrency check
+	Version int `json:"version"`
+}
+
+// BulkUpdateResult reports the outcome of a single item in a bulk update
+type BulkUpdateResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	// Version is the new version when updated, or the current server version on conflict
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Todo    *Todo  `json:"todo,omitempty"`
+}
+
+// equalTimes reports whether two optional timestamps are both unset or denote the same instant
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// Creation sources recorded on todos
+const (
+	SourceAPI    = "api"
+	SourceImport = "import"
+	SourceClone  = "clone"
+)
+
+// KnownSources lists the creation sources a todo can carry
+var KnownSources = []string{SourceAPI, SourceImport, SourceClone}
+
+// ValidateSource checks that source is one of KnownSources
+func ValidateSource(source string) error {
+	if !slices.Contains(KnownSources, source) {
+		return fmt.Errorf("unknown source %q (supported: %s)", source, strings.Join(KnownSources, ", "))
+	}
+	return nil
+}
+
+// CreationSource returns how the todo was created; todos stored before sources were
+// recorded could only have been created through the API
+func (t *Todo) CreationSource() string {
+	if t.Source == "" {
+		return SourceAPI
+	}
+	return t.Source
+}
+
+// ListFilter describes the criteria used to select a subset of todos
+type ListFilter struct {
+	// Completed restricts results to the given completion status when set
+	Completed *bool
+	// TitlePrefix restricts results to titles starting with the value
+	TitlePrefix string
+	// TitleSuffix restricts results to titles ending with the value
+	TitleSuffix string
+	// CaseSensitive makes the title criteria match case exactly instead of ignoring case
+	CaseSensitive bool
+	// Source restricts results to todos with the given creation source when set
+	Source string
+	// Query restricts results to todos whose title or description contains the value
+	Query string
+	// Overdue restricts results to open todos due before OverdueAt when true, and to all
+	// other todos when false. Completed todos are never overdue.
+	Overdue *bool
+	// OverdueAt is the reference time for Overdue; the service sets it from its clock when zero
+	OverdueAt time.Time
+}
+
+// IsOverdue reports whether the todo is open and was due before now
+func (t *Todo) IsOverdue(now time.Time) bool {
+	return !t.Completed && t.DueDate != nil && t.DueDate.Before(now)
+}
+
+// Matches reports whether the todo satisfies every criterion of the filter
+func (f ListFilter) Matches(todo *Todo) bool {
+	if f.Completed != nil && todo.Completed != *f.Completed {
+		return false
+	}
+	if f.Source != "" && todo.CreationSource() != f.Source {
+		return false
+	}
+	if f.Overdue != nil && todo.IsOverdue(f.OverdueAt) != *f.Overdue {
+		return false
+	}
+	if f.TitlePrefix == "" && f.TitleSuffix == "" && f.Query == "" {
+		return true
+	}
+	title := FoldCase(todo.Title, f.CaseSensitive)
+	if f.TitlePrefix != "" && !strings.HasPrefix(title, FoldCase(f.TitlePrefix, f.CaseSensitive)) {
+		return false
+	}
+	if f.TitleSuffix != "" && !strings.HasSuffix(title, FoldCase(f.TitleSuffix, f.CaseSensitive)) {
+		return false
+	}
+	if f.Query != "" {
+		query := FoldCase(f.Query, f.CaseSensitive)
+		if !strings.Contains(title, query) && !strings.Contains(FoldCase(todo.Description, f.CaseSensitive), query) {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareActionable orders todos by what to work on next: pinned first, then by earliest
+// due date (todos without one last), then oldest first by ID
+func CompareActionable(a, b Todo) int {
+	if a.Pinned != b.Pinned {
+		if a.Pinned {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case a.DueDate != nil && b.DueDate == nil:
+		return -1
+	case a.DueDate == nil && b.DueDate != nil:
+		return 1
+	case a.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+		return a.DueDate.Compare(*b.DueDate)
+	}
+	return a.ID - b.ID
+}
+
+// SortPinnedFirst reorders todos so pinned ones come first, keeping the existing
+// order within the pinned and unpinned groups
+func SortPinnedFirst(todos []Todo) {
+	slices.SortStableFunc(todos, comparePinned)
+}
+
+// PinnedFirst returns todos in the order SortPinnedFirst gives them without modifying
+// todos, so it is safe on a shared slice: todos already in that order are returned as is,
+// and a sorted copy otherwise
+func PinnedFirst(todos []Todo) []Todo {
+	if slices.IsSortedFunc(todos, comparePinned) {
+		return todos
+	}
+	sorted := slices.Clone(todos)
+	SortPinnedFirst(sorted)
+	return sorted
+}
+
+// comparePinned orders pinned todos before unpinned ones
+func comparePinned(a, b Todo) int {
+	switch {
+	case a.Pinned == b.Pinned:
+		return 0
+	case a.Pinned:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// FoldCase prepares a string for comparison, lowercasing it unless caseSensitive is set.
+// All text matching goes through it so case handling stays consistent.
+func FoldCase(s string, caseSensitive bool) string {
+	if caseSensitive {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
 // TodoStorage represents the storage structure for file-based persistence
 type TodoStorage struct {
 	Todos  []Todo `json:"todos"`
 	NextID int    `json:"next_id"`
+	// Views holds the saved list filters, in creation order
+	Views []View `json:"views,omitempty"`
+
+	// Now supplies update timestamps; nil uses time.Now
+	Now func() time.Time `json:"-"`
+	// MonotonicUpdatedAt keeps each todo's UpdatedAt increasing even if the clock goes backwards
+	MonotonicUpdatedAt bool `json:"-"`
+
+	// idMutex guards NextID so IDs stay unique even when callers don't hold an outer lock
+	idMutex sync.Mutex
 }
 
 // NewTodoStorage creates a new TodoStorage instance with initial values
 func NewTodoStorage() *TodoStorage {
+	return NewTodoStorageWithStartID(1)
+}
+
+// NewTodoStorageWithStartID creates a new TodoStorage whose first assigned ID is startID
+func NewTodoStorageWithStartID(startID int) *TodoStorage {
+	if startID < 1 {
+		startID = 1
+	}
 	return &TodoStorage{
 		Todos:  make([]Todo, 0),
-		NextID: 1,
+		NextID: startID,
 	}
 }
 
-// GenerateNextID returns the next available ID and increments the counter
+// GenerateNextID returns the next available ID and increments the counter.
+// It is safe for concurrent use; no two calls ever return the same ID.
 func (ts *TodoStorage) GenerateNextID() int {
+	ts.idMutex.Lock()
+	defer ts.idMutex.Unlock()
+
 	id := ts.NextID
 	ts.NextID++
 	return id
 }
 
-// AddTodo adds a new todo to the storage and assigns it an ID
+// AdvanceNextID raises the counter to at least minNext and returns the resulting value.
+// The counter never moves backwards, so previously issued IDs are not handed out again.
+func (ts *TodoStorage) AdvanceNextID(minNext int) int {
+	ts.idMutex.Lock()
+	defer ts.idMutex.Unlock()
+
+	if ts.NextID < minNext {
+		ts.NextID = minNext
+	}
+	return ts.NextID
+}
+
+// AddTodo adds a new todo to the storage and assigns it an ID. A next_id left behind the
+// stored IDs, as a hand-edited data file may have, is moved past them first so an ID in use
+// is never issued again.
 func (ts *TodoStorage) AddTodo(todo Todo) Todo {
+	ts.AdvanceNextID(ts.MaxID() + 1)
 	todo.ID = ts.GenerateNextID()
 	todo.SetTimestamps()
+	todo.Version = 1
+	todo.CompletedAt = completionTime(&Todo{}, todo)
 	ts.Todos = append(ts.Todos, todo)
 	return todo
 }
 
+// DuplicateIDs returns each ID that more than one stored todo shares, in order of first repeat
+func (ts *TodoStorage) DuplicateIDs() []int {
+	duplicates := make([]int, 0)
+	seen := make(map[int]int)
+	for _, todo := range ts.Todos {
+		seen[todo.ID]++
+		if seen[todo.ID] == 2 {
+			duplicates = append(duplicates, todo.ID)
+		}
+	}
+	return duplicates
+}
+
+// MaxID returns the highest ID among the stored todos, or zero when there are none
+func (ts *TodoStorage) MaxID() int {
+	maxID := 0
+	for _, todo := range ts.Todos {
+		if todo.ID > maxID {
+			maxID = todo.ID
+		}
+	}
+	return maxID
+}
+
+// RenumberDuplicateIDs keeps the first todo with each ID and assigns fresh IDs to later
+// todos sharing it. It returns the number of todos renumbered.
+func (ts *TodoStorage) RenumberDuplicateIDs() int {
+	ts.AdvanceNextID(ts.MaxID() + 1)
+
+	renumbered := 0
+	seen := make(map[int]bool)
+	for i := range ts.Todos {
+		if seen[ts.Todos[i].ID] {
+			ts.Todos[i].ID = ts.GenerateNextID()
+			renumbered++
+		}
+		seen[ts.Todos[i].ID] = true
+	}
+	return renumbered
+}
+
+// FutureCreatedIDs returns the IDs of todos created after cutoff, in storage order
+func (ts *TodoStorage) FutureCreatedIDs(cutoff time.Time) []int {
+	ids := make([]int, 0)
+	for _, todo := range ts.Todos {
+		if todo.CreatedAt.After(cutoff) {
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ids
+}
+
+// ClampFutureCreatedAt sets the creation time of todos created after cutoff to now.
+// It returns the number of todos changed.
+func (ts *TodoStorage) ClampFutureCreatedAt(cutoff, now time.Time) int {
+	clamped := 0
+	for i := range ts.Todos {
+		if ts.Todos[i].CreatedAt.After(cutoff) {
+			ts.Todos[i].CreatedAt = now
+			clamped++
+		}
+	}
+	return clamped
+}
+
 // FindTodoByID finds a todo by its ID and returns it with its index
 func (ts *TodoStorage) FindTodoByID(id int) (*Todo, int, error) {
 	for i, todo := range ts.Todos {
@@ -94,38 +579,202 @@ func (ts *TodoStorage) FindTodoByID(id int) (*Todo, int, error) {
 	return nil, -1, errors.New("todo not found")
 }
 
+// ErrVersionConflict is returned when an update expects a version other than the stored one
+var ErrVersionConflict = errors.New("version conflict")
+
 // UpdateTodo updates an existing todo in the storage
 func (ts *TodoStorage) UpdateTodo(id int, updatedTodo Todo) (*Todo, error) {
 	todo, index, err := ts.FindTodoByID(id)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Preserve original creation time and ID
 	updatedTodo.ID = todo.ID
 	updatedTodo.CreatedAt = todo.CreatedAt
-	updatedTodo.UpdatedAt = time.Now()
-	
+	updatedTodo.UpdatedAt = ts.updateTime(todo.UpdatedAt)
+	updatedTodo.Version = todo.Version + 1
+	updatedTodo.CompletedAt = completionTime(todo, updatedTodo)
+
 	ts.Todos[index] = updatedTodo
 	return &ts.Todos[index], nil
 }
 
+// UpdatedAtEpsilon is how far a monotonic UpdatedAt advances past the previous value when
+// the clock has not moved forward; a millisecond survives clients that truncate to milliseconds
+const UpdatedAtEpsilon = time.Millisecond
+
+// updateTime returns the UpdatedAt for a todo last updated at previous. With MonotonicUpdatedAt
+// set, it is never at or before previous, so updated_since style sync doesn't miss changes.
+func (ts *TodoStorage) updateTime(previous time.Time) time.Time {
+	now := time.Now()
+	if ts.Now != nil {
+		now = ts.Now()
+	}
+	if ts.MonotonicUpdatedAt && !now.After(previous) {
+		return previous.Add(UpdatedAtEpsilon)
+	}
+	return now
+}
+
+// completionTime tracks when a todo was completed: it is stamped when the todo becomes
+// completed, kept while it stays completed, and cleared when it is reopened
+func completionTime(before *Todo, after Todo) *time.Time {
+	switch {
+	case !after.Completed:
+		return nil
+	case before.Completed && before.CompletedAt != nil:
+		return before.CompletedAt
+	default:
+		completedAt := after.UpdatedAt
+		return &completedAt
+	}
+}
+
 // DeleteTodo removes a todo from the storage by ID
 func (ts *TodoStorage) DeleteTodo(id int) error {
 	_, index, err := ts.FindTodoByID(id)
 	if err != nil {
 		return err
 	}
-	
+
 	// Remove todo from slice
 	ts.Todos = append(ts.Todos[:index], ts.Todos[index+1:]...)
 	return nil
 }
 
+// Purge fields select which timestamp PurgeBefore compares with the cutoff
+const (
+	PurgeFieldCreated   = "created_at"
+	PurgeFieldCompleted = "completed_at"
+)
+
+// ValidatePurgeField reports whether field is a timestamp todos can be purged by
+func ValidatePurgeField(field string) error {
+	if field != PurgeFieldCreated && field != PurgeFieldCompleted {
+		return fmt.Errorf("field must be %q or %q", PurgeFieldCreated, PurgeFieldCompleted)
+	}
+	return nil
+}
+
+// PurgeBefore removes the todos whose field timestamp is before cutoff and returns them.
+// Open todos have no completion time, so purging by completed_at never removes them.
+func (ts *TodoStorage) PurgeBefore(cutoff time.Time, field string) ([]Todo, error) {
+	if err := ValidatePurgeField(field); err != nil {
+		return nil, err
+	}
+
+	kept := make([]Todo, 0, len(ts.Todos))
+	purged := make([]Todo, 0)
+	for _, todo := range ts.Todos {
+		at := &todo.CreatedAt
+		if field == PurgeFieldCompleted {
+			at = todo.CompletedAt
+		}
+		if at != nil && at.Before(cutoff) {
+			purged = append(purged, todo)
+			continue
+		}
+		kept = append(kept, todo)
+	}
+	ts.Todos = kept
+	return purged, nil
+}
+
+// TakeCompletedBy removes the todos completed at or before cutoff and returns them
+func (ts *TodoStorage) TakeCompletedBy(cutoff time.Time) []Todo {
+	kept := make([]Todo, 0, len(ts.Todos))
+	taken := make([]Todo, 0)
+	for _, todo := range ts.Todos {
+		if todo.Completed && todo.CompletedAt != nil && !todo.CompletedAt.After(cutoff) {
+			taken = append(taken, todo)
+			continue
+		}
+		kept = append(kept, todo)
+	}
+	ts.Todos = kept
+	return taken
+}
+
+// TakeExpiredBy removes the todos whose expiry is at or before now and returns them
+func (ts *TodoStorage) TakeExpiredBy(now time.Time) []Todo {
+	kept := make([]Todo, 0, len(ts.Todos))
+	taken := make([]Todo, 0)
+	for _, todo := range ts.Todos {
+		if todo.ExpiresAt != nil && !todo.ExpiresAt.After(now) {
+			taken = append(taken, todo)
+			continue
+		}
+		kept = append(kept, todo)
+	}
+	ts.Todos = kept
+	return taken
+}
+
+// CountTodos returns the number of todos matching the given filter without copying them
+func (ts *TodoStorage) CountTodos(filter ListFilter) int {
+	count := 0
+	for i := range ts.Todos {
+		if filter.Matches(&ts.Todos[i]) {
+			count++
+		}
+	}
+	return count
+}
+
 // GetAllTodos returns a copy of all todos in the storage
 func (ts *TodoStorage) GetAllTodos() []Todo {
 	// Return a copy to prevent external modification
 	todos := make([]Todo, len(ts.Todos))
 	copy(todos, ts.Todos)
 	return todos
-}
\ No newline at end of file
+}
+
+// InvalidTodo identifies a stored todo that fails validation
+type InvalidTodo struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
+}
+
+// ConsistencyReport describes integrity problems found in the stored data
+type ConsistencyReport struct {
+	Consistent        bool          `json:"consistent"`
+	TodoCount         int           `json:"todo_count"`
+	NextID            int           `json:"next_id"`
+	DuplicateIDs      []int         `json:"duplicate_ids"`
+	IDsNotBelowNextID []int         `json:"ids_not_below_next_id"`
+	InvalidTodos      []InvalidTodo `json:"invalid_todos"`
+	// Warnings flags operational concerns, such as a stale data file, that don't make the data inconsistent
+	Warnings []string `json:"warnings"`
+}
+
+// CheckConsistency inspects the storage for duplicate IDs, IDs that the ID counter
+// could hand out again, and todos failing validation. It never modifies the storage.
+func (ts *TodoStorage) CheckConsistency(maxDescriptionLength int) *ConsistencyReport {
+	report := &ConsistencyReport{
+		TodoCount:         len(ts.Todos),
+		NextID:            ts.NextID,
+		DuplicateIDs:      make([]int, 0),
+		IDsNotBelowNextID: make([]int, 0),
+		InvalidTodos:      make([]InvalidTodo, 0),
+		Warnings:          make([]string, 0),
+	}
+
+	report.DuplicateIDs = ts.DuplicateIDs()
+	for i := range ts.Todos {
+		todo := &ts.Todos[i]
+
+		if todo.ID >= ts.NextID {
+			report.IDsNotBelowNextID = append(report.IDsNotBelowNextID, todo.ID)
+		}
+
+		if err := todo.ValidateWithMaxDescription(maxDescriptionLength); err != nil {
+			report.InvalidTodos = append(report.InvalidTodos, InvalidTodo{ID: todo.ID, Error: err.Error()})
+		}
+	}
+
+	report.Consistent = len(report.DuplicateIDs) == 0 &&
+		len(report.IDsNotBelowNextID) == 0 &&
+		len(report.InvalidTodos) == 0
+	return report
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// View is a named set of list filters saved so they can be applied with ?view=name
+type View struct {
+	Name        string `json:"name"`
+	Completed   *bool  `json:"completed,omitempty"`
+	TitlePrefix string `json:"title_prefix,omitempty"`
+	TitleSuffix string `json:"title_suffix,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Query       string `json:"q,omitempty"`
+}
+
+// viewNamePattern limits view names to characters that are safe in a URL path and query
+var viewNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Validate validates the view name and filter criteria
+func (v *View) Validate() error {
+	if !viewNamePattern.MatchString(v.Name) {
+		return errors.New("view name must be 1 to 64 letters, digits, hyphens or underscores")
+	}
+	if v.Source != "" {
+		if err := ValidateSource(v.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filter returns the list filter the view applies
+func (v View) Filter() ListFilter {
+	return ListFilter{
+		Completed:   v.Completed,
+		TitlePrefix: v.TitlePrefix,
+		TitleSuffix: v.TitleSuffix,
+		Source:      v.Source,
+		Query:       v.Query,
+	}
+}
+
+// FindView finds a saved view by name
+func (ts *TodoStorage) FindView(name string) (*View, int, error) {
+	for i, view := range ts.Views {
+		if view.Name == name {
+			return &ts.Views[i], i, nil
+		}
+	}
+	return nil, -1, errors.New("view not found")
+}
+
+// AddView saves a new view, failing if one with the same name exists
+func (ts *TodoStorage) AddView(view View) error {
+	if _, _, err := ts.FindView(view.Name); err == nil {
+		return fmt.Errorf("view %q already exists", view.Name)
+	}
+	ts.Views = append(ts.Views, view)
+	return nil
+}
+
+// DeleteView removes a saved view by name
+func (ts *TodoStorage) DeleteView(name string) error {
+	_, index, err := ts.FindView(name)
+	if err != nil {
+		return err
+	}
+	ts.Views = append(ts.Views[:index], ts.Views[index+1:]...)
+	return nil
+}
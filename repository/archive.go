@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-todo-list/models"
+	"os"
+	"time"
+)
+
+// ErrArchiveDisabled is returned by archive operations when no archive file is configured
+var ErrArchiveDisabled = errors.New("archive disabled: no archive file configured")
+
+// archiveStorage is the layout of the archive file
+type archiveStorage struct {
+	Todos []models.Todo `json:"todos"`
+}
+
+// ArchiveCompletedBy moves the todos completed at or before t from the data file to the
+// archive file and returns them. The archive is written first, so a failed save can leave a
+// todo in both files but never in neither.
+func (r *FileBasedTodoRepository) ArchiveCompletedBy(t time.Time) ([]models.Todo, error) {
+	if r.config.ArchiveFilePath == "" {
+		return nil, ErrArchiveDisabled
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.storage.Todos
+	archived := r.storage.TakeCompletedBy(t)
+	if len(archived) == 0 {
+		return archived, nil
+	}
+
+	archive, err := r.readArchive()
+	if err == nil {
+		archive.Todos = append(archive.Todos, archived...)
+		err = r.writeArchive(archive)
+	}
+	if err != nil {
+		r.storage.Todos = previous
+		return nil, fmt.Errorf("failed to archive todos: %w", err)
+	}
+
+	if err := r.saveUnsafe(); err != nil {
+		return nil, fmt.Errorf("failed to save after archiving: %w", err)
+	}
+
+	return archived, nil
+}
+
+// ListArchived returns the archived todos in the order they were archived
+func (r *FileBasedTodoRepository) ListArchived() ([]models.Todo, error) {
+	if r.config.ArchiveFilePath == "" {
+		return nil, ErrArchiveDisabled
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	archive, err := r.readArchive()
+	if err != nil {
+		return nil, err
+	}
+	return archive.Todos, nil
+}
+
+// readArchive loads the archive file; a missing file is an empty archive
+func (r *FileBasedTodoRepository) readArchive() (*archiveStorage, error) {
+	archive := &archiveStorage{Todos: make([]models.Todo, 0)}
+
+	data, err := os.ReadFile(r.config.ArchiveFilePath)
+	if os.IsNotExist(err) {
+		return archive, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal archive: %w", err)
+	}
+	if archive.Todos == nil {
+		archive.Todos = make([]models.Todo, 0)
+	}
+	return archive, nil
+}
+
+// writeArchive replaces the archive file with archive
+func (r *FileBasedTodoRepository) writeArchive(archive *archiveStorage) error {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive: %w", err)
+	}
+
+	if err := r.ensureDataDir(r.config.ArchiveFilePath); err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.config.ArchiveFilePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"go-crud-todo-list/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveCompletedBy(t *testing.T) {
+	done := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	filePath := createTempFile(t)
+	data, err := json.Marshal(models.TodoStorage{
+		Todos: []models.Todo{
+			{ID: 1, Title: "Open", CreatedAt: done, UpdatedAt: done},
+			{ID: 2, Title: "Done", Completed: true, CreatedAt: done, UpdatedAt: done, CompletedAt: &done},
+		},
+		NextID: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal storage: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	config := DefaultRepositoryConfig()
+	config.ArchiveFilePath = filepath.Join(t.TempDir(), "archive", "archive.json")
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	archived, err := repo.ArchiveCompletedBy(done.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("Expected nothing archived before the completion time, got %v", archived)
+	}
+
+	// The cutoff is inclusive, so archiving as of the completion time moves the todo
+	archived, err = repo.ArchiveCompletedBy(done)
+	if err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != 2 {
+		t.Fatalf("Expected todo 2 archived, got %v", archived)
+	}
+
+	// Both files are persisted
+	reloaded := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	todos, _ := reloaded.GetAll()
+	if len(todos) != 1 || todos[0].ID != 1 {
+		t.Errorf("Expected only the open todo to remain, got %v", todos)
+	}
+	stored, err := reloaded.ListArchived()
+	if err != nil {
+		t.Fatalf("Failed to list archive: %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != 2 || stored[0].Title != "Done" {
+		t.Errorf("Expected the archived todo in the archive file, got %v", stored)
+	}
+}
+
+func TestArchiveCompletedBy_Disabled(t *testing.T) {
+	repo := NewFileBasedTodoRepository(createTempFile(t))
+
+	if _, err := repo.ArchiveCompletedBy(time.Now()); !errors.Is(err, ErrArchiveDisabled) {
+		t.Errorf("Expected ErrArchiveDisabled, got %v", err)
+	}
+	if _, err := repo.ListArchived(); !errors.Is(err, ErrArchiveDisabled) {
+		t.Errorf("Expected ErrArchiveDisabled, got %v", err)
+	}
+}
+
+func TestArchiveCompletedBy_WriteFailureKeepsTodos(t *testing.T) {
+	filePath := createTempFile(t)
+	config := DefaultRepositoryConfig()
+	// A directory where the archive file should be makes the archive write fail
+	config.ArchiveFilePath = t.TempDir()
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	todo := &models.Todo{Title: "Done", Completed: true}
+	if err := repo.Create(todo); err != nil {
+		t.Fatalf("Failed to create: %v", err)
+	}
+
+	if _, err := repo.ArchiveCompletedBy(time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("Expected the archive write to fail")
+	}
+
+	todos, _ := repo.GetAll()
+	if len(todos) != 1 {
+		t.Errorf("Expected the todo to stay in the data file, got %v", todos)
+	}
+}
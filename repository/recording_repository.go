@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-todo-list/models"
+	"io"
+	"sync"
+	"time"
+)
+
+// RecordedCall is one repository method call as written by RecordingRepository
+type RecordedCall struct {
+	Method string                  `json:"method"`
+	ID     int                     `json:"id,omitempty"`
+	Todo   *models.Todo            `json:"todo,omitempty"`
+	Todos  []models.Todo           `json:"todos,omitempty"`
+	Items  []models.BulkUpdateItem `json:"items,omitempty"`
+	IDs    []int                   `json:"ids,omitempty"`
+	Add    []string                `json:"add,omitempty"`
+	Remove []string                `json:"remove,omitempty"`
+	Filter *models.ListFilter      `json:"filter,omitempty"`
+	Name   string                  `json:"name,omitempty"`
+	View   *models.View            `json:"view,omitempty"`
+	Before *time.Time              `json:"before,omitempty"`
+	Field  string                  `json:"field,omitempty"`
+	// Version is the version UpdateIfVersion expected
+	Version int `json:"version,omitempty"`
+	// Rejected holds the batch validator's verdicts by todo ID, so a replay makes the same decisions
+	Rejected map[int]string `json:"rejected,omitempty"`
+	// Error is the error the call returned, if any
+	Error string `json:"error,omitempty"`
+}
+
+// RecordingRepository wraps a TodoRepository and writes every method call, with its
+// arguments and outcome, as a JSON line so the sequence can be replayed with Replay
+type RecordingRepository struct {
+	TodoRepository
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewRecordingRepository creates a RecordingRepository that records calls on repo to w
+func NewRecordingRepository(repo TodoRepository, w io.Writer) *RecordingRepository {
+	return &RecordingRepository{TodoRepository: repo, encoder: json.NewEncoder(w)}
+}
+
+// record writes a call and its error; recording failures never affect the wrapped call
+func (r *RecordingRepository) record(call RecordedCall, err error) {
+	if err != nil {
+		call.Error = err.Error()
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.encoder.Encode(call)
+}
+
+// recordingValidator wraps validate, noting each rejection in rejected
+func recordingValidator(validate BatchValidator, rejected map[int]string, mutex *sync.Mutex) BatchValidator {
+	if validate == nil {
+		return nil
+	}
+	return func(existing, proposed *models.Todo) error {
+		err := validate(existing, proposed)
+		if err != nil {
+			mutex.Lock()
+			rejected[existing.ID] = err.Error()
+			mutex.Unlock()
+		}
+		return err
+	}
+}
+
+// GetAll records the call and delegates
+func (r *RecordingRepository) GetAll() ([]models.Todo, error) {
+	todos, err := r.TodoRepository.GetAll()
+	r.record(RecordedCall{Method: "GetAll"}, err)
+	return todos, err
+}
+
+// List records the call and delegates
+func (r *RecordingRepository) List(filter models.ListFilter) ([]models.Todo, error) {
+	todos, err := r.TodoRepository.List(filter)
+	r.record(RecordedCall{Method: "List", Filter: &filter}, err)
+	return todos, err
+}
+
+// Count records the call and delegates
+func (r *RecordingRepository) Count(filter models.ListFilter) (int, error) {
+	count, err := r.TodoRepository.Count(filter)
+	r.record(RecordedCall{Method: "Count", Filter: &filter}, err)
+	return count, err
+}
+
+// StreamFiltered records the call and delegates
+func (r *RecordingRepository) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	err := r.TodoRepository.StreamFiltered(w, filter)
+	r.record(RecordedCall{Method: "StreamFiltered", Filter: &filter}, err)
+	return err
+}
+
+// GetByID records the call and delegates
+func (r *RecordingRepository) GetByID(id int) (*models.Todo, error) {
+	todo, err := r.TodoRepository.GetByID(id)
+	r.record(RecordedCall{Method: "GetByID", ID: id}, err)
+	return todo, err
+}
+
+// Create records the todo as passed in, before the repository assigns its ID, and delegates
+func (r *RecordingRepository) Create(todo *models.Todo) error {
+	var input *models.Todo
+	if todo != nil {
+		copied := *todo
+		input = &copied
+	}
+	err := r.TodoRepository.Create(todo)
+	r.record(RecordedCall{Method: "Create", Todo: input}, err)
+	return err
+}
+
+// CreateBatch records the call and delegates
+func (r *RecordingRepository) CreateBatch(todos []*models.Todo) error {
+	inputs := make([]models.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if todo != nil {
+			inputs = append(inputs, *todo)
+		}
+	}
+	err := r.TodoRepository.CreateBatch(todos)
+	r.record(RecordedCall{Method: "CreateBatch", Todos: inputs}, err)
+	return err
+}
+
+// Update records the call and delegates
+func (r *RecordingRepository) Update(id int, todo *models.Todo) error {
+	var input *models.Todo
+	if todo != nil {
+		copied := *todo
+		input = &copied
+	}
+	err := r.TodoRepository.Update(id, todo)
+	r.record(RecordedCall{Method: "Update", ID: id, Todo: input}, err)
+	return err
+}
+
+// UpdateIfVersion records the call and delegates
+func (r *RecordingRepository) UpdateIfVersion(id, version int, todo *models.Todo) error {
+	var input *models.Todo
+	if todo != nil {
+		copied := *todo
+		input = &copied
+	}
+	err := r.TodoRepository.UpdateIfVersion(id, version, todo)
+	r.record(RecordedCall{Method: "UpdateIfVersion", ID: id, Version: version, Todo: input}, err)
+	return err
+}
+
+// UpdateBatch records the call, including the validator's rejections, and delegates
+func (r *RecordingRepository) UpdateBatch(items []models.BulkUpdateItem, validate BatchValidator) ([]models.BulkUpdateResult, error) {
+	var mutex sync.Mutex
+	rejected := make(map[int]string)
+	results, err := r.TodoRepository.UpdateBatch(items, recordingValidator(validate, rejected, &mutex))
+	r.record(RecordedCall{Method: "UpdateBatch", Items: items, Rejected: rejected}, err)
+	return results, err
+}
+
+// UpdateTagsBatch records the call, including the validator's rejections, and delegates
+func (r *RecordingRepository) UpdateTagsBatch(ids []int, add, remove []string, validate BatchValidator) (*models.TagBatchResult, error) {
+	var mutex sync.Mutex
+	rejected := make(map[int]string)
+	result, err := r.TodoRepository.UpdateTagsBatch(ids, add, remove, recordingValidator(validate, rejected, &mutex))
+	r.record(RecordedCall{Method: "UpdateTagsBatch", IDs: ids, Add: add, Remove: remove, Rejected: rejected}, err)
+	return result, err
+}
+
+// Delete records the call and delegates
+func (r *RecordingRepository) Delete(id int) error {
+	err := r.TodoRepository.Delete(id)
+	r.record(RecordedCall{Method: "Delete", ID: id}, err)
+	return err
+}
+
+// CheckConsistency records the call and delegates
+func (r *RecordingRepository) CheckConsistency() (*models.ConsistencyReport, error) {
+	report, err := r.TodoRepository.CheckConsistency()
+	r.record(RecordedCall{Method: "CheckConsistency"}, err)
+	return report, err
+}
+
+// PurgeBefore records the call and delegates
+func (r *RecordingRepository) PurgeBefore(t time.Time, field string) ([]models.Todo, error) {
+	purged, err := r.TodoRepository.PurgeBefore(t, field)
+	r.record(RecordedCall{Method: "PurgeBefore", Before: &t, Field: field}, err)
+	return purged, err
+}
+
+// DeleteExpiredBy records the call and delegates
+func (r *RecordingRepository) DeleteExpiredBy(now time.Time) ([]models.Todo, error) {
+	expired, err := r.TodoRepository.DeleteExpiredBy(now)
+	r.record(RecordedCall{Method: "DeleteExpiredBy", Before: &now}, err)
+	return expired, err
+}
+
+// ArchiveCompletedBy records the call and delegates
+func (r *RecordingRepository) ArchiveCompletedBy(t time.Time) ([]models.Todo, error) {
+	archived, err := r.TodoRepository.ArchiveCompletedBy(t)
+	r.record(RecordedCall{Method: "ArchiveCompletedBy", Before: &t}, err)
+	return archived, err
+}
+
+// ListArchived records the call and delegates
+func (r *RecordingRepository) ListArchived() ([]models.Todo, error) {
+	todos, err := r.TodoRepository.ListArchived()
+	r.record(RecordedCall{Method: "ListArchived"}, err)
+	return todos, err
+}
+
+// VerifyRoundTrip records the call and delegates
+func (r *RecordingRepository) VerifyRoundTrip() error {
+	err := r.TodoRepository.VerifyRoundTrip()
+	r.record(RecordedCall{Method: "VerifyRoundTrip"}, err)
+	return err
+}
+
+// ListViews records the call and delegates
+func (r *RecordingRepository) ListViews() ([]models.View, error) {
+	views, err := r.TodoRepository.ListViews()
+	r.record(RecordedCall{Method: "ListViews"}, err)
+	return views, err
+}
+
+// GetView records the call and delegates
+func (r *RecordingRepository) GetView(name string) (*models.View, error) {
+	view, err := r.TodoRepository.GetView(name)
+	r.record(RecordedCall{Method: "GetView", Name: name}, err)
+	return view, err
+}
+
+// CreateView records the call and delegates
+func (r *RecordingRepository) CreateView(view *models.View) error {
+	var input *models.View
+	if view != nil {
+		copied := *view
+		input = &copied
+	}
+	err := r.TodoRepository.CreateView(view)
+	r.record(RecordedCall{Method: "CreateView", View: input}, err)
+	return err
+}
+
+// DeleteView records the call and delegates
+func (r *RecordingRepository) DeleteView(name string) error {
+	err := r.TodoRepository.DeleteView(name)
+	r.record(RecordedCall{Method: "DeleteView", Name: name}, err)
+	return err
+}
+
+// Save records the call and delegates
+func (r *RecordingRepository) Save() error {
+	err := r.TodoRepository.Save()
+	r.record(RecordedCall{Method: "Save"}, err)
+	return err
+}
+
+// Load records the call and delegates
+func (r *RecordingRepository) Load() error {
+	err := r.TodoRepository.Load()
+	r.record(RecordedCall{Method: "Load"}, err)
+	return err
+}
+
+// replayValidator repeats the recorded batch validator verdicts
+func replayValidator(rejected map[int]string) BatchValidator {
+	return func(existing, proposed *models.Todo) error {
+		if message, ok := rejected[existing.ID]; ok {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// Replay re-issues the calls recorded by a RecordingRepository against repo, in order.
+// It stops at the first call whose outcome differs from the recording: one that failed
+// only on replay or only when recorded.
+func Replay(r io.Reader, repo TodoRepository) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		var call RecordedCall
+		if err := json.Unmarshal(scanner.Bytes(), &call); err != nil {
+			return fmt.Errorf("line %d: failed to decode call: %w", line, err)
+		}
+
+		var err error
+		filter := models.ListFilter{}
+		if call.Filter != nil {
+			filter = *call.Filter
+		}
+		switch call.Method {
+		case "GetAll":
+			_, err = repo.GetAll()
+		case "List":
+			_, err = repo.List(filter)
+		case "Count":
+			_, err = repo.Count(filter)
+		case "StreamFiltered":
+			err = repo.StreamFiltered(io.Discard, filter)
+		case "GetByID":
+			_, err = repo.GetByID(call.ID)
+		case "Create":
+			err = repo.Create(call.Todo)
+		case "CreateBatch":
+			todos := make([]*models.Todo, len(call.Todos))
+			for i := range call.Todos {
+				todos[i] = &call.Todos[i]
+			}
+			err = repo.CreateBatch(todos)
+		case "Update":
+			err = repo.Update(call.ID, call.Todo)
+		case "UpdateIfVersion":
+			err = repo.UpdateIfVersion(call.ID, call.Version, call.Todo)
+		case "UpdateBatch":
+			_, err = repo.UpdateBatch(call.Items, replayValidator(call.Rejected))
+		case "UpdateTagsBatch":
+			_, err = repo.UpdateTagsBatch(call.IDs, call.Add, call.Remove, replayValidator(call.Rejected))
+		case "Delete":
+			err = repo.Delete(call.ID)
+		case "PurgeBefore":
+			var before time.Time
+			if call.Before != nil {
+				before = *call.Before
+			}
+			_, err = repo.PurgeBefore(before, call.Field)
+		case "DeleteExpiredBy":
+			var before time.Time
+			if call.Before != nil {
+				before = *call.Before
+			}
+			_, err = repo.DeleteExpiredBy(before)
+		case "ArchiveCompletedBy":
+			var before time.Time
+			if call.Before != nil {
+				before = *call.Before
+			}
+			_, err = repo.ArchiveCompletedBy(before)
+		case "ListArchived":
+			_, err = repo.ListArchived()
+		case "CheckConsistency":
+			_, err = repo.CheckConsistency()
+		case "VerifyRoundTrip":
+			err = repo.VerifyRoundTrip()
+		case "ListViews":
+			_, err = repo.ListViews()
+		case "GetView":
+			_, err = repo.GetView(call.Name)
+		case "CreateView":
+			err = repo.CreateView(call.View)
+		case "DeleteView":
+			err = repo.DeleteView(call.Name)
+		case "Save":
+			err = repo.Save()
+		case "Load":
+			err = repo.Load()
+		default:
+			return fmt.Errorf("line %d: unknown method %q", line, call.Method)
+		}
+
+		if (err != nil) != (call.Error != "") {
+			return fmt.Errorf("line %d: %s diverged from the recording: recorded error %q, replay error %v",
+				line, call.Method, call.Error, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	return nil
+}
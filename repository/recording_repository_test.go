@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"go-crud-todo-list/models"
+	"strings"
+	"testing"
+)
+
+// stateOf returns the stored todos, failing the test on error
+func stateOf(t *testing.T, repo TodoRepository) []models.Todo {
+	todos, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("Failed to get todos: %v", err)
+	}
+	return todos
+}
+
+func TestRecordingRepository_Replay(t *testing.T) {
+	var recording bytes.Buffer
+	original := NewRecordingRepository(NewFileBasedTodoRepository(createTempFile(t)), &recording)
+	
+	for _, title := range []string{"First", "Second", "Third"} {
+		todo := models.Todo{Title: title}
+		if err := original.Create(&todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+	}
+	original.Update(1, &models.Todo{Title: "First, renamed", Completed: true})
+	original.Delete(2)
+	original.Delete(99)
+	original.UpdateBatch([]models.BulkUpdateItem{
+		{ID: 1, Title: "Batch one"},
+		{ID: 3, Title: "Batch three"},
+	}, func(existing, proposed *models.Todo) error {
+		if existing.ID == 3 {
+			return errors.New("rejected by policy")
+		}
+		return nil
+	})
+	original.UpdateTagsBatch([]int{1, 3}, []string{"work"}, nil, nil)
+	original.List(models.ListFilter{TitlePrefix: "batch"})
+	
+	if lines := strings.Count(recording.String(), "\n"); lines != 9 {
+		t.Errorf("Expected 9 recorded calls, got %d:\n%s", lines, recording.String())
+	}
+	
+	replayed := NewFileBasedTodoRepository(createTempFile(t))
+	if err := Replay(bytes.NewReader(recording.Bytes()), replayed); err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	
+	want, got := stateOf(t, original), stateOf(t, replayed)
+	if len(want) != len(got) {
+		t.Fatalf("Expected %d todos after replay, got %d", len(want), len(got))
+	}
+	// Timestamps differ between runs; everything else must match
+	for i := range want {
+		if want[i].ID != got[i].ID || want[i].Title != got[i].Title || want[i].Completed != got[i].Completed ||
+			want[i].Version != got[i].Version || strings.Join(want[i].Tags, ",") != strings.Join(got[i].Tags, ",") {
+			t.Errorf("Todo %d differs after replay: want %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if got[1].Title != "Third" {
+		t.Errorf("Expected the recorded rejection to be replayed, got title %q", got[1].Title)
+	}
+}
+
+func TestReplay_Divergence(t *testing.T) {
+	recording := `{"method":"Delete","id":1}` + "\n"
+	err := Replay(strings.NewReader(recording), NewFileBasedTodoRepository(createTempFile(t)))
+	if err == nil || !strings.Contains(err.Error(), "diverged") {
+		t.Errorf("Expected a divergence error, got %v", err)
+	}
+	
+	err = Replay(strings.NewReader(`{"method":"Truncate"}`+"\n"), NewFileBasedTodoRepository(createTempFile(t)))
+	if err == nil || !strings.Contains(err.Error(), "unknown method") {
+		t.Errorf("Expected an unknown method error, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"go-crud-todo-list/models"
+)
+
+// Data file formats the repository can persist to
+const (
+	// StorageFormatJSON writes indented, human-readable JSON
+	StorageFormatJSON = "json"
+	// StorageFormatGob writes encoding/gob, which is smaller and faster for large datasets
+	StorageFormatGob = "gob"
+)
+
+// ValidateStorageFormat checks that format names a supported data file format
+func ValidateStorageFormat(format string) error {
+	switch format {
+	case StorageFormatJSON, StorageFormatGob:
+		return nil
+	}
+	return fmt.Errorf("unsupported storage format %q (supported: %s, %s)", format, StorageFormatJSON, StorageFormatGob)
+}
+
+// detectStorageFormat identifies the format of a data file from its contents: JSON data
+// files are objects, so anything not starting with '{' is taken to be gob
+func detectStorageFormat(data []byte) string {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return StorageFormatJSON
+	}
+	return StorageFormatGob
+}
+
+// encodeStorage serializes storage in the given format
+func encodeStorage(storage *models.TodoStorage, format string) ([]byte, error) {
+	if format == StorageFormatGob {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(storage); err != nil {
+			return nil, fmt.Errorf("failed to encode gob: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := json.MarshalIndent(storage, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return data, nil
+}
+
+// decodeStorage deserializes data in the given format into storage
+func decodeStorage(data []byte, format string, storage *models.TodoStorage) error {
+	if format == StorageFormatGob {
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(storage); err != nil {
+			return fmt.Errorf("failed to decode gob: %w", err)
+		}
+		// gob omits empty slices, so restore the empty list JSON would have produced
+		if storage.Todos == nil {
+			storage.Todos = make([]models.Todo, 0)
+		}
+		return nil
+	}
+
+	if err := json.Unmarshal(data, storage); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return nil
+}
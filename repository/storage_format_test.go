@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"bytes"
+	"go-crud-todo-list/models"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestStorageFormat_GobRoundTrip tests that todos and views survive a save and load in gob
+func TestStorageFormat_GobRoundTrip(t *testing.T) {
+	filePath := createTempFile(t)
+	config := DefaultRepositoryConfig()
+	config.StorageFormat = StorageFormatGob
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	due := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	todo := &models.Todo{Title: "Gob", Description: "Binary", DueDate: &due, Tags: []string{"fast"}}
+	if err := repo.Create(todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	if err := repo.CreateView(&models.View{Name: "fast", Query: "gob"}); err != nil {
+		t.Fatalf("Failed to create view: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if detectStorageFormat(data) != StorageFormatGob {
+		t.Fatalf("Expected data file to be written as gob, got %q", data)
+	}
+
+	reloaded := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	got, err := reloaded.GetByID(todo.ID)
+	if err != nil {
+		t.Fatalf("Expected todo to survive a reload: %v", err)
+	}
+	if got.Title != "Gob" || got.Description != "Binary" || got.DueDate == nil || !got.DueDate.Equal(due) ||
+		len(got.Tags) != 1 || !got.CreatedAt.Equal(todo.CreatedAt) || got.Version != todo.Version {
+		t.Errorf("Expected %+v after round trip, got %+v", todo, got)
+	}
+	if _, err := reloaded.GetView("fast"); err != nil {
+		t.Errorf("Expected view to survive a reload: %v", err)
+	}
+
+	next := &models.Todo{Title: "Next"}
+	if err := reloaded.Create(next); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	if next.ID != todo.ID+1 {
+		t.Errorf("Expected next_id to survive a reload, got ID %d", next.ID)
+	}
+}
+
+// TestStorageFormat_MigrateJSONToGob tests that a JSON data file is rewritten as gob on load
+func TestStorageFormat_MigrateJSONToGob(t *testing.T) {
+	filePath := createTempFile(t)
+	data := `{
+  "todos": [
+    {"id": 1, "title": "From JSON", "completed": true}
+  ],
+  "next_id": 2
+}`
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to seed data file: %v", err)
+	}
+
+	config := DefaultRepositoryConfig()
+	config.StorageFormat = StorageFormatGob
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	migrated, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if detectStorageFormat(migrated) != StorageFormatGob {
+		t.Fatalf("Expected data file to be migrated to gob")
+	}
+
+	todo, err := repo.GetByID(1)
+	if err != nil || todo.Title != "From JSON" || !todo.Completed {
+		t.Errorf("Expected migrated todo, got %+v (err %v)", todo, err)
+	}
+
+	// Switching back to JSON migrates the gob file again
+	jsonRepo := NewFileBasedTodoRepository(filePath)
+	if err := jsonRepo.Load(); err != nil {
+		t.Fatalf("Failed to load gob file as JSON repository: %v", err)
+	}
+	restored, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if !bytes.Contains(restored, []byte(`"title": "From JSON"`)) {
+		t.Errorf("Expected data file to be migrated back to JSON, got %q", restored)
+	}
+}
@@ -2,97 +2,386 @@ package repository
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-crud-todo-list/models"
+	"io"
+	"io/fs"
+	"log"
 	"os"
+	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 )
 
 // TodoRepository defines the interface for todo data persistence operations
 type TodoRepository interface {
 	GetAll() ([]models.Todo, error)
+	List(filter models.ListFilter) ([]models.Todo, error)
+	Count(filter models.ListFilter) (int, error)
+	StreamFiltered(w io.Writer, filter models.ListFilter) error
 	GetByID(id int) (*models.Todo, error)
 	Create(todo *models.Todo) error
+	CreateBatch(todos []*models.Todo) error
 	Update(id int, todo *models.Todo) error
+	UpdateIfVersion(id, version int, todo *models.Todo) error
+	UpdateBatch(items []models.BulkUpdateItem, validate BatchValidator) ([]models.BulkUpdateResult, error)
+	UpdateTagsBatch(ids []int, add, remove []string, validate BatchValidator) (*models.TagBatchResult, error)
 	Delete(id int) error
+	PurgeBefore(t time.Time, field string) ([]models.Todo, error)
+	DeleteExpiredBy(now time.Time) ([]models.Todo, error)
+	ArchiveCompletedBy(t time.Time) ([]models.Todo, error)
+	ListArchived() ([]models.Todo, error)
+	CheckConsistency() (*models.ConsistencyReport, error)
+	VerifyRoundTrip() error
+	ListViews() ([]models.View, error)
+	GetView(name string) (*models.View, error)
+	CreateView(view *models.View) error
+	DeleteView(name string) error
 	Save() error
 	Load() error
 }
 
+// RepositoryConfig holds tunable options for the file-based repository
+type RepositoryConfig struct {
+	// IDStart is the first ID assigned when the data file is new or empty
+	IDStart int
+	// MaxDescriptionLength limits todo descriptions; zero uses models.DefaultMaxDescriptionLength
+	MaxDescriptionLength int
+	// DirMode is the permission mode for data file directories created on save; zero uses DefaultDirMode
+	DirMode os.FileMode
+	// RejectSymlinks treats a data file path that is a symlink as an error instead of
+	// reading and writing through to the link target
+	RejectSymlinks bool
+	// DuplicateIDPolicy decides how Load handles todos sharing an ID; empty uses DuplicateIDsAllow
+	DuplicateIDPolicy string
+	// MaxDataFileAge is how long the data file may go unmodified before the consistency
+	// check warns about it, which can indicate a stuck writer; zero disables the warning
+	MaxDataFileAge time.Duration
+	// MonotonicUpdatedAt keeps each todo's UpdatedAt strictly increasing across updates, even
+	// when the clock goes backwards
+	MonotonicUpdatedAt bool
+	// Now supplies update timestamps and the time checked against on load; nil uses time.Now
+	Now func() time.Time
+	// StorageFormat is the data file format written on save; empty uses StorageFormatJSON.
+	// Files in the other format are detected on load and migrated.
+	StorageFormat string
+	// FutureCreatedAtPolicy decides how Load handles todos created more than CreatedAtSkew
+	// in the future; empty uses FutureCreatedAtAllow
+	FutureCreatedAtPolicy string
+	// RecreateDataDir recreates the data file's directory when a save finds it missing; turn
+	// it off when the directory is a mount point, so a detached volume fails saves instead
+	// of writing to the underlying disk
+	RecreateDataDir bool
+	// ArchiveFilePath is the JSON file completed todos are moved to when archived; empty
+	// disables archiving
+	ArchiveFilePath string
+}
+
+// Duplicate ID policies applied when loading the data file
+const (
+	// DuplicateIDsAllow loads duplicates unchanged, leaving them to the consistency check
+	DuplicateIDsAllow = "allow"
+	// DuplicateIDsStrict fails the load when two todos share an ID
+	DuplicateIDsStrict = "strict"
+	// DuplicateIDsRenumber keeps the first todo with each ID and gives later duplicates fresh IDs
+	DuplicateIDsRenumber = "renumber"
+)
+
+// Future creation time policies applied when loading the data file
+const (
+	// FutureCreatedAtAllow loads future creation times unchanged
+	FutureCreatedAtAllow = "allow"
+	// FutureCreatedAtReject fails the load when a todo was created in the future
+	FutureCreatedAtReject = "reject"
+	// FutureCreatedAtClamp resets future creation times to the time of loading
+	FutureCreatedAtClamp = "clamp"
+)
+
+// CreatedAtSkew is how far in the future a creation time may be before the future creation
+// time policy applies, allowing for clock differences between machines
+const CreatedAtSkew = 5 * time.Minute
+
+// ValidateFutureCreatedAtPolicy checks that policy names a supported future creation time policy
+func ValidateFutureCreatedAtPolicy(policy string) error {
+	switch policy {
+	case FutureCreatedAtAllow, FutureCreatedAtReject, FutureCreatedAtClamp:
+		return nil
+	}
+	return fmt.Errorf("unsupported future created_at policy %q (supported: %s, %s, %s)",
+		policy, FutureCreatedAtAllow, FutureCreatedAtReject, FutureCreatedAtClamp)
+}
+
+// ValidateDuplicateIDPolicy checks that policy names a supported duplicate ID policy
+func ValidateDuplicateIDPolicy(policy string) error {
+	switch policy {
+	case DuplicateIDsAllow, DuplicateIDsStrict, DuplicateIDsRenumber:
+		return nil
+	}
+	return fmt.Errorf("unsupported duplicate ID policy %q (supported: %s, %s, %s)",
+		policy, DuplicateIDsAllow, DuplicateIDsStrict, DuplicateIDsRenumber)
+}
+
+// DefaultDirMode is the permission mode used when creating missing data file directories
+const DefaultDirMode os.FileMode = 0755
+
+// DefaultRepositoryConfig returns the repository configuration used when none is supplied
+func DefaultRepositoryConfig() RepositoryConfig {
+	return RepositoryConfig{
+		IDStart:              1,
+		MaxDescriptionLength: models.DefaultMaxDescriptionLength,
+		DirMode:              DefaultDirMode,
+		DuplicateIDPolicy:    DuplicateIDsAllow,
+		MonotonicUpdatedAt:   true,
+		StorageFormat:        StorageFormatJSON,
+		RecreateDataDir:      true,
+	}
+}
+
 // FileBasedTodoRepository implements TodoRepository using file-based persistence
 type FileBasedTodoRepository struct {
 	storage  *models.TodoStorage
 	filePath string
+	config   RepositoryConfig
 	mutex    sync.RWMutex
+
+	// snapshot caches the GetAll result until the next mutation; guarded by mutex
+	snapshot      []models.Todo
+	snapshotValid bool
 }
 
 // NewFileBasedTodoRepository creates a new file-based repository instance
 func NewFileBasedTodoRepository(filePath string) *FileBasedTodoRepository {
-	return &FileBasedTodoRepository{
-		storage:  models.NewTodoStorage(),
+	return NewFileBasedTodoRepositoryWithConfig(filePath, DefaultRepositoryConfig())
+}
+
+// NewFileBasedTodoRepositoryWithConfig creates a new file-based repository instance with the given configuration
+func NewFileBasedTodoRepositoryWithConfig(filePath string, config RepositoryConfig) *FileBasedTodoRepository {
+	r := &FileBasedTodoRepository{
 		filePath: filePath,
+		config:   config,
+	}
+	r.storage = r.configureStorage(models.NewTodoStorageWithStartID(config.IDStart))
+	return r
+}
+
+// configureStorage applies the repository's update timestamp settings to storage
+func (r *FileBasedTodoRepository) configureStorage(storage *models.TodoStorage) *models.TodoStorage {
+	storage.Now = r.config.Now
+	storage.MonotonicUpdatedAt = r.config.MonotonicUpdatedAt
+	return storage
+}
+
+// maxDescriptionLength returns the configured description length limit
+func (r *FileBasedTodoRepository) maxDescriptionLength() int {
+	if r.config.MaxDescriptionLength <= 0 {
+		return models.DefaultMaxDescriptionLength
 	}
+	return r.config.MaxDescriptionLength
 }
 
 // Load reads todo data from the JSON file into memory
 func (r *FileBasedTodoRepository) Load() error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
+	r.snapshotValid = false
+
+	filePath, err := r.resolveDataFile()
+	if err != nil {
+		return err
+	}
 
 	// Check if file exists
-	if _, err := os.Stat(r.filePath); os.IsNotExist(err) {
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		// File doesn't exist, start with empty storage
-		r.storage = models.NewTodoStorage()
+		r.storage = r.configureStorage(models.NewTodoStorageWithStartID(r.config.IDStart))
 		return nil
 	}
 
 	// Read file contents
-	data, err := os.ReadFile(r.filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Handle empty file
 	if len(data) == 0 {
-		r.storage = models.NewTodoStorage()
+		r.storage = r.configureStorage(models.NewTodoStorageWithStartID(r.config.IDStart))
 		return nil
 	}
 
-	// Unmarshal JSON data
+	// Decode in whichever format the file was written, which may differ from the configured one
+	format := detectStorageFormat(data)
 	var storage models.TodoStorage
-	if err := json.Unmarshal(data, &storage); err != nil {
-		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	if err := decodeStorage(data, format, &storage); err != nil {
+		return err
 	}
 
-	r.storage = &storage
+	switch r.config.DuplicateIDPolicy {
+	case DuplicateIDsStrict:
+		if duplicates := storage.DuplicateIDs(); len(duplicates) > 0 {
+			return fmt.Errorf("data file contains duplicate todo IDs %v", duplicates)
+		}
+	case DuplicateIDsRenumber:
+		storage.RenumberDuplicateIDs()
+	}
+
+	now := r.now()
+	cutoff := now.Add(CreatedAtSkew)
+	switch r.config.FutureCreatedAtPolicy {
+	case FutureCreatedAtReject:
+		if ids := storage.FutureCreatedIDs(cutoff); len(ids) > 0 {
+			return fmt.Errorf("data file contains todos created in the future %v", ids)
+		}
+	case FutureCreatedAtClamp:
+		storage.ClampFutureCreatedAt(cutoff, now)
+	}
+
+	r.storage = r.configureStorage(&storage)
+
+	// Migrate a file written in another format by rewriting it in the configured one
+	if format != r.storageFormat() {
+		if err := r.saveUnsafe(); err != nil {
+			return fmt.Errorf("failed to migrate data file to %s: %w", r.storageFormat(), err)
+		}
+	}
 	return nil
 }
 
-// Save writes the current todo data to the JSON file
+// now returns the current time from the configured clock
+func (r *FileBasedTodoRepository) now() time.Time {
+	if r.config.Now != nil {
+		return r.config.Now()
+	}
+	return time.Now()
+}
+
+// storageFormat returns the configured data file format
+func (r *FileBasedTodoRepository) storageFormat() string {
+	if r.config.StorageFormat == "" {
+		return StorageFormatJSON
+	}
+	return r.config.StorageFormat
+}
+
+// Save writes the current todo data to the data file
 func (r *FileBasedTodoRepository) Save() error {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	// Marshal storage to JSON
-	data, err := json.MarshalIndent(r.storage, "", "  ")
+	data, err := encodeStorage(r.storage, r.storageFormat())
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return err
 	}
 
-	// Write to file
-	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	return r.writeDataFile(data)
+}
+
+// GetAll returns all todos from the repository. Repeated reads between writes share one
+// cached copy, so callers must treat the returned slice as read-only.
+func (r *FileBasedTodoRepository) GetAll() ([]models.Todo, error) {
+	r.mutex.RLock()
+	if r.snapshotValid {
+		todos := r.snapshot
+		r.mutex.RUnlock()
+		return todos, nil
 	}
+	r.mutex.RUnlock()
 
-	return nil
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Another reader may have rebuilt the snapshot while we waited for the write lock
+	if !r.snapshotValid {
+		r.snapshot = r.storage.GetAllTodos()
+		r.snapshotValid = true
+	}
+	return r.snapshot, nil
 }
 
-// GetAll returns all todos from the repository
-func (r *FileBasedTodoRepository) GetAll() ([]models.Todo, error) {
+// List returns the todos matching the given filter, read from the cached GetAll snapshot.
+// When every todo matches, the snapshot itself is returned, so callers must treat the
+// returned slice as read-only.
+func (r *FileBasedTodoRepository) List(filter models.ListFilter) ([]models.Todo, error) {
+	todos, err := r.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// The snapshot is never modified once built, so it is filtered without the lock
+	for i := range todos {
+		if filter.Matches(&todos[i]) {
+			continue
+		}
+		// Copy only once a todo is left out
+		matches := slices.Clone(todos[:i])
+		for j := i + 1; j < len(todos); j++ {
+			if filter.Matches(&todos[j]) {
+				matches = append(matches, todos[j])
+			}
+		}
+		return matches, nil
+	}
+	return todos, nil
+}
+
+// Count returns the number of todos matching the given filter without materializing them
+func (r *FileBasedTodoRepository) Count(filter models.ListFilter) (int, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	return r.storage.GetAllTodos(), nil
+	return r.storage.CountTodos(filter), nil
+}
+
+// StreamFiltered writes the todos matching the filter to w as a JSON array, encoding one
+// todo at a time under the read lock instead of building the whole result in memory.
+// Pinned todos come first, in the order models.SortPinnedFirst gives a listing.
+func (r *FileBasedTodoRepository) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return fmt.Errorf("failed to write todos: %w", err)
+	}
+
+	// One pass for the pinned todos and one for the rest avoids sorting a copy
+	first := true
+	for _, pinned := range []bool{true, false} {
+		if err := r.streamMatching(w, filter, pinned, &first); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, "]"); err != nil {
+		return fmt.Errorf("failed to write todos: %w", err)
+	}
+	return nil
+}
+
+// streamMatching writes the todos matching the filter whose pinned flag is pinned as JSON
+// array elements, prefixing a comma to all but the first element of the array
+func (r *FileBasedTodoRepository) streamMatching(w io.Writer, filter models.ListFilter, pinned bool, first *bool) error {
+	for i := range r.storage.Todos {
+		todo := &r.storage.Todos[i]
+		if todo.Pinned != pinned || !filter.Matches(todo) {
+			continue
+		}
+
+		data, err := json.Marshal(todo)
+		if err != nil {
+			return fmt.Errorf("failed to marshal todo with ID %d: %w", todo.ID, err)
+		}
+		if !*first {
+			data = append([]byte(","), data...)
+		}
+		*first = false
+
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write todos: %w", err)
+		}
+	}
+	return nil
 }
 
 // GetByID returns a specific todo by its ID
@@ -117,7 +406,7 @@ func (r *FileBasedTodoRepository) Create(todo *models.Todo) error {
 	}
 
 	// Validate the todo
-	if err := todo.Validate(); err != nil {
+	if err := todo.ValidateWithMaxDescription(r.maxDescriptionLength()); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -136,20 +425,69 @@ func (r *FileBasedTodoRepository) Create(todo *models.Todo) error {
 	return nil
 }
 
+// CreateBatch adds every todo under a single lock and saves once, assigning IDs and
+// timestamps in order. The todos are all validated first, and none are kept if the save
+// fails; the IDs they were assigned are not reissued.
+func (r *FileBasedTodoRepository) CreateBatch(todos []*models.Todo) error {
+	for i, todo := range todos {
+		if todo == nil {
+			return fmt.Errorf("todo %d cannot be nil", i)
+		}
+		if err := todo.ValidateWithMaxDescription(r.maxDescriptionLength()); err != nil {
+			return fmt.Errorf("validation failed: todo %d: %w", i, err)
+		}
+	}
+	if len(todos) == 0 {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.storage.Todos
+	created := make([]models.Todo, len(todos))
+	for i, todo := range todos {
+		created[i] = r.storage.AddTodo(*todo)
+	}
+
+	if err := r.saveUnsafe(); err != nil {
+		r.storage.Todos = previous
+		return fmt.Errorf("failed to save todos: %w", err)
+	}
+
+	for i, todo := range todos {
+		*todo = created[i]
+	}
+	return nil
+}
+
 // Update modifies an existing todo in the repository
 func (r *FileBasedTodoRepository) Update(id int, todo *models.Todo) error {
+	return r.UpdateIfVersion(id, 0, todo)
+}
+
+// UpdateIfVersion modifies an existing todo only if its stored version is still version,
+// checked under the same lock as the write; a mismatch returns models.ErrVersionConflict.
+// A zero version skips the check, as in UpdateBatch.
+func (r *FileBasedTodoRepository) UpdateIfVersion(id, version int, todo *models.Todo) error {
 	if todo == nil {
 		return fmt.Errorf("todo cannot be nil")
 	}
 
 	// Validate the todo
-	if err := todo.Validate(); err != nil {
+	if err := todo.ValidateWithMaxDescription(r.maxDescriptionLength()); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if version != 0 {
+		if existing, _, err := r.storage.FindTodoByID(id); err == nil && existing.Version != version {
+			return fmt.Errorf("%w: expected %d, current is %d", models.ErrVersionConflict, version, existing.Version)
+		}
+	}
+
 	// Update todo in storage
 	updatedTodo, err := r.storage.UpdateTodo(id, *todo)
 	if err != nil {
@@ -166,6 +504,122 @@ func (r *FileBasedTodoRepository) Update(id int, todo *models.Todo) error {
 	return nil
 }
 
+// BatchValidator checks a proposed update against the current state of the todo. It may
+// also normalize the proposed todo, which is saved as the validator leaves it.
+type BatchValidator func(existing, proposed *models.Todo) error
+
+// UpdateBatch applies each item as a full replacement under a single lock and saves once.
+// Items whose todo is missing, whose version doesn't match, or that fail validation are
+// skipped and reported individually; the remaining items are applied.
+func (r *FileBasedTodoRepository) UpdateBatch(items []models.BulkUpdateItem, validate BatchValidator) ([]models.BulkUpdateResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	results := make([]models.BulkUpdateResult, 0, len(items))
+	updatedCount := 0
+
+	for _, item := range items {
+		existing, _, err := r.storage.FindTodoByID(item.ID)
+		if err != nil {
+			results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusNotFound, Error: "todo not found"})
+			continue
+		}
+
+		if item.Version != 0 && item.Version != existing.Version {
+			results = append(results, models.BulkUpdateResult{
+				ID:      item.ID,
+				Status:  models.BulkStatusConflict,
+				Version: existing.Version,
+				Error:   fmt.Sprintf("version conflict: expected %d, current is %d", item.Version, existing.Version),
+			})
+			continue
+		}
+
+		// Start from the stored todo so fields outside the item are preserved
+		proposed := *existing
+		proposed.Title = item.Title
+		proposed.Description = item.Description
+		proposed.Completed = item.Completed
+		proposed.DueDate = item.DueDate
+		err = proposed.ValidateWithMaxDescription(r.maxDescriptionLength())
+		if err == nil && validate != nil {
+			err = validate(existing, &proposed)
+		}
+		if err != nil {
+			results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusInvalid, Error: err.Error()})
+			continue
+		}
+
+		updated, err := r.storage.UpdateTodo(item.ID, proposed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update todo with ID %d: %w", item.ID, err)
+		}
+		todoCopy := *updated
+		results = append(results, models.BulkUpdateResult{
+			ID:      item.ID,
+			Status:  models.BulkStatusUpdated,
+			Version: updated.Version,
+			Todo:    &todoCopy,
+		})
+		updatedCount++
+	}
+
+	if updatedCount > 0 {
+		if err := r.saveUnsafe(); err != nil {
+			return nil, fmt.Errorf("failed to save bulk update: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// UpdateTagsBatch adds and removes tags on every listed todo under a single lock and saves once.
+// IDs without a todo are reported as missing; a validation failure aborts the whole batch.
+func (r *FileBasedTodoRepository) UpdateTagsBatch(ids []int, add, remove []string, validate BatchValidator) (*models.TagBatchResult, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := &models.TagBatchResult{
+		Updated:    make([]models.Todo, 0, len(ids)),
+		MissingIDs: make([]int, 0),
+	}
+	proposals := make([]models.Todo, 0, len(ids))
+
+	// Validate every change before touching storage so the batch applies all-or-nothing
+	for _, id := range ids {
+		existing, _, err := r.storage.FindTodoByID(id)
+		if err != nil {
+			result.MissingIDs = append(result.MissingIDs, id)
+			continue
+		}
+
+		proposed := *existing
+		proposed.Tags = models.ApplyTagChanges(existing.Tags, add, remove)
+		if validate != nil {
+			if err := validate(existing, &proposed); err != nil {
+				return nil, fmt.Errorf("todo with ID %d: %w", id, err)
+			}
+		}
+		proposals = append(proposals, proposed)
+	}
+
+	for _, proposed := range proposals {
+		updated, err := r.storage.UpdateTodo(proposed.ID, proposed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update todo with ID %d: %w", proposed.ID, err)
+		}
+		result.Updated = append(result.Updated, *updated)
+	}
+
+	if len(proposals) > 0 {
+		if err := r.saveUnsafe(); err != nil {
+			return nil, fmt.Errorf("failed to save tag update: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
 // Delete removes a todo from the repository
 func (r *FileBasedTodoRepository) Delete(id int) error {
 	r.mutex.Lock()
@@ -184,18 +638,257 @@ func (r *FileBasedTodoRepository) Delete(id int) error {
 	return nil
 }
 
+// PurgeBefore permanently removes the todos whose field ("created_at" or "completed_at")
+// is before t, in a single save, and returns the removed todos
+func (r *FileBasedTodoRepository) PurgeBefore(t time.Time, field string) ([]models.Todo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	purged, err := r.storage.PurgeBefore(t, field)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge todos: %w", err)
+	}
+
+	if len(purged) > 0 {
+		if err := r.saveUnsafe(); err != nil {
+			return nil, fmt.Errorf("failed to save after purge: %w", err)
+		}
+	}
+
+	return purged, nil
+}
+
+// DeleteExpiredBy removes the todos whose expiry is at or before now, in a single save,
+// and returns the removed todos. The expiry is checked under the same lock as the delete,
+// so a todo whose expiry was extended concurrently is kept.
+func (r *FileBasedTodoRepository) DeleteExpiredBy(now time.Time) ([]models.Todo, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.storage.Todos
+	expired := r.storage.TakeExpiredBy(now)
+	if len(expired) == 0 {
+		return expired, nil
+	}
+
+	if err := r.saveUnsafe(); err != nil {
+		r.storage.Todos = previous
+		return nil, fmt.Errorf("failed to save after deleting expired todos: %w", err)
+	}
+
+	return expired, nil
+}
+
+// ListViews returns the saved views in creation order
+func (r *FileBasedTodoRepository) ListViews() ([]models.View, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	views := make([]models.View, len(r.storage.Views))
+	copy(views, r.storage.Views)
+	return views, nil
+}
+
+// GetView returns a saved view by name
+func (r *FileBasedTodoRepository) GetView(name string) (*models.View, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	view, _, err := r.storage.FindView(name)
+	if err != nil {
+		return nil, fmt.Errorf("view %q not found", name)
+	}
+
+	viewCopy := *view
+	return &viewCopy, nil
+}
+
+// CreateView saves a new view
+func (r *FileBasedTodoRepository) CreateView(view *models.View) error {
+	if view == nil {
+		return fmt.Errorf("view cannot be nil")
+	}
+
+	if err := view.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.storage.AddView(*view); err != nil {
+		return err
+	}
+
+	if err := r.saveUnsafe(); err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteView removes a saved view by name
+func (r *FileBasedTodoRepository) DeleteView(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.storage.DeleteView(name); err != nil {
+		return fmt.Errorf("view %q not found", name)
+	}
+
+	if err := r.saveUnsafe(); err != nil {
+		return fmt.Errorf("failed to save after view deletion: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyRoundTrip encodes the current data in the storage format and decodes it again,
+// confirming it would save and load back intact without touching the data file
+func (r *FileBasedTodoRepository) VerifyRoundTrip() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	data, err := encodeStorage(r.storage, r.storageFormat())
+	if err != nil {
+		return err
+	}
+
+	var decoded models.TodoStorage
+	if err := decodeStorage(data, r.storageFormat(), &decoded); err != nil {
+		return err
+	}
+	if len(decoded.Todos) != len(r.storage.Todos) || decoded.NextID != r.storage.NextID ||
+		len(decoded.Views) != len(r.storage.Views) {
+		return fmt.Errorf("round trip changed the data: %d todos, next_id %d became %d todos, next_id %d",
+			len(r.storage.Todos), r.storage.NextID, len(decoded.Todos), decoded.NextID)
+	}
+	return nil
+}
+
+// CheckConsistency reports integrity problems in the loaded data without modifying it
+func (r *FileBasedTodoRepository) CheckConsistency() (*models.ConsistencyReport, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	report := r.storage.CheckConsistency(r.maxDescriptionLength())
+	warning, err := r.dataFileAgeWarning()
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		log.Printf("Warning: %s", warning)
+		report.Warnings = append(report.Warnings, warning)
+	}
+	return report, nil
+}
+
+// dataFileAgeWarning describes the data file as stale when it was last modified longer
+// ago than MaxDataFileAge, returning "" when it is fresh, missing, or the check is disabled
+func (r *FileBasedTodoRepository) dataFileAgeWarning() (string, error) {
+	if r.config.MaxDataFileAge <= 0 {
+		return "", nil
+	}
+
+	filePath, err := r.resolveDataFile()
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat data file: %w", err)
+	}
+
+	age := time.Since(info.ModTime())
+	if age <= r.config.MaxDataFileAge {
+		return "", nil
+	}
+	return fmt.Sprintf("data file %s has not been modified for %s (threshold %s)",
+		r.filePath, age.Round(time.Second), r.config.MaxDataFileAge), nil
+}
+
 // saveUnsafe saves data without acquiring mutex (internal use only)
 func (r *FileBasedTodoRepository) saveUnsafe() error {
-	// Marshal storage to JSON
-	data, err := json.MarshalIndent(r.storage, "", "  ")
+	// Every mutation persists through here, so this is where the GetAll cache goes stale
+	r.snapshotValid = false
+
+	data, err := encodeStorage(r.storage, r.storageFormat())
+	if err != nil {
+		return err
+	}
+
+	return r.writeDataFile(data)
+}
+
+// writeDataFile writes data to the data file. When the file's directory is missing, either
+// because it never existed or because it disappeared at runtime (a mounted volume detaching,
+// say), it is recreated once and the write retried, unless RecreateDataDir is off.
+func (r *FileBasedTodoRepository) writeDataFile(data []byte) error {
+	filePath, err := r.resolveDataFile()
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return err
 	}
 
-	// Write to file
-	if err := os.WriteFile(r.filePath, data, 0644); err != nil {
+	err = os.WriteFile(filePath, data, 0644)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
+	dir := filepath.Dir(filePath)
+	if !r.config.RecreateDataDir {
+		return fmt.Errorf("data directory %s is missing and recreating it is disabled: %w", dir, err)
+	}
+	log.Printf("Data directory %s is missing; recreating it", dir)
+	if err := r.ensureDataDir(filePath); err != nil {
+		log.Printf("Failed to recreate data directory %s: %v", dir, err)
+		return fmt.Errorf("data directory %s is missing and could not be recreated: %w", dir, err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		log.Printf("Failed to save to recreated data directory %s: %v", dir, err)
+		return fmt.Errorf("failed to write file after recreating data directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// resolveDataFile returns the path to read and write, following a symlinked data file to its
+// target unless symlinks are rejected. A dangling link resolves to the path it points at.
+func (r *FileBasedTodoRepository) resolveDataFile() (string, error) {
+	info, err := os.Lstat(r.filePath)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return r.filePath, nil
+	}
+
+	if r.config.RejectSymlinks {
+		return "", fmt.Errorf("data file %s is a symlink and symlinks are not allowed", r.filePath)
+	}
+
+	if target, err := filepath.EvalSymlinks(r.filePath); err == nil {
+		return target, nil
+	}
+	target, err := os.Readlink(r.filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve data file symlink: %w", err)
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(r.filePath), target)
+	}
+	return target, nil
+}
+
+// ensureDataDir creates the data file's parent directory if it is missing
+func (r *FileBasedTodoRepository) ensureDataDir(filePath string) error {
+	dirMode := r.config.DirMode
+	if dirMode == 0 {
+		dirMode = DefaultDirMode
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), dirMode); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
 	return nil
 }
\ No newline at end of file
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"go-crud-todo-list/models"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -42,6 +46,49 @@ func TestNewFileBasedTodoRepository(t *testing.T) {
 	}
 }
 
+// TestIDStart tests that a fresh repository assigns IDs from the configured base
+func TestIDStart(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, RepositoryConfig{IDStart: 1000})
+
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	todo := createTestTodo()
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+
+	if todo.ID != 1000 {
+		t.Errorf("Expected first ID 1000, got %d", todo.ID)
+	}
+}
+
+// TestIDStart_ExistingFile tests that the configured base does not override existing data
+func TestIDStart_ExistingFile(t *testing.T) {
+	filePath := createTempFile(t)
+	repo1 := NewFileBasedTodoRepository(filePath)
+	todo := createTestTodo()
+	if err := repo1.Create(&todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+
+	repo2 := NewFileBasedTodoRepositoryWithConfig(filePath, RepositoryConfig{IDStart: 1000})
+	if err := repo2.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	next := createTestTodo()
+	if err := repo2.Create(&next); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+
+	if next.ID != 2 {
+		t.Errorf("Expected existing sequence to continue at 2, got %d", next.ID)
+	}
+}
+
 func TestLoad_NonExistentFile(t *testing.T) {
 	filePath := createTempFile(t)
 	repo := NewFileBasedTodoRepository(filePath)
@@ -230,6 +277,144 @@ func TestGetAll(t *testing.T) {
 	}
 }
 
+// TestCount tests that counts agree with the filtered list length
+func TestCount(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	for i := 0; i < 5; i++ {
+		todo := createTestTodo()
+		todo.Title = fmt.Sprintf("Todo %d", i)
+		todo.Completed = i%2 == 0
+		if err := repo.Create(&todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+	}
+
+	completed := true
+	open := false
+	filters := []models.ListFilter{
+		{},
+		{Completed: &completed},
+		{Completed: &open},
+	}
+
+	for _, filter := range filters {
+		todos, err := repo.List(filter)
+		if err != nil {
+			t.Fatalf("Failed to list todos: %v", err)
+		}
+
+		count, err := repo.Count(filter)
+		if err != nil {
+			t.Fatalf("Failed to count todos: %v", err)
+		}
+
+		if count != len(todos) {
+			t.Errorf("Expected count %d to match list length %d", count, len(todos))
+		}
+	}
+
+	count, _ := repo.Count(models.ListFilter{Completed: &completed})
+	if count != 3 {
+		t.Errorf("Expected 3 completed todos, got %d", count)
+	}
+}
+
+// TestList_TitlePrefixSuffix tests anchored, case-insensitive title matching
+func TestList_TitlePrefixSuffix(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	titles := []string{"Buy milk today", "buy bread", "Sell bike today", "Call mom"}
+	for i, title := range titles {
+		todo := createTestTodo()
+		todo.Title = title
+		todo.Completed = i == 0
+		if err := repo.Create(&todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+	}
+
+	open := false
+	tests := []struct {
+		name     string
+		filter   models.ListFilter
+		expected []string
+	}{
+		{"prefix only", models.ListFilter{TitlePrefix: "BUY"}, []string{"Buy milk today", "buy bread"}},
+		{"suffix only", models.ListFilter{TitleSuffix: "Today"}, []string{"Buy milk today", "Sell bike today"}},
+		{"prefix and suffix", models.ListFilter{TitlePrefix: "buy", TitleSuffix: "today"}, []string{"Buy milk today"}},
+		{"prefix with status", models.ListFilter{TitlePrefix: "buy", Completed: &open}, []string{"buy bread"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, err := repo.List(tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to list todos: %v", err)
+			}
+
+			if len(todos) != len(tt.expected) {
+				t.Fatalf("Expected %d todos, got %d", len(tt.expected), len(todos))
+			}
+			for i, todo := range todos {
+				if todo.Title != tt.expected[i] {
+					t.Errorf("Expected title %q, got %q", tt.expected[i], todo.Title)
+				}
+			}
+		})
+	}
+}
+
+// TestList_SearchWithStatus tests free-text search combined with the completion filter
+func TestList_SearchWithStatus(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	todos := []models.Todo{
+		{Title: "Write report", Completed: true},
+		{Title: "Review Report draft"},
+		{Title: "Email team", Description: "Attach the quarterly report"},
+		{Title: "Book flights"},
+	}
+	for i := range todos {
+		if err := repo.Create(&todos[i]); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+	}
+
+	open := false
+	tests := []struct {
+		name     string
+		filter   models.ListFilter
+		expected []string
+	}{
+		{"search only", models.ListFilter{Query: "report"}, []string{"Write report", "Review Report draft", "Email team"}},
+		{"search open only", models.ListFilter{Query: "report", Completed: &open}, []string{"Review Report draft", "Email team"}},
+		{"empty search with status", models.ListFilter{Completed: &open}, []string{"Review Report draft", "Email team", "Book flights"}},
+		{"case-sensitive search", models.ListFilter{Query: "Report", Completed: &open, CaseSensitive: true}, []string{"Review Report draft"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, err := repo.List(tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to list todos: %v", err)
+			}
+
+			if len(todos) != len(tt.expected) {
+				t.Fatalf("Expected %d todos, got %d", len(tt.expected), len(todos))
+			}
+			for i, todo := range todos {
+				if todo.Title != tt.expected[i] {
+					t.Errorf("Expected title %q, got %q", tt.expected[i], todo.Title)
+				}
+			}
+		})
+	}
+}
+
 func TestGetByID(t *testing.T) {
 	filePath := createTempFile(t)
 	repo := NewFileBasedTodoRepository(filePath)
@@ -322,6 +507,28 @@ func TestUpdate(t *testing.T) {
 	if !foundTodo.Completed {
 		t.Error("Expected todo to be completed")
 	}
+
+	// Completing a todo stamps CompletedAt, which later updates keep
+	if foundTodo.CompletedAt == nil || !foundTodo.CompletedAt.Equal(foundTodo.UpdatedAt) {
+		t.Errorf("Expected CompletedAt to match the completing update, got %v", foundTodo.CompletedAt)
+	}
+	completedAt := *foundTodo.CompletedAt
+	time.Sleep(10 * time.Millisecond)
+	retitled := models.Todo{Title: "Retitled", Completed: true}
+	if err := repo.Update(originalID, &retitled); err != nil {
+		t.Fatalf("Failed to update todo: %v", err)
+	}
+	if retitled.CompletedAt == nil || !retitled.CompletedAt.Equal(completedAt) {
+		t.Errorf("Expected CompletedAt to be preserved, got %v", retitled.CompletedAt)
+	}
+
+	reopened := models.Todo{Title: "Retitled"}
+	if err := repo.Update(originalID, &reopened); err != nil {
+		t.Fatalf("Failed to update todo: %v", err)
+	}
+	if reopened.CompletedAt != nil {
+		t.Errorf("Expected CompletedAt to be cleared on reopen, got %v", reopened.CompletedAt)
+	}
 }
 
 func TestUpdate_NotFound(t *testing.T) {
@@ -442,6 +649,180 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+// TestGenerateNextID_Concurrent hammers storage-level ID generation without an outer lock
+func TestGenerateNextID_Concurrent(t *testing.T) {
+	storage := models.NewTodoStorage()
+
+	const numGoroutines = 50
+	const idsPerGoroutine = 200
+
+	var wg sync.WaitGroup
+	ids := make(chan int, numGoroutines*idsPerGoroutine)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < idsPerGoroutine; j++ {
+				ids <- storage.GenerateNextID()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("ID %d was generated more than once", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != numGoroutines*idsPerGoroutine {
+		t.Errorf("Expected %d unique IDs, got %d", numGoroutines*idsPerGoroutine, len(seen))
+	}
+}
+
+// TestUpdateIfVersion tests that an update expecting a stale version is rejected
+func TestUpdateIfVersion(t *testing.T) {
+	repo := NewFileBasedTodoRepository(createTempFile(t))
+	todo := createTestTodo()
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	
+	first := todo
+	first.Title = "First writer"
+	if err := repo.UpdateIfVersion(todo.ID, 1, &first); err != nil {
+		t.Fatalf("Expected the update at version 1 to succeed, got %v", err)
+	}
+	
+	second := todo
+	second.Title = "Second writer"
+	if err := repo.UpdateIfVersion(todo.ID, 1, &second); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("Expected a version conflict, got %v", err)
+	}
+	stored, _ := repo.GetByID(todo.ID)
+	if stored.Title != "First writer" || stored.Version != 2 {
+		t.Errorf("Expected the first write to be kept, got %q at version %d", stored.Title, stored.Version)
+	}
+	
+	// Version zero skips the check
+	if err := repo.UpdateIfVersion(todo.ID, 0, &second); err != nil {
+		t.Errorf("Expected an unchecked update to succeed, got %v", err)
+	}
+}
+
+// TestCreate_StaleNextID tests that a data file whose next_id is behind its todos doesn't
+// cause stored IDs to be issued again
+func TestCreate_StaleNextID(t *testing.T) {
+	filePath := createTempFile(t)
+	data := `{"todos": [{"id": 5, "title": "Hand-edited", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}], "next_id": 2}`
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	todo := createTestTodo()
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	if todo.ID != 6 {
+		t.Errorf("Expected the next ID after the highest stored one (6), got %d", todo.ID)
+	}
+}
+
+// TestCreateBatch tests that a batch is validated up front and saved in order
+func TestCreateBatch(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	
+	first, second := createTestTodo(), createTestTodo()
+	second.Title = "Second"
+	if err := repo.CreateBatch([]*models.Todo{&first, {Title: ""}}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Fatalf("Expected a validation error for the empty title, got %v", err)
+	}
+	if todos, _ := repo.GetAll(); len(todos) != 0 {
+		t.Fatalf("Expected nothing created from a rejected batch, got %v", todos)
+	}
+	
+	if err := repo.CreateBatch([]*models.Todo{&first, &second}); err != nil {
+		t.Fatalf("Failed to create batch: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || first.Version != 1 || first.CreatedAt.IsZero() {
+		t.Errorf("Expected IDs and timestamps assigned in order, got %+v and %+v", first, second)
+	}
+	
+	// The batch is persisted
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	todos, _ := reloaded.GetAll()
+	if len(todos) != 2 || todos[1].Title != "Second" {
+		t.Errorf("Expected both todos saved, got %v", todos)
+	}
+}
+
+// TestAdvanceNextID tests that the counter can be raised but never lowered
+func TestAdvanceNextID(t *testing.T) {
+	storage := models.NewTodoStorage()
+
+	if next := storage.AdvanceNextID(10); next != 10 {
+		t.Errorf("Expected next ID 10, got %d", next)
+	}
+	if next := storage.AdvanceNextID(5); next != 10 {
+		t.Errorf("Expected next ID to stay at 10, got %d", next)
+	}
+	if id := storage.GenerateNextID(); id != 10 {
+		t.Errorf("Expected generated ID 10, got %d", id)
+	}
+}
+
+// TestConcurrentCreates_UniqueIDs tests that concurrent creates never share an ID
+func TestConcurrentCreates_UniqueIDs(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	const numGoroutines = 20
+
+	var wg sync.WaitGroup
+	ids := make(chan int, numGoroutines)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			todo := createTestTodo()
+			todo.Title = fmt.Sprintf("Todo %d", n)
+			if err := repo.Create(&todo); err != nil {
+				t.Errorf("Failed to create todo: %v", err)
+				return
+			}
+			ids <- todo.ID
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("ID %d was assigned more than once", id)
+		}
+		seen[id] = true
+	}
+}
+
 // TestFilePersistence tests that data survives repository recreation
 func TestFilePersistence(t *testing.T) {
 	filePath := createTempFile(t)
@@ -474,4 +855,761 @@ func TestFilePersistence(t *testing.T) {
 	if todos[0].Title != todo.Title {
 		t.Errorf("Expected persisted title %s, got %s", todo.Title, todos[0].Title)
 	}
-}
\ No newline at end of file
+}
+
+// TestCheckConsistency tests that seeded inconsistencies are all reported without modifying data
+func TestCheckConsistency(t *testing.T) {
+	filePath := createTempFile(t)
+	data := `{
+  "todos": [
+    {"id": 1, "title": "First"},
+    {"id": 1, "title": "Duplicate"},
+    {"id": 2, "title": ""},
+    {"id": 7, "title": "Beyond next ID"}
+  ],
+  "next_id": 3
+}`
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to seed data file: %v", err)
+	}
+
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	report, err := repo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+
+	if report.Consistent {
+		t.Error("Expected report to be inconsistent")
+	}
+	if len(report.DuplicateIDs) != 1 || report.DuplicateIDs[0] != 1 {
+		t.Errorf("Expected duplicate ID 1, got %v", report.DuplicateIDs)
+	}
+	if len(report.IDsNotBelowNextID) != 1 || report.IDsNotBelowNextID[0] != 7 {
+		t.Errorf("Expected ID 7 to be reported against next_id, got %v", report.IDsNotBelowNextID)
+	}
+	if len(report.InvalidTodos) != 1 || report.InvalidTodos[0].ID != 2 {
+		t.Errorf("Expected todo 2 to fail validation, got %v", report.InvalidTodos)
+	}
+
+	after, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if string(after) != data {
+		t.Error("Expected consistency check not to modify the data file")
+	}
+}
+
+// TestCheckConsistency_StaleDataFile tests that a data file older than MaxDataFileAge is reported
+func TestCheckConsistency_StaleDataFile(t *testing.T) {
+	filePath := createTempFile(t)
+	config := DefaultRepositoryConfig()
+	config.MaxDataFileAge = time.Hour
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if err := repo.Create(&models.Todo{Title: "Fresh"}); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+
+	report, err := repo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("Expected no warnings for a fresh data file, got %v", report.Warnings)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filePath, old, old); err != nil {
+		t.Fatalf("Failed to age data file: %v", err)
+	}
+
+	report, err = repo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "has not been modified for") {
+		t.Errorf("Expected a stale data file warning, got %v", report.Warnings)
+	}
+	if !report.Consistent {
+		t.Error("Expected a stale data file not to make the report inconsistent")
+	}
+}
+
+// TestViews_Persist tests that saved views are written to the data file alongside todos
+func TestViews_Persist(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	completed := false
+	if err := repo.CreateView(&models.View{Name: "open", Completed: &completed}); err != nil {
+		t.Fatalf("Failed to create view: %v", err)
+	}
+	if err := repo.CreateView(&models.View{Name: "open"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected duplicate view name to be rejected, got %v", err)
+	}
+
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	view, err := reloaded.GetView("open")
+	if err != nil {
+		t.Fatalf("Expected view to survive a reload: %v", err)
+	}
+	if view.Completed == nil || *view.Completed {
+		t.Errorf("Expected completed=false criterion, got %+v", view)
+	}
+
+	if err := reloaded.DeleteView("open"); err != nil {
+		t.Fatalf("Failed to delete view: %v", err)
+	}
+	if views, _ := reloaded.ListViews(); len(views) != 0 {
+		t.Errorf("Expected no views after deletion, got %+v", views)
+	}
+}
+
+// TestUpdate_MonotonicUpdatedAt tests that UpdatedAt keeps increasing when the clock goes backwards
+func TestUpdate_MonotonicUpdatedAt(t *testing.T) {
+	for _, monotonic := range []bool{true, false} {
+		now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		config := DefaultRepositoryConfig()
+		config.MonotonicUpdatedAt = monotonic
+		config.Now = func() time.Time { return now }
+		repo := NewFileBasedTodoRepositoryWithConfig(createTempFile(t), config)
+		if err := repo.Load(); err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+
+		todo := &models.Todo{Title: "Clock skew"}
+		if err := repo.Create(todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+		if err := repo.Update(todo.ID, &models.Todo{Title: "First update"}); err != nil {
+			t.Fatalf("Failed to update todo: %v", err)
+		}
+		first, _ := repo.GetByID(todo.ID)
+
+		// The clock jumps back an hour before the next update
+		now = now.Add(-time.Hour)
+		if err := repo.Update(todo.ID, &models.Todo{Title: "Second update"}); err != nil {
+			t.Fatalf("Failed to update todo: %v", err)
+		}
+		second, _ := repo.GetByID(todo.ID)
+
+		if monotonic && !second.UpdatedAt.After(first.UpdatedAt) {
+			t.Errorf("Expected UpdatedAt to increase past %v, got %v", first.UpdatedAt, second.UpdatedAt)
+		}
+		if !monotonic && !second.UpdatedAt.Equal(now) {
+			t.Errorf("Expected UpdatedAt to follow the clock without the guard, got %v", second.UpdatedAt)
+		}
+	}
+}
+
+// TestLoad_FutureCreatedAt tests the reject and clamp policies for future-dated todos
+func TestLoad_FutureCreatedAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	data := `{
+  "todos": [
+    {"id": 1, "title": "Within skew", "created_at": "2024-06-01T12:04:00Z"},
+    {"id": 2, "title": "Far future", "created_at": "2030-01-01T00:00:00Z"}
+  ],
+  "next_id": 3
+}`
+
+	load := func(policy string) (*FileBasedTodoRepository, error) {
+		filePath := createTempFile(t)
+		if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to seed data file: %v", err)
+		}
+		config := DefaultRepositoryConfig()
+		config.FutureCreatedAtPolicy = policy
+		config.Now = func() time.Time { return now }
+		repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+		return repo, repo.Load()
+	}
+
+	if _, err := load(FutureCreatedAtReject); err == nil || !strings.Contains(err.Error(), "created in the future [2]") {
+		t.Errorf("Expected reject policy to fail naming todo 2, got %v", err)
+	}
+
+	repo, err := load(FutureCreatedAtClamp)
+	if err != nil {
+		t.Fatalf("Expected clamp policy to load, got %v", err)
+	}
+	clamped, _ := repo.GetByID(2)
+	if !clamped.CreatedAt.Equal(now) {
+		t.Errorf("Expected future created_at to be clamped to %v, got %v", now, clamped.CreatedAt)
+	}
+	withinSkew, _ := repo.GetByID(1)
+	if !withinSkew.CreatedAt.Equal(now.Add(4 * time.Minute)) {
+		t.Errorf("Expected created_at within the skew allowance to be kept, got %v", withinSkew.CreatedAt)
+	}
+
+	repo, err = load(FutureCreatedAtAllow)
+	if err != nil {
+		t.Fatalf("Expected allow policy to load, got %v", err)
+	}
+	if kept, _ := repo.GetByID(2); kept.CreatedAt.Year() != 2030 {
+		t.Errorf("Expected allow policy to keep created_at, got %v", kept.CreatedAt)
+	}
+}
+
+// TestLoad_DuplicateIDs tests the strict and renumber policies for todos sharing an ID
+func TestLoad_DuplicateIDs(t *testing.T) {
+	data := `{
+  "todos": [
+    {"id": 1, "title": "First"},
+    {"id": 2, "title": "Second"},
+    {"id": 1, "title": "Duplicate"}
+  ],
+  "next_id": 3
+}`
+
+	t.Run("strict", func(t *testing.T) {
+		filePath := createTempFile(t)
+		if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to seed data file: %v", err)
+		}
+
+		repo := NewFileBasedTodoRepositoryWithConfig(filePath, RepositoryConfig{DuplicateIDPolicy: DuplicateIDsStrict})
+		err := repo.Load()
+		if err == nil || !strings.Contains(err.Error(), "duplicate todo IDs [1]") {
+			t.Errorf("Expected duplicate ID error, got %v", err)
+		}
+	})
+
+	t.Run("renumber", func(t *testing.T) {
+		filePath := createTempFile(t)
+		if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to seed data file: %v", err)
+		}
+
+		repo := NewFileBasedTodoRepositoryWithConfig(filePath, RepositoryConfig{DuplicateIDPolicy: DuplicateIDsRenumber})
+		if err := repo.Load(); err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+
+		first, err := repo.GetByID(1)
+		if err != nil || first.Title != "First" {
+			t.Errorf("Expected the first todo to keep ID 1, got %+v (err %v)", first, err)
+		}
+		renumbered, err := repo.GetByID(3)
+		if err != nil || renumbered.Title != "Duplicate" {
+			t.Errorf("Expected the duplicate to be renumbered to 3, got %+v (err %v)", renumbered, err)
+		}
+
+		todo := createTestTodo()
+		if err := repo.Create(&todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+		if todo.ID != 4 {
+			t.Errorf("Expected the next ID after renumbering to be 4, got %d", todo.ID)
+		}
+
+		report, _ := repo.CheckConsistency()
+		if !report.Consistent {
+			t.Errorf("Expected consistent data after renumbering, got %+v", report)
+		}
+	})
+}
+
+// TestCheckConsistency_Clean tests that valid data is reported as consistent
+func TestCheckConsistency_Clean(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	todo := createTestTodo()
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+
+	report, err := repo.CheckConsistency()
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if !report.Consistent {
+		t.Errorf("Expected consistent report, got %+v", report)
+	}
+}
+
+// TestUpdateBatch tests that a batch applies valid items, reports the rest, and saves once
+func TestUpdateBatch(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	first := createTestTodo()
+	second := createTestTodo()
+	repo.Create(&first)
+	repo.Create(&second)
+
+	results, err := repo.UpdateBatch([]models.BulkUpdateItem{
+		{ID: first.ID, Title: "Updated", Completed: true, Version: first.Version},
+		{ID: second.ID, Title: "Stale", Version: second.Version + 5},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to apply batch: %v", err)
+	}
+
+	if results[0].Status != models.BulkStatusUpdated || results[0].Version != 2 {
+		t.Errorf("Expected first item updated to version 2, got %+v", results[0])
+	}
+	if results[1].Status != models.BulkStatusConflict || results[1].Version != 1 {
+		t.Errorf("Expected second item to conflict at version 1, got %+v", results[1])
+	}
+
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	stored, _ := reloaded.GetByID(first.ID)
+	if stored.Title != "Updated" || !stored.Completed {
+		t.Errorf("Expected batch update to be persisted, got %+v", stored)
+	}
+}
+func TestUpdateTagsBatch(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	first := createTestTodo()
+	first.Tags = []string{"old", "home"}
+	second := createTestTodo()
+	second.Tags = []string{"old"}
+	repo.Create(&first)
+	repo.Create(&second)
+
+	result, err := repo.UpdateTagsBatch([]int{first.ID, second.ID, 999}, []string{"urgent"}, []string{"old"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to apply tag batch: %v", err)
+	}
+	if len(result.Updated) != 2 || len(result.MissingIDs) != 1 || result.MissingIDs[0] != 999 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	stored, _ := reloaded.GetByID(first.ID)
+	if len(stored.Tags) != 2 || stored.Tags[0] != "home" || stored.Tags[1] != "urgent" {
+		t.Errorf("Expected tags [home urgent] to be persisted, got %v", stored.Tags)
+	}
+	stored, _ = reloaded.GetByID(second.ID)
+	if len(stored.Tags) != 1 || stored.Tags[0] != "urgent" {
+		t.Errorf("Expected tags [urgent] to be persisted, got %v", stored.Tags)
+	}
+}
+
+func TestCreate_CustomDescriptionLimit(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, RepositoryConfig{IDStart: 1, MaxDescriptionLength: 1500})
+
+	todo := createTestTodo()
+	todo.Description = strings.Repeat("a", 1200)
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Expected description within the custom limit to be accepted, got %v", err)
+	}
+
+	todo = createTestTodo()
+	todo.Description = strings.Repeat("a", 1501)
+	err := repo.Create(&todo)
+	if err == nil || !strings.Contains(err.Error(), "description must be 1500 characters or less") {
+		t.Errorf("Expected the error to name the 1500 character limit, got %v", err)
+	}
+}
+
+func TestSave_CreatesMissingDirectory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "data", "nested", "todos.json")
+	repo := NewFileBasedTodoRepository(filePath)
+
+	todo := createTestTodo()
+	if err := repo.Create(&todo); err != nil {
+		t.Fatalf("Expected save to create the missing directory, got %v", err)
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		t.Errorf("Expected data file to exist, got %v", err)
+	}
+}
+
+func TestSave_RecreatesDeletedDirectory(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "volume")
+	filePath := filepath.Join(dataDir, "todos.json")
+	repo := NewFileBasedTodoRepository(filePath)
+
+	first := createTestTodo()
+	if err := repo.Create(&first); err != nil {
+		t.Fatalf("Failed to create: %v", err)
+	}
+
+	// The directory disappears out from under the running repository
+	if err := os.RemoveAll(dataDir); err != nil {
+		t.Fatalf("Failed to remove data directory: %v", err)
+	}
+
+	second := createTestTodo()
+	if err := repo.Create(&second); err != nil {
+		t.Fatalf("Expected the save to recreate the directory and succeed, got %v", err)
+	}
+
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	todos, _ := reloaded.GetAll()
+	if len(todos) != 2 {
+		t.Errorf("Expected both todos saved to the recreated directory, got %d", len(todos))
+	}
+}
+
+func TestSave_DeletedDirectoryWithoutRecreate(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "volume")
+	filePath := filepath.Join(dataDir, "todos.json")
+	config := DefaultRepositoryConfig()
+	config.RecreateDataDir = false
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatalf("Failed to create data directory: %v", err)
+	}
+	repo := NewFileBasedTodoRepositoryWithConfig(filePath, config)
+
+	if err := os.RemoveAll(dataDir); err != nil {
+		t.Fatalf("Failed to remove data directory: %v", err)
+	}
+
+	todo := createTestTodo()
+	err := repo.Create(&todo)
+	if err == nil || !strings.Contains(err.Error(), "data directory "+dataDir+" is missing") {
+		t.Fatalf("Expected an error naming the missing directory, got %v", err)
+	}
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the directory not to be recreated, got %v", err)
+	}
+}
+
+func TestStreamFiltered(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	titles := []string{"Buy milk", "Walk dog", "Buy bread"}
+	for _, title := range titles {
+		todo := createTestTodo()
+		todo.Title = title
+		repo.Create(&todo)
+	}
+
+	var buf bytes.Buffer
+	if err := repo.StreamFiltered(&buf, models.ListFilter{TitlePrefix: "buy"}); err != nil {
+		t.Fatalf("Failed to stream todos: %v", err)
+	}
+
+	var todos []models.Todo
+	if err := json.Unmarshal(buf.Bytes(), &todos); err != nil {
+		t.Fatalf("Streamed output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(todos) != 2 || todos[0].Title != "Buy milk" || todos[1].Title != "Buy bread" {
+		t.Errorf("Expected the two matching todos in order, got %+v", todos)
+	}
+
+	// A filter matching nothing still produces a valid empty array
+	buf.Reset()
+	if err := repo.StreamFiltered(&buf, models.ListFilter{TitlePrefix: "zzz"}); err != nil {
+		t.Fatalf("Failed to stream todos: %v", err)
+	}
+	if buf.String() != "[]" {
+		t.Errorf("Expected an empty array, got %q", buf.String())
+	}
+}
+
+// TestGetAll_CacheInvalidatedOnCreate tests that a cached GetAll result is refreshed after a write
+func TestGetAll_CacheInvalidatedOnCreate(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	first := createTestTodo()
+	repo.Create(&first)
+
+	before, _ := repo.GetAll()
+	cached, _ := repo.GetAll()
+	if len(before) != 1 || &before[0] != &cached[0] {
+		t.Fatalf("Expected repeated reads to share the cached snapshot")
+	}
+
+	second := createTestTodo()
+	repo.Create(&second)
+
+	after, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("Failed to get all todos: %v", err)
+	}
+	if len(after) != 2 {
+		t.Errorf("Expected 2 todos after create, got %d", len(after))
+	}
+	if len(before) != 1 {
+		t.Errorf("Expected the earlier snapshot to be left untouched, got %d todos", len(before))
+	}
+}
+
+func BenchmarkGetAll(b *testing.B) {
+	repo := NewFileBasedTodoRepository(filepath.Join(b.TempDir(), "todos.json"))
+	for i := 0; i < 1000; i++ {
+		todo := createTestTodo()
+		repo.Create(&todo)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		repo.GetAll()
+	}
+}
+
+// TestList_ServedFromSnapshot tests that List shares the GetAll snapshot when every todo
+// matches and copies only the matches otherwise
+func TestList_ServedFromSnapshot(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+
+	open, done := createTestTodo(), createTestTodo()
+	done.Completed = true
+	repo.Create(&open)
+	repo.Create(&done)
+
+	all, _ := repo.GetAll()
+	listed, err := repo.List(models.ListFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list todos: %v", err)
+	}
+	if len(listed) != 2 || &listed[0] != &all[0] {
+		t.Errorf("Expected an unfiltered list to share the cached snapshot")
+	}
+
+	completed := true
+	filtered, _ := repo.List(models.ListFilter{Completed: &completed})
+	if len(filtered) != 1 || filtered[0].ID != done.ID {
+		t.Fatalf("Expected only the completed todo, got %v", filtered)
+	}
+	if &filtered[0] == &all[1] {
+		t.Errorf("Expected a filtered list to be copied out of the snapshot")
+	}
+
+	third := createTestTodo()
+	repo.Create(&third)
+	if listed, _ := repo.List(models.ListFilter{}); len(listed) != 3 {
+		t.Errorf("Expected 3 todos listed after create, got %d", len(listed))
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	repo := NewFileBasedTodoRepository(filepath.Join(b.TempDir(), "todos.json"))
+	for i := 0; i < 1000; i++ {
+		todo := createTestTodo()
+		todo.Completed = i%2 == 0
+		repo.Create(&todo)
+	}
+
+	completed := true
+	for _, bm := range []struct {
+		name   string
+		filter models.ListFilter
+	}{
+		{"unfiltered", models.ListFilter{}},
+		{"completed", models.ListFilter{Completed: &completed}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				repo.List(bm.filter)
+			}
+		})
+	}
+}
+
+func TestSymlinkedDataFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real.json")
+	link := filepath.Join(dir, "todos.json")
+	if err := os.WriteFile(target, []byte(`{"todos": [], "next_id": 1}`), 0644); err != nil {
+		t.Fatalf("Failed to create target file: %v", err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	t.Run("follow", func(t *testing.T) {
+		repo := NewFileBasedTodoRepository(link)
+		if err := repo.Load(); err != nil {
+			t.Fatalf("Failed to load through symlink: %v", err)
+		}
+		todo := createTestTodo()
+		if err := repo.Create(&todo); err != nil {
+			t.Fatalf("Failed to save through symlink: %v", err)
+		}
+
+		info, err := os.Lstat(link)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("Expected the symlink itself to be preserved")
+		}
+		data, _ := os.ReadFile(target)
+		if !strings.Contains(string(data), todo.Title) {
+			t.Errorf("Expected the link target to receive the write, got %s", data)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		config := DefaultRepositoryConfig()
+		config.RejectSymlinks = true
+		repo := NewFileBasedTodoRepositoryWithConfig(link, config)
+
+		err := repo.Load()
+		if err == nil || !strings.Contains(err.Error(), "symlink") {
+			t.Errorf("Expected load to reject the symlink, got %v", err)
+		}
+		if err := repo.Save(); err == nil {
+			t.Error("Expected save to reject the symlink, got nil")
+		}
+	})
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	for _, format := range []string{StorageFormatJSON, StorageFormatGob} {
+		t.Run(format, func(t *testing.T) {
+			config := DefaultRepositoryConfig()
+			config.StorageFormat = format
+			repo := NewFileBasedTodoRepositoryWithConfig(createTempFile(t), config)
+			if err := repo.Load(); err != nil {
+				t.Fatalf("Failed to load: %v", err)
+			}
+			if err := repo.VerifyRoundTrip(); err != nil {
+				t.Errorf("Expected empty storage to round trip, got %v", err)
+			}
+
+			completed := &models.Todo{Title: "Done", Description: "ünïcode ✓", Tags: []string{"a", "b"}}
+			if err := repo.Create(completed); err != nil {
+				t.Fatalf("Failed to create todo: %v", err)
+			}
+			if err := repo.Create(&models.Todo{Title: "Open"}); err != nil {
+				t.Fatalf("Failed to create todo: %v", err)
+			}
+			completed.Completed = true
+			if err := repo.Update(completed.ID, completed); err != nil {
+				t.Fatalf("Failed to update todo: %v", err)
+			}
+
+			if err := repo.VerifyRoundTrip(); err != nil {
+				t.Errorf("Expected valid data to round trip, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPurgeBefore(t *testing.T) {
+	old := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	filePath := createTempFile(t)
+	data, err := json.Marshal(models.TodoStorage{
+		Todos: []models.Todo{
+			{ID: 1, Title: "Old open", CreatedAt: old, UpdatedAt: old},
+			{ID: 2, Title: "Old done", Completed: true, CreatedAt: old, UpdatedAt: old, CompletedAt: &old},
+			{ID: 3, Title: "Recent open", CreatedAt: recent, UpdatedAt: recent},
+			{ID: 4, Title: "Old but recently done", Completed: true, CreatedAt: old, UpdatedAt: recent, CompletedAt: &recent},
+		},
+		NextID: 5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal storage: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	cutoff := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	purged, err := repo.PurgeBefore(cutoff, models.PurgeFieldCompleted)
+	if err != nil {
+		t.Fatalf("Failed to purge: %v", err)
+	}
+	if len(purged) != 1 || purged[0].ID != 2 {
+		t.Fatalf("Expected only todo 2 purged by completed_at, got %v", purged)
+	}
+
+	purged, err = repo.PurgeBefore(cutoff, models.PurgeFieldCreated)
+	if err != nil {
+		t.Fatalf("Failed to purge: %v", err)
+	}
+	if len(purged) != 2 || purged[0].ID != 1 || purged[1].ID != 4 {
+		t.Fatalf("Expected todos 1 and 4 purged by created_at, got %v", purged)
+	}
+
+	// The purge is persisted
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	todos, _ := reloaded.GetAll()
+	if len(todos) != 1 || todos[0].ID != 3 {
+		t.Errorf("Expected only the todo created after the cutoff to remain, got %v", todos)
+	}
+
+	if _, err := repo.PurgeBefore(cutoff, "updated_at"); err == nil {
+		t.Error("Expected an error for an unsupported field")
+	}
+}
+
+func TestDeleteExpiredBy(t *testing.T) {
+	filePath := createTempFile(t)
+	repo := NewFileBasedTodoRepository(filePath)
+	if err := repo.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	expired := &models.Todo{Title: "Expired", ExpiresAt: &past}
+	extended := &models.Todo{Title: "Extended", ExpiresAt: &past}
+	kept := &models.Todo{Title: "No expiry"}
+	for _, todo := range []*models.Todo{expired, extended, kept} {
+		if err := repo.Create(todo); err != nil {
+			t.Fatalf("Failed to create: %v", err)
+		}
+	}
+
+	// An expiry extended after a caller read the todos must still be honoured
+	later := now.Add(time.Hour)
+	update := *extended
+	update.ExpiresAt = &later
+	if err := repo.Update(extended.ID, &update); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+
+	deleted, err := repo.DeleteExpiredBy(now)
+	if err != nil {
+		t.Fatalf("Failed to delete expired todos: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != expired.ID {
+		t.Fatalf("Expected only todo %d deleted, got %v", expired.ID, deleted)
+	}
+
+	// The delete is persisted
+	reloaded := NewFileBasedTodoRepository(filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	todos, _ := reloaded.GetAll()
+	if len(todos) != 2 || todos[0].ID != extended.ID || todos[1].ID != kept.ID {
+		t.Errorf("Expected the extended and unexpiring todos to remain, got %v", todos)
+	}
+}
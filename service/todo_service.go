@@ -1,32 +1,293 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"go-crud-todo-list/audit"
 	"go-crud-todo-list/models"
 	"go-crud-todo-list/repository"
+	"io"
+	"log"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // TodoService defines the interface for todo business logic operations
 type TodoService interface {
 	GetAllTodos() ([]models.Todo, error)
+	ListTodos(filter models.ListFilter) ([]models.Todo, error)
+	CountTodos(filter models.ListFilter) (int, error)
+	NextActionable() (*models.Todo, error)
+	StaleTodos(olderThan time.Duration) ([]models.Todo, error)
+	RelatedTodos(id int, limit int, includeCompleted bool) ([]models.Todo, error)
+	StreamFiltered(w io.Writer, filter models.ListFilter) error
 	GetTodoByID(id int) (*models.Todo, error)
-	CreateTodo(title, description string) (*models.Todo, error)
-	UpdateTodo(id int, title, description string, completed bool) (*models.Todo, error)
-	DeleteTodo(id int) error
+	CreateTodo(input CreateTodoInput) (*models.Todo, error)
+	NormalizeTodos(inputs []CreateTodoInput) []NormalizeResult
+	ImportTodos(todos []models.Todo) (int, error)
+	UpdateTodo(id int, input UpdateTodoInput) (*models.Todo, error)
+	PreviewUpdate(id int, input UpdateTodoInput) ([]models.FieldChange, error)
+	SnoozeTodo(id int, d time.Duration) (*models.Todo, error)
+	PinTodo(id int) (*models.Todo, error)
+	UnpinTodo(id int) (*models.Todo, error)
+	AddChecklistItem(id int, text string) (*models.Todo, error)
+	SetChecklistItemDone(id, index int, done *bool) (*models.Todo, error)
+	RemoveChecklistItem(id, index int) (*models.Todo, error)
+	PatchTodo(id int, ops []models.PatchOperation) (*models.Todo, error)
+	BulkUpdateTodos(items []models.BulkUpdateItem) ([]models.BulkUpdateResult, error)
+	ReopenCompletedBefore(before time.Time) (int, error)
+	UpdateTagsBatch(ids []int, add, remove []string) (*models.TagBatchResult, error)
+	DeleteTodo(id int, force bool) error
+	DeleteExpired() (int, error)
+	PurgeBefore(before time.Time, field string) (int, error)
+	ArchiveCompleted() (int, error)
+	ListArchived() ([]models.Todo, error)
+	GetTodoHistory(id int) ([]audit.Entry, error)
+	Burndown(from, to time.Time, bucket string) ([]models.BurndownBucket, error)
+	TagStats() ([]models.TagStats, error)
+	CheckConsistency() (*models.ConsistencyReport, error)
+	VerifyStorage() error
+	DiffSnapshot(snapshot []models.Todo) (*models.DiffResult, error)
+	ListViews() ([]models.View, error)
+	GetView(name string) (*models.View, error)
+	CreateView(view models.View) (*models.View, error)
+	DeleteView(name string) error
+}
+
+// CreateTodoInput holds the client-supplied fields for a new todo
+type CreateTodoInput struct {
+	Title       string
+	Description string
+	DueDate     *time.Time
+	// ExpiresAt schedules the todo for deletion by DeleteExpired; it must be in the future
+	ExpiresAt *time.Time
+	// Source records how the todo was created; empty means models.SourceAPI
+	Source string
+	// Tags are trimmed, lowercased and deduplicated; when empty, the configured default tags apply
+	Tags []string
+	// Pinned is not accepted from clients; it carries the configured default
+	Pinned bool
+	// Completed is not accepted from clients either; imports use it to carry over completion,
+	// which must meet CompletionRequiredFields
+	Completed bool
+}
+
+// NormalizeResult is the outcome of normalizing one create input: the todo that would be
+// stored, or the error that would reject it
+type NormalizeResult struct {
+	Todo *models.Todo
+	Err  error
+}
+
+// UpdateTodoInput holds the client-supplied fields that replace a todo's editable state.
+// It is a full replacement: Completed false reopens the todo. Partial changes go through
+// PatchTodo, which leaves fields the patch doesn't touch as they are.
+type UpdateTodoInput struct {
+	Title       string
+	Description string
+	Completed   bool
+	DueDate     *time.Time
+	ExpiresAt   *time.Time
+}
+
+// ServiceConfig holds optional collaborators and business rules for the service layer
+type ServiceConfig struct {
+	// AuditLogger records successful mutations; nil disables auditing
+	AuditLogger audit.Logger
+	// CompletionRequiredFields lists fields that must be non-empty before a todo can be completed
+	CompletionRequiredFields []string
+	// MaxDescriptionLength limits todo descriptions; zero uses models.DefaultMaxDescriptionLength
+	MaxDescriptionLength int
+	// MaxDescriptionLines limits the number of lines in a description; zero means unlimited
+	MaxDescriptionLines int
+	// RejectTitleControlChars rejects titles containing control characters such as newlines
+	// and tabs; descriptions may still span lines
+	RejectTitleControlChars bool
+	// TitleScript restricts titles to the characters of one script (see models.TitleScriptLatin);
+	// empty or models.TitleScriptAny allows any script
+	TitleScript string
+	// PreserveDescriptionWhitespace keeps leading and trailing whitespace in descriptions;
+	// titles are always trimmed
+	PreserveDescriptionWhitespace bool
+	// Now returns the current time for expiry checks; nil uses time.Now
+	Now func() time.Time
+	// CreateDefaults fills in fields that API create requests leave unset
+	CreateDefaults CreateDefaults
+	// ForbiddenWords rejects titles and descriptions containing any of these words, matched
+	// as whole words ignoring case; empty disables the check
+	ForbiddenWords []string
+	// ArchiveCompleted moves completed todos from the active data file to the repository's
+	// archive file once they have been completed for ArchiveAfter
+	ArchiveCompleted bool
+	// ArchiveAfter delays archiving after completion; zero archives as soon as a todo is completed
+	ArchiveAfter time.Duration
+	// AutoTitle derives a missing title from the first line of the description on create
+	// instead of rejecting the todo
+	AutoTitle bool
+	// MaxChecklistItems limits the number of checklist items per todo; zero uses
+	// models.DefaultMaxChecklistItems
+	MaxChecklistItems int
+	// AutoCompleteChecklist completes a todo when checking an item leaves every checklist
+	// item done, and reopens it when an item is unchecked
+	AutoCompleteChecklist bool
+	// MaxTodoBytes limits the size of a todo encoded as JSON, bounding the storage one todo
+	// can take however its fields are filled; zero means unlimited
+	MaxTodoBytes int
+	// AutoTag adds the #hashtags in a title to the todo's tags on create and update
+	AutoTag bool
+	// StripHashtags removes the hashtags AutoTag extracts from the stored title
+	StripHashtags bool
+	// RequireCompleteBeforeDelete rejects deleting an open todo unless the delete is forced
+	RequireCompleteBeforeDelete bool
+}
+
+// CreateDefaults holds the values CreateTodo gives todos created through the API for fields
+// the request leaves unset. Imported and cloned todos keep the values they were copied with.
+type CreateDefaults struct {
+	Description string
+	Tags        []string
+	Pinned      bool
+	// DueIn sets the due date this long after creation; zero leaves it unset
+	DueIn time.Duration
+}
+
+// ParseCreateDefaults parses a create defaults spec of semicolon-separated field=value
+// pairs, e.g. "tags=inbox,triage;due_in=72h;pinned=true". Supported fields are
+// description, tags (comma-separated), pinned and due_in (a duration).
+func ParseCreateDefaults(spec string) (CreateDefaults, error) {
+	var defaults CreateDefaults
+	for _, pair := range strings.Split(spec, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, value, found := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		if !found {
+			return CreateDefaults{}, fmt.Errorf("default %q must be field=value", pair)
+		}
+
+		switch field {
+		case "description":
+			defaults.Description = value
+		case "tags":
+			defaults.Tags = models.NormalizeTags(strings.Split(value, ","))
+			if err := models.ValidateTags(defaults.Tags); err != nil {
+				return CreateDefaults{}, err
+			}
+		case "pinned":
+			pinned, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return CreateDefaults{}, fmt.Errorf("pinned default %q must be true or false", value)
+			}
+			defaults.Pinned = pinned
+		case "due_in":
+			dueIn, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil || dueIn <= 0 {
+				return CreateDefaults{}, fmt.Errorf("due_in default %q must be a positive duration", value)
+			}
+			defaults.DueIn = dueIn
+		default:
+			return CreateDefaults{}, fmt.Errorf("unsupported default field %q: must be description, tags, pinned or due_in", field)
+		}
+	}
+	return defaults, nil
+}
+
+const (
+	// CompletionFieldDescription requires a non-empty description to complete a todo
+	CompletionFieldDescription = "description"
+	// CompletionFieldDueDate requires a due date to complete a todo
+	CompletionFieldDueDate = "due_date"
+)
+
+// ValidateCompletionRequiredFields checks that every configured completion requirement is supported
+func ValidateCompletionRequiredFields(fields []string) error {
+	for _, field := range fields {
+		switch field {
+		case CompletionFieldDescription, CompletionFieldDueDate:
+		default:
+			return fmt.Errorf("unsupported completion required field %q", field)
+		}
+	}
+	return nil
+}
+
+// ValidateForbiddenWords checks that every forbidden word is a single word
+func ValidateForbiddenWords(words []string) error {
+	_, err := models.NewWordList(words)
+	return err
 }
 
 // TodoServiceImpl implements the TodoService interface
 type TodoServiceImpl struct {
 	repository repository.TodoRepository
+	config     ServiceConfig
+	forbidden  models.WordList
 }
 
 // NewTodoService creates a new TodoService instance with the given repository
 func NewTodoService(repo repository.TodoRepository) TodoService {
+	return NewTodoServiceWithConfig(repo, ServiceConfig{})
+}
+
+// NewTodoServiceWithConfig creates a new TodoService instance with the given repository and configuration
+func NewTodoServiceWithConfig(repo repository.TodoRepository, config ServiceConfig) TodoService {
+	// Invalid words are rejected by ValidateForbiddenWords when the configuration is loaded
+	forbidden, _ := models.NewWordList(config.ForbiddenWords)
 	return &TodoServiceImpl{
 		repository: repo,
+		config:     config,
+		forbidden:  forbidden,
+	}
+}
+
+// recordAudit writes an audit entry for a successful mutation.
+// Audit failures are logged and never fail the operation that triggered them.
+func (s *TodoServiceImpl) recordAudit(action string, todoID int, before, after *models.Todo) {
+	if s.config.AuditLogger == nil {
+		return
 	}
+
+	entry := audit.Entry{
+		Timestamp: s.now(),
+		Action:    action,
+		TodoID:    todoID,
+		Before:    before,
+		After:     after,
+	}
+	if err := s.config.AuditLogger.Log(entry); err != nil {
+		log.Printf("Failed to write audit entry for %s of todo %d: %v", action, todoID, err)
+	}
+}
+
+// now returns the current time from the configured clock
+func (s *TodoServiceImpl) now() time.Time {
+	if s.config.Now != nil {
+		return s.config.Now()
+	}
+	return time.Now()
+}
+
+// validateExpiry requires a newly set expiry to be in the future; an unchanged expiry is accepted
+func (s *TodoServiceImpl) validateExpiry(current, expiresAt *time.Time) error {
+	if expiresAt == nil || (current != nil && current.Equal(*expiresAt)) {
+		return nil
+	}
+	if !expiresAt.After(s.now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// normalizeDescription trims a description unless whitespace preservation is configured
+func (s *TodoServiceImpl) normalizeDescription(description string) string {
+	if s.config.PreserveDescriptionWhitespace {
+		return description
+	}
+	return strings.TrimSpace(description)
 }
 
 // validateTodoInput validates input parameters for todo creation and updates
@@ -35,19 +296,42 @@ func (s *TodoServiceImpl) validateTodoInput(title, description string) error {
 	if strings.TrimSpace(title) == "" {
 		return errors.New("title is required and cannot be empty")
 	}
-	if len(title) > 200 {
-		return errors.New("title must be 200 characters or less")
+	if len(title) > models.MaxTitleLength {
+		return models.TitleTooLongError(len(title))
+	}
+	if s.config.RejectTitleControlChars {
+		if err := (&models.Todo{Title: title}).ValidateTitleCharacters(); err != nil {
+			return err
+		}
+	}
+	if err := (&models.Todo{Title: title}).ValidateTitleScript(s.config.TitleScript); err != nil {
+		return err
+	}
+	if word, found := s.forbidden.Find(title); found {
+		return fmt.Errorf("title contains the forbidden word %q", word)
+	}
+	if word, found := s.forbidden.Find(description); found {
+		return fmt.Errorf("description contains the forbidden word %q", word)
 	}
 
 	// Validate description
-	if len(description) > 1000 {
-		return errors.New("description must be 1000 characters or less")
+	maxDescriptionLength := s.config.MaxDescriptionLength
+	if maxDescriptionLength <= 0 {
+		maxDescriptionLength = models.DefaultMaxDescriptionLength
+	}
+	if len(description) > maxDescriptionLength {
+		return models.DescriptionTooLongError(maxDescriptionLength, len(description))
+	}
+	todo := models.Todo{Description: description}
+	if err := todo.ValidateDescriptionLines(s.config.MaxDescriptionLines); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// GetAllTodos retrieves all todos from the repository
+// GetAllTodos retrieves all todos from the repository. The slice may be shared with other
+// callers and must not be modified.
 func (s *TodoServiceImpl) GetAllTodos() ([]models.Todo, error) {
 	todos, err := s.repository.GetAll()
 	if err != nil {
@@ -56,6 +340,121 @@ func (s *TodoServiceImpl) GetAllTodos() ([]models.Todo, error) {
 	return todos, nil
 }
 
+// resolveFilter fills in the parts of a filter that depend on the current time
+func (s *TodoServiceImpl) resolveFilter(filter models.ListFilter) models.ListFilter {
+	if filter.Overdue != nil && filter.OverdueAt.IsZero() {
+		filter.OverdueAt = s.now()
+	}
+	return filter
+}
+
+// ListTodos retrieves the todos matching the given filter, pinned todos first. The slice
+// may be shared with other callers and must not be modified.
+func (s *TodoServiceImpl) ListTodos(filter models.ListFilter) ([]models.Todo, error) {
+	todos, err := s.repository.List(s.resolveFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+	return models.PinnedFirst(todos), nil
+}
+
+// ErrNoActionableTodo is returned by NextActionable when every todo is completed
+var ErrNoActionableTodo = errors.New("no actionable todo")
+
+// NextActionable returns the open todo to work on next as ranked by models.CompareActionable
+func (s *TodoServiceImpl) NextActionable() (*models.Todo, error) {
+	completed := false
+	todos, err := s.repository.List(models.ListFilter{Completed: &completed})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+	if len(todos) == 0 {
+		return nil, ErrNoActionableTodo
+	}
+	next := slices.MinFunc(todos, models.CompareActionable)
+	return &next, nil
+}
+
+// StaleTodos returns the open todos not updated within olderThan, least recently updated first
+func (s *TodoServiceImpl) StaleTodos(olderThan time.Duration) ([]models.Todo, error) {
+	if olderThan <= 0 {
+		return nil, errors.New("validation failed: stale age must be positive")
+	}
+
+	completed := false
+	todos, err := s.repository.List(models.ListFilter{Completed: &completed})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+
+	cutoff := s.now().Add(-olderThan)
+	stale := make([]models.Todo, 0)
+	for _, todo := range todos {
+		if todo.UpdatedAt.Before(cutoff) {
+			stale = append(stale, todo)
+		}
+	}
+	slices.SortStableFunc(stale, func(a, b models.Todo) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	return stale, nil
+}
+
+// Related todo limits applied when the caller's limit is unset or too large
+const (
+	DefaultRelatedLimit = 10
+	MaxRelatedLimit     = 100
+)
+
+// RelatedTodos returns the todos sharing the most tags with the given one, excluding completed
+// todos unless includeCompleted is set. A todo without tags has no related todos.
+func (s *TodoServiceImpl) RelatedTodos(id int, limit int, includeCompleted bool) ([]models.Todo, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+	if limit <= 0 {
+		limit = DefaultRelatedLimit
+	}
+	limit = min(limit, MaxRelatedLimit)
+
+	todo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+	if len(todo.Tags) == 0 {
+		return []models.Todo{}, nil
+	}
+
+	var filter models.ListFilter
+	if !includeCompleted {
+		completed := false
+		filter.Completed = &completed
+	}
+	candidates, err := s.repository.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+	return models.RankRelated(*todo, candidates, limit), nil
+}
+
+// CountTodos returns the number of todos matching the given filter
+func (s *TodoServiceImpl) CountTodos(filter models.ListFilter) (int, error) {
+	count, err := s.repository.Count(s.resolveFilter(filter))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count todos: %w", err)
+	}
+	return count, nil
+}
+
+// StreamFiltered writes the todos matching the filter to w as a JSON array without
+// materializing the full result
+func (s *TodoServiceImpl) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	if err := s.repository.StreamFiltered(w, s.resolveFilter(filter)); err != nil {
+		return fmt.Errorf("failed to stream todos: %w", err)
+	}
+	return nil
+}
+
 // GetTodoByID retrieves a specific todo by its ID
 func (s *TodoServiceImpl) GetTodoByID(id int) (*models.Todo, error) {
 	if id <= 0 {
@@ -69,36 +468,199 @@ func (s *TodoServiceImpl) GetTodoByID(id int) (*models.Todo, error) {
 	return todo, nil
 }
 
-// CreateTodo creates a new todo with the provided title and description
-func (s *TodoServiceImpl) CreateTodo(title, description string) (*models.Todo, error) {
+// CreateTodo creates a new todo from the provided input
+func (s *TodoServiceImpl) CreateTodo(input CreateTodoInput) (*models.Todo, error) {
+	todo, err := s.prepareTodo(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to repository
+	if err := s.repository.Create(todo); err != nil {
+		return nil, fmt.Errorf("failed to create todo: %w", err)
+	}
+
+	created := *todo
+	s.recordAudit(audit.ActionCreate, todo.ID, nil, &created)
+
+	if todo.Completed {
+		s.archiveImmediately()
+	}
+
+	return todo, nil
+}
+
+// NormalizeTodos runs each input through the same defaults, normalization and validation
+// as CreateTodo without saving anything, reporting the todo that would be stored or why
+// the input would be rejected
+func (s *TodoServiceImpl) NormalizeTodos(inputs []CreateTodoInput) []NormalizeResult {
+	results := make([]NormalizeResult, len(inputs))
+	for i, input := range inputs {
+		results[i].Todo, results[i].Err = s.prepareTodo(input)
+	}
+	return results
+}
+
+// prepareTodo builds the todo CreateTodo stores for input: defaults are applied, fields
+// are trimmed and normalized, and the result is validated. Server-assigned fields such as
+// the ID and timestamps are left for the repository.
+func (s *TodoServiceImpl) prepareTodo(input CreateTodoInput) (*models.Todo, error) {
+	if input.Source == "" || input.Source == models.SourceAPI {
+		input = s.applyCreateDefaults(input)
+	}
+	if s.config.AutoTitle && strings.TrimSpace(input.Title) == "" {
+		input.Title = models.TitleFromDescription(input.Description)
+	}
+
 	// Validate input
-	if err := s.validateTodoInput(title, description); err != nil {
+	if err := s.validateTodoInput(input.Title, input.Description); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	source := input.Source
+	if source == "" {
+		source = models.SourceAPI
+	}
+	if err := models.ValidateSource(source); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := s.validateExpiry(nil, input.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	var tags []string
+	if len(input.Tags) > 0 {
+		tags = models.NormalizeTags(input.Tags)
+		if err := models.ValidateTags(tags); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
 
-	// Create new todo
 	todo := &models.Todo{
-		Title:       strings.TrimSpace(title),
-		Description: strings.TrimSpace(description),
-		Completed:   false,
+		Title:       strings.TrimSpace(input.Title),
+		Description: s.normalizeDescription(input.Description),
+		Completed:   input.Completed,
+		DueDate:     input.DueDate,
+		ExpiresAt:   input.ExpiresAt,
+		Source:      source,
+		Tags:        tags,
+		Pinned:      input.Pinned,
+	}
+	if err := s.applyHashtags(todo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkCompletionRequirements(&models.Todo{}, todo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := s.checkTodoSize(todo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return todo, nil
+}
 
-	// Save to repository
-	if err := s.repository.Create(todo); err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+// checkTodoSize enforces MaxTodoBytes on the JSON encoding of a todo about to be stored
+func (s *TodoServiceImpl) checkTodoSize(todo *models.Todo) error {
+	if s.config.MaxTodoBytes <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return fmt.Errorf("failed to encode todo: %w", err)
+	}
+	if len(data) > s.config.MaxTodoBytes {
+		return &models.LimitError{Field: "todo", Unit: models.LimitUnitBytes, Max: s.config.MaxTodoBytes, Actual: len(data)}
 	}
+	return nil
+}
 
-	return todo, nil
+// applyHashtags adds the hashtags in the todo's title to its tags when AutoTag is set,
+// removing them from the title when StripHashtags is also set
+func (s *TodoServiceImpl) applyHashtags(todo *models.Todo) error {
+	if !s.config.AutoTag {
+		return nil
+	}
+	hashtags, stripped := models.ExtractHashtags(todo.Title)
+	if len(hashtags) == 0 {
+		return nil
+	}
+	if s.config.StripHashtags {
+		if stripped == "" {
+			return errors.New("title must contain more than hashtags")
+		}
+		todo.Title = stripped
+	}
+	todo.Tags = models.ApplyTagChanges(todo.Tags, hashtags, nil)
+	return models.ValidateTags(todo.Tags)
+}
+
+// applyCreateDefaults fills the fields input leaves unset from the configured defaults
+func (s *TodoServiceImpl) applyCreateDefaults(input CreateTodoInput) CreateTodoInput {
+	defaults := s.config.CreateDefaults
+	if input.Description == "" {
+		input.Description = defaults.Description
+	}
+	if len(input.Tags) == 0 {
+		input.Tags = defaults.Tags
+	}
+	if !input.Pinned {
+		input.Pinned = defaults.Pinned
+	}
+	if input.DueDate == nil && defaults.DueIn > 0 {
+		due := s.now().Add(defaults.DueIn)
+		input.DueDate = &due
+	}
+	return input
+}
+
+// ImportTodos creates a new todo from each exported one, in order, with fresh IDs and the
+// import source, saving them all at once. Completion, tags and pinning are carried over;
+// other server-managed fields such as timestamps and version start afresh. Each todo is
+// validated in full. The todos before the first rejected one are still imported, and the
+// count returned is how many were, so it is also the index of the rejected todo.
+func (s *TodoServiceImpl) ImportTodos(todos []models.Todo) (int, error) {
+	prepared := make([]*models.Todo, 0, len(todos))
+	var rejected error
+	for _, todo := range todos {
+		input := CreateTodoInput{
+			Title:       todo.Title,
+			Description: todo.Description,
+			DueDate:     todo.DueDate,
+			Source:      models.SourceImport,
+			Tags:        todo.Tags,
+			Pinned:      todo.Pinned,
+			Completed:   todo.Completed,
+		}
+		p, err := s.prepareTodo(input)
+		if err != nil {
+			rejected = err
+			break
+		}
+		prepared = append(prepared, p)
+	}
+
+	if err := s.repository.CreateBatch(prepared); err != nil {
+		return 0, fmt.Errorf("failed to import todos: %w", err)
+	}
+
+	completed := false
+	for _, todo := range prepared {
+		created := *todo
+		s.recordAudit(audit.ActionCreate, todo.ID, nil, &created)
+		completed = completed || todo.Completed
+	}
+	if completed {
+		s.archiveImmediately()
+	}
+
+	return len(prepared), rejected
 }
 
 // UpdateTodo updates an existing todo with new values
-func (s *TodoServiceImpl) UpdateTodo(id int, title, description string, completed bool) (*models.Todo, error) {
+func (s *TodoServiceImpl) UpdateTodo(id int, input UpdateTodoInput) (*models.Todo, error) {
 	if id <= 0 {
 		return nil, errors.New("invalid todo ID: ID must be a positive integer")
 	}
 
 	// Validate input
-	if err := s.validateTodoInput(title, description); err != nil {
+	if err := s.validateTodoInput(input.Title, input.Description); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -109,38 +671,664 @@ func (s *TodoServiceImpl) UpdateTodo(id int, title, description string, complete
 	}
 
 	// Create updated todo with new values
-	updatedTodo := &models.Todo{
-		ID:          existingTodo.ID,
-		Title:       strings.TrimSpace(title),
-		Description: strings.TrimSpace(description),
-		Completed:   completed,
-		CreatedAt:   existingTodo.CreatedAt, // Preserve original creation time
+	updatedTodo := s.buildUpdatedTodo(existingTodo, input)
+	if err := s.applyHashtags(updatedTodo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	return s.validateAndSaveUpdate(existingTodo, updatedTodo)
+}
+
+// validateAndSaveUpdate applies the rules that depend on the todo's previous state, then
+// persists the update
+func (s *TodoServiceImpl) validateAndSaveUpdate(existingTodo, updatedTodo *models.Todo) (*models.Todo, error) {
+	if err := s.validateUpdate(existingTodo, updatedTodo); err != nil {
+		return nil, err
+	}
+
+	return s.saveUpdate(existingTodo, updatedTodo)
+}
+
+// validateUpdate applies the rules that depend on the todo's previous state
+func (s *TodoServiceImpl) validateUpdate(existingTodo, updatedTodo *models.Todo) error {
+	if err := s.checkCompletionRequirements(existingTodo, updatedTodo); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.validateExpiry(existingTodo.ExpiresAt, updatedTodo.ExpiresAt); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkTodoSize(updatedTodo); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	return nil
+}
+
+// saveUpdate persists an updated todo and records the change in the audit log
+func (s *TodoServiceImpl) saveUpdate(existingTodo, updatedTodo *models.Todo) (*models.Todo, error) {
+	return s.saveUpdateIfVersion(existingTodo, updatedTodo, 0)
+}
+
+// saveUpdateIfVersion is saveUpdate for a change computed from a specific version of the
+// todo: the repository rejects it with models.ErrVersionConflict if the todo has changed
+// since. A zero version skips the check.
+func (s *TodoServiceImpl) saveUpdateIfVersion(existingTodo, updatedTodo *models.Todo, version int) (*models.Todo, error) {
 	// Update in repository
-	if err := s.repository.Update(id, updatedTodo); err != nil {
+	if err := s.repository.UpdateIfVersion(existingTodo.ID, version, updatedTodo); err != nil {
 		return nil, fmt.Errorf("failed to update todo: %w", err)
 	}
 
+	updated := *updatedTodo
+	s.recordAudit(audit.ActionUpdate, existingTodo.ID, existingTodo, &updated)
+
+	if updatedTodo.Completed && !existingTodo.Completed {
+		s.archiveImmediately()
+	}
+
 	return updatedTodo, nil
 }
 
-// DeleteTodo removes a todo by its ID
-func (s *TodoServiceImpl) DeleteTodo(id int) error {
+// SnoozeTodo defers a todo's due date by d, counting from the current due date
+// or from now when the todo has none
+func (s *TodoServiceImpl) SnoozeTodo(id int, d time.Duration) (*models.Todo, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+	if d <= 0 {
+		return nil, errors.New("validation failed: snooze duration must be positive")
+	}
+
+	existingTodo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+
+	base := s.now()
+	if existingTodo.DueDate != nil {
+		base = *existingTodo.DueDate
+	}
+	dueDate := base.Add(d)
+
+	updatedTodo := *existingTodo
+	updatedTodo.DueDate = &dueDate
+
+	return s.saveUpdate(existingTodo, &updatedTodo)
+}
+
+// BulkUpdateTodos applies many full-replacement updates in a single repository transaction.
+// Each item is checked against its expected version and the usual validation rules, and
+// the outcome of every item is reported individually.
+func (s *TodoServiceImpl) BulkUpdateTodos(items []models.BulkUpdateItem) ([]models.BulkUpdateResult, error) {
+	normalized := make([]models.BulkUpdateItem, len(items))
+	for i, item := range items {
+		item.Title = strings.TrimSpace(item.Title)
+		item.Description = s.normalizeDescription(item.Description)
+		normalized[i] = item
+	}
+
+	// Capture the pre-update state for auditing while the repository holds its lock
+	before := make(map[int]models.Todo)
+	validate := func(existing, proposed *models.Todo) error {
+		if err := s.validateTodoInput(proposed.Title, proposed.Description); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := s.applyHashtags(proposed); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := s.checkCompletionRequirements(existing, proposed); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		if err := s.checkTodoSize(proposed); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		before[existing.ID] = *existing
+		return nil
+	}
+
+	results, err := s.repository.UpdateBatch(normalized, validate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply bulk update: %w", err)
+	}
+
+	completed := false
+	for _, result := range results {
+		if result.Status == models.BulkStatusUpdated {
+			previous := before[result.ID]
+			updated := *result.Todo
+			s.recordAudit(audit.ActionUpdate, result.ID, &previous, &updated)
+			completed = completed || (updated.Completed && !previous.Completed)
+		}
+	}
+	if completed {
+		s.archiveImmediately()
+	}
+
+	return results, nil
+}
+
+// PinTodo pins a todo so it is listed ahead of unpinned todos
+func (s *TodoServiceImpl) PinTodo(id int) (*models.Todo, error) {
+	return s.setPinned(id, true)
+}
+
+// UnpinTodo removes a todo's pin
+func (s *TodoServiceImpl) UnpinTodo(id int) (*models.Todo, error) {
+	return s.setPinned(id, false)
+}
+
+// setPinned updates a todo's pinned flag, leaving it untouched when already in that state
+func (s *TodoServiceImpl) setPinned(id int, pinned bool) (*models.Todo, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+
+	existingTodo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+	if existingTodo.Pinned == pinned {
+		return existingTodo, nil
+	}
+
+	updatedTodo := *existingTodo
+	updatedTodo.Pinned = pinned
+
+	return s.saveUpdate(existingTodo, &updatedTodo)
+}
+
+// ErrChecklistItemNotFound is returned when a checklist index is outside the todo's checklist
+var ErrChecklistItemNotFound = errors.New("checklist item not found")
+
+// AddChecklistItem appends an open item with the given text to a todo's checklist
+func (s *TodoServiceImpl) AddChecklistItem(id int, text string) (*models.Todo, error) {
+	text = strings.TrimSpace(text)
+	if err := models.ValidateChecklistItemText(text); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return s.updateChecklist(id, false, func(checklist []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		maxItems := s.config.MaxChecklistItems
+		if maxItems <= 0 {
+			maxItems = models.DefaultMaxChecklistItems
+		}
+		if len(checklist) >= maxItems {
+			return nil, fmt.Errorf("validation failed: %w", &models.LimitError{Field: "checklist", Unit: models.LimitUnitItems, Max: maxItems, Actual: len(checklist) + 1})
+		}
+		return append(checklist, models.ChecklistItem{Text: text}), nil
+	})
+}
+
+// SetChecklistItemDone marks the checklist item at index done or not done; a nil done
+// toggles it. With AutoCompleteChecklist set, the todo's completed flag follows the checklist.
+func (s *TodoServiceImpl) SetChecklistItemDone(id, index int, done *bool) (*models.Todo, error) {
+	return s.updateChecklist(id, s.config.AutoCompleteChecklist, func(checklist []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		if index < 0 || index >= len(checklist) {
+			return nil, ErrChecklistItemNotFound
+		}
+		if done == nil {
+			checklist[index].Done = !checklist[index].Done
+		} else {
+			checklist[index].Done = *done
+		}
+		return checklist, nil
+	})
+}
+
+// RemoveChecklistItem deletes the checklist item at index; later items move up one place
+func (s *TodoServiceImpl) RemoveChecklistItem(id, index int) (*models.Todo, error) {
+	return s.updateChecklist(id, false, func(checklist []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		if index < 0 || index >= len(checklist) {
+			return nil, ErrChecklistItemNotFound
+		}
+		return slices.Delete(checklist, index, index+1), nil
+	})
+}
+
+// updateChecklist applies change to a copy of a todo's checklist and saves the result with
+// the same validation and auditing as other updates. With syncCompletion set, the todo is
+// completed when every remaining item is done and reopened otherwise.
+func (s *TodoServiceImpl) updateChecklist(id int, syncCompletion bool, change func([]models.ChecklistItem) ([]models.ChecklistItem, error)) (*models.Todo, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+
+	existingTodo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+
+	// The stored todo shares its checklist with existingTodo, so change a copy
+	checklist, err := change(slices.Clone(existingTodo.Checklist))
+	if err != nil {
+		return nil, err
+	}
+
+	updatedTodo := *existingTodo
+	updatedTodo.Checklist = checklist
+	if len(updatedTodo.Checklist) == 0 {
+		updatedTodo.Checklist = nil
+	}
+	if syncCompletion && len(updatedTodo.Checklist) > 0 {
+		done, total, _ := updatedTodo.ChecklistProgress()
+		updatedTodo.Completed = done == total
+	}
+
+	// A completion from the checklist must meet the same requirements as any other
+	return s.validateAndSaveUpdate(existingTodo, &updatedTodo)
+}
+
+// UpdateTagsBatch adds and removes tags across many todos, saving once. Tags are normalized
+// (trimmed, lowercased, deduplicated) and IDs without a todo are reported as missing.
+func (s *TodoServiceImpl) UpdateTagsBatch(ids []int, add, remove []string) (*models.TagBatchResult, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("validation failed: at least one ID is required")
+	}
+
+	add = models.NormalizeTags(add)
+	remove = models.NormalizeTags(remove)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.New("validation failed: at least one tag to add or remove is required")
+	}
+	if err := models.ValidateTags(add); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	uniqueIDs := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(uniqueIDs, id) {
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	// Check sizes and capture the pre-update state for auditing while the repository holds its lock
+	before := make(map[int]models.Todo)
+	capture := func(existing, proposed *models.Todo) error {
+		if err := s.checkTodoSize(proposed); err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+		before[existing.ID] = *existing
+		return nil
+	}
+
+	result, err := s.repository.UpdateTagsBatch(uniqueIDs, add, remove, capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	for _, todo := range result.Updated {
+		previous := before[todo.ID]
+		updated := todo
+		s.recordAudit(audit.ActionUpdate, todo.ID, &previous, &updated)
+	}
+
+	return result, nil
+}
+
+// PatchTodo applies JSON Patch operations to an existing todo and persists the result with
+// the same validation and auditing as UpdateTodo. Unlike an update it never goes through
+// UpdateTodoInput, so fields the patch doesn't touch, completion included, keep their values.
+// If the todo changes between applying the patch and saving it, the save fails with
+// models.ErrVersionConflict rather than overwriting the newer version.
+func (s *TodoServiceImpl) PatchTodo(id int, ops []models.PatchOperation) (*models.Todo, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+
+	existingTodo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+
+	patched, err := models.ApplyJSONPatch(*existingTodo, ops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	if err := s.validateTodoInput(patched.Title, patched.Description); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	patched.Title = strings.TrimSpace(patched.Title)
+	patched.Description = s.normalizeDescription(patched.Description)
+	if err := s.applyHashtags(&patched); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.validateUpdate(existingTodo, &patched); err != nil {
+		return nil, err
+	}
+
+	// The test operations ran against this version, so only save over that version
+	return s.saveUpdateIfVersion(existingTodo, &patched, existingTodo.Version)
+}
+
+// PreviewUpdate validates an update and reports which fields would change without persisting it
+func (s *TodoServiceImpl) PreviewUpdate(id int, input UpdateTodoInput) ([]models.FieldChange, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+
+	// Validate input
+	if err := s.validateTodoInput(input.Title, input.Description); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	existingTodo, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("todo not found: %w", err)
+	}
+
+	proposedTodo := s.buildUpdatedTodo(existingTodo, input)
+	if err := s.applyHashtags(proposedTodo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkCompletionRequirements(existingTodo, proposedTodo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.validateExpiry(existingTodo.ExpiresAt, proposedTodo.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkTodoSize(proposedTodo); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return models.DiffTodos(*existingTodo, *proposedTodo), nil
+}
+
+// checkCompletionRequirements enforces the configured required fields when a todo transitions to completed
+func (s *TodoServiceImpl) checkCompletionRequirements(existingTodo, updatedTodo *models.Todo) error {
+	if existingTodo.Completed || !updatedTodo.Completed {
+		return nil
+	}
+
+	for _, field := range s.config.CompletionRequiredFields {
+		switch field {
+		case CompletionFieldDescription:
+			if updatedTodo.Description == "" {
+				return errors.New("description is required to complete a todo")
+			}
+		case CompletionFieldDueDate:
+			if updatedTodo.DueDate == nil {
+				return errors.New("due date is required to complete a todo")
+			}
+		}
+	}
+	return nil
+}
+
+// buildUpdatedTodo applies normalized update values on top of an existing todo,
+// preserving fields that clients cannot edit directly
+func (s *TodoServiceImpl) buildUpdatedTodo(existingTodo *models.Todo, input UpdateTodoInput) *models.Todo {
+	updatedTodo := *existingTodo
+	updatedTodo.Title = strings.TrimSpace(input.Title)
+	updatedTodo.Description = s.normalizeDescription(input.Description)
+	updatedTodo.Completed = input.Completed
+	updatedTodo.DueDate = input.DueDate
+	updatedTodo.ExpiresAt = input.ExpiresAt
+	return &updatedTodo
+}
+
+// ErrDeleteIncomplete is returned when deleting an open todo while completion is required
+// before deletion and the delete is not forced
+var ErrDeleteIncomplete = errors.New("todo is not completed: complete it or force the delete")
+
+// DeleteTodo removes a todo by its ID. When RequireCompleteBeforeDelete is set, open todos
+// are only deleted if force is true.
+func (s *TodoServiceImpl) DeleteTodo(id int, force bool) error {
 	if id <= 0 {
 		return errors.New("invalid todo ID: ID must be a positive integer")
 	}
 
 	// Check if todo exists before attempting deletion
-	_, err := s.repository.GetByID(id)
+	existingTodo, err := s.repository.GetByID(id)
 	if err != nil {
 		return fmt.Errorf("todo not found: %w", err)
 	}
+	if s.config.RequireCompleteBeforeDelete && !existingTodo.Completed && !force {
+		return ErrDeleteIncomplete
+	}
 
 	// Delete from repository
 	if err := s.repository.Delete(id); err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
 
+	s.recordAudit(audit.ActionDelete, id, existingTodo, nil)
+
+	return nil
+}
+
+// DeleteExpired deletes every todo whose expiry has passed and returns how many were deleted
+func (s *TodoServiceImpl) DeleteExpired() (int, error) {
+	expired, err := s.repository.DeleteExpiredBy(s.now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired todos: %w", err)
+	}
+
+	for i := range expired {
+		s.recordAudit(audit.ActionDelete, expired[i].ID, &expired[i], nil)
+	}
+	return len(expired), nil
+}
+
+// ReopenCompletedBefore reopens every todo completed before the cutoff, clearing its
+// completion time, in a single save, and returns how many were reopened. A todo changed
+// concurrently is left as it is.
+func (s *TodoServiceImpl) ReopenCompletedBefore(before time.Time) (int, error) {
+	completed := true
+	todos, err := s.repository.List(models.ListFilter{Completed: &completed})
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+
+	items := make([]models.BulkUpdateItem, 0)
+	for _, todo := range todos {
+		if todo.CompletedAt == nil || !todo.CompletedAt.Before(before) {
+			continue
+		}
+		items = append(items, models.BulkUpdateItem{
+			ID:          todo.ID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   false,
+			DueDate:     todo.DueDate,
+			Version:     todo.Version,
+		})
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	// Capture the pre-update state for auditing while the repository holds its lock
+	previous := make(map[int]models.Todo)
+	results, err := s.repository.UpdateBatch(items, func(existing, proposed *models.Todo) error {
+		previous[existing.ID] = *existing
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to reopen todos: %w", err)
+	}
+
+	reopened := 0
+	for _, result := range results {
+		if result.Status != models.BulkStatusUpdated {
+			continue
+		}
+		before, updated := previous[result.ID], *result.Todo
+		s.recordAudit(audit.ActionUpdate, result.ID, &before, &updated)
+		reopened++
+	}
+	return reopened, nil
+}
+
+// PurgeBefore permanently deletes the todos whose field ("created_at" or "completed_at")
+// is before the cutoff, in a single save, and returns how many were removed
+func (s *TodoServiceImpl) PurgeBefore(before time.Time, field string) (int, error) {
+	if err := models.ValidatePurgeField(field); err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+
+	purged, err := s.repository.PurgeBefore(before, field)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge todos: %w", err)
+	}
+
+	for i := range purged {
+		s.recordAudit(audit.ActionDelete, purged[i].ID, &purged[i], nil)
+	}
+	return len(purged), nil
+}
+
+// ArchiveCompleted moves the todos completed at least ArchiveAfter ago to the archive and
+// returns how many were moved; it does nothing when archiving is disabled
+func (s *TodoServiceImpl) ArchiveCompleted() (int, error) {
+	if !s.config.ArchiveCompleted {
+		return 0, nil
+	}
+
+	archived, err := s.repository.ArchiveCompletedBy(s.now().Add(-s.config.ArchiveAfter))
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive todos: %w", err)
+	}
+
+	for i := range archived {
+		s.recordAudit(audit.ActionArchive, archived[i].ID, &archived[i], nil)
+	}
+	return len(archived), nil
+}
+
+// archiveImmediately archives newly completed todos when no archive delay is configured.
+// The completing update has already been saved, so a failure is logged rather than returned.
+func (s *TodoServiceImpl) archiveImmediately() {
+	if s.config.ArchiveAfter > 0 {
+		return
+	}
+	if _, err := s.ArchiveCompleted(); err != nil {
+		log.Printf("Failed to archive completed todos: %v", err)
+	}
+}
+
+// ErrArchiveUnavailable is returned when archived todos are requested but archiving is disabled
+var ErrArchiveUnavailable = errors.New("archive unavailable: archiving is disabled")
+
+// ListArchived returns the archived todos in the order they were archived
+func (s *TodoServiceImpl) ListArchived() ([]models.Todo, error) {
+	todos, err := s.repository.ListArchived()
+	if errors.Is(err, repository.ErrArchiveDisabled) {
+		return nil, ErrArchiveUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived todos: %w", err)
+	}
+	return todos, nil
+}
+
+// ErrHistoryUnavailable is returned when history is requested but audit logging is disabled
+var ErrHistoryUnavailable = errors.New("history unavailable: audit logging is disabled")
+
+// GetTodoHistory returns the recorded changes to a todo in chronological order.
+// Todos that were deleted keep their history; unknown IDs have an empty history.
+func (s *TodoServiceImpl) GetTodoHistory(id int) ([]audit.Entry, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid todo ID: ID must be a positive integer")
+	}
+	if s.config.AuditLogger == nil {
+		return nil, ErrHistoryUnavailable
+	}
+
+	entries, err := s.config.AuditLogger.History(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Burndown counts the todos created and completed per day or week between from and to
+func (s *TodoServiceImpl) Burndown(from, to time.Time, bucket string) ([]models.BurndownBucket, error) {
+	todos, err := s.repository.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+
+	buckets, err := models.ComputeBurndown(todos, from, to, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	return buckets, nil
+}
+
+// TagStats returns per-tag totals and completed counts across all todos
+func (s *TodoServiceImpl) TagStats() ([]models.TagStats, error) {
+	todos, err := s.repository.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todos: %w", err)
+	}
+	return models.ComputeTagStats(todos), nil
+}
+
+// CheckConsistency runs a read-only integrity check over the stored todos
+func (s *TodoServiceImpl) CheckConsistency() (*models.ConsistencyReport, error) {
+	report, err := s.repository.CheckConsistency()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check consistency: %w", err)
+	}
+	return report, nil
+}
+
+// VerifyStorage confirms the stored data survives a save and load round trip
+func (s *TodoServiceImpl) VerifyStorage() error {
+	if err := s.repository.VerifyRoundTrip(); err != nil {
+		return fmt.Errorf("storage round trip failed: %w", err)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// DiffSnapshot reports the todos added, removed and modified since an earlier snapshot
+func (s *TodoServiceImpl) DiffSnapshot(snapshot []models.Todo) (*models.DiffResult, error) {
+	todos, err := s.repository.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve todos: %w", err)
+	}
+	result := models.DiffStorage(snapshot, todos)
+	return &result, nil
+}
+
+// ListViews returns the saved views
+func (s *TodoServiceImpl) ListViews() ([]models.View, error) {
+	views, err := s.repository.ListViews()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve views: %w", err)
+	}
+	return views, nil
+}
+
+// GetView returns a saved view by name
+func (s *TodoServiceImpl) GetView(name string) (*models.View, error) {
+	view, err := s.repository.GetView(name)
+	if err != nil {
+		return nil, fmt.Errorf("view not found: %w", err)
+	}
+	return view, nil
+}
+
+// CreateView saves a named filter combination for later use with ?view=
+func (s *TodoServiceImpl) CreateView(view models.View) (*models.View, error) {
+	view.Query = strings.TrimSpace(view.Query)
+	if err := view.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.repository.CreateView(&view); err != nil {
+		return nil, fmt.Errorf("failed to create view: %w", err)
+	}
+	return &view, nil
+}
+
+// DeleteView removes a saved view by name
+func (s *TodoServiceImpl) DeleteView(name string) error {
+	if _, err := s.repository.GetView(name); err != nil {
+		return fmt.Errorf("view not found: %w", err)
+	}
+
+	if err := s.repository.DeleteView(name); err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
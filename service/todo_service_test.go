@@ -1,9 +1,16 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"go-crud-todo-list/audit"
 	"go-crud-todo-list/models"
+	"go-crud-todo-list/repository"
+	"io"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -12,8 +19,12 @@ import (
 type MockTodoRepository struct {
 	todos   map[int]*models.Todo
 	nextID  int
+	idMutex sync.Mutex
 	loadErr error
 	saveErr error
+	views   []models.View
+	// archived holds the todos moved out by ArchiveCompletedBy; nil means archiving is disabled
+	archived []models.Todo
 }
 
 // NewMockTodoRepository creates a new mock repository
@@ -47,6 +58,47 @@ func (m *MockTodoRepository) GetAll() ([]models.Todo, error) {
 	return todos, nil
 }
 
+// List returns the todos matching the filter from the mock repository
+func (m *MockTodoRepository) List(filter models.ListFilter) ([]models.Todo, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	
+	todos := make([]models.Todo, 0, len(m.todos))
+	for _, todo := range m.todos {
+		if filter.Matches(todo) {
+			todos = append(todos, *todo)
+		}
+	}
+	// Match the repository's insertion order, which map iteration doesn't preserve
+	slices.SortFunc(todos, func(a, b models.Todo) int { return a.ID - b.ID })
+	return todos, nil
+}
+
+// StreamFiltered writes the matching todos in the mock repository as a JSON array
+func (m *MockTodoRepository) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	todos, err := m.List(filter)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(todos)
+}
+
+// Count returns the number of todos matching the filter in the mock repository
+func (m *MockTodoRepository) Count(filter models.ListFilter) (int, error) {
+	if m.loadErr != nil {
+		return 0, m.loadErr
+	}
+	
+	count := 0
+	for _, todo := range m.todos {
+		if filter.Matches(todo) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // GetByID returns a specific todo by ID from the mock repository
 func (m *MockTodoRepository) GetByID(id int) (*models.Todo, error) {
 	if m.loadErr != nil {
@@ -75,11 +127,17 @@ func (m *MockTodoRepository) Create(todo *models.Todo) error {
 	}
 	
 	// Assign ID and timestamps
+	m.idMutex.Lock()
 	todo.ID = m.nextID
 	m.nextID++
+	m.idMutex.Unlock()
 	now := time.Now()
 	todo.CreatedAt = now
 	todo.UpdatedAt = now
+	todo.Version = 1
+	if todo.Completed {
+		todo.CompletedAt = &now
+	}
 	
 	// Store copy
 	todoCopy := *todo
@@ -88,6 +146,32 @@ func (m *MockTodoRepository) Create(todo *models.Todo) error {
 	return nil
 }
 
+// CreateBatch validates every todo before creating any of them
+func (m *MockTodoRepository) CreateBatch(todos []*models.Todo) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	for _, todo := range todos {
+		if err := todo.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, todo := range todos {
+		if err := m.Create(todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateIfVersion checks the stored version before delegating to Update
+func (m *MockTodoRepository) UpdateIfVersion(id, version int, todo *models.Todo) error {
+	if existingTodo, exists := m.todos[id]; exists && version != 0 && existingTodo.Version != version {
+		return fmt.Errorf("%w: expected %d, current is %d", models.ErrVersionConflict, version, existingTodo.Version)
+	}
+	return m.Update(id, todo)
+}
+
 // Update modifies an existing todo in the mock repository
 func (m *MockTodoRepository) Update(id int, todo *models.Todo) error {
 	if m.saveErr != nil {
@@ -105,10 +189,15 @@ func (m *MockTodoRepository) Update(id int, todo *models.Todo) error {
 		return err
 	}
 	
-	// Preserve ID and creation time, update timestamp
+	// Preserve ID and creation time, update timestamp and version
 	todo.ID = id
 	todo.CreatedAt = existingTodo.CreatedAt
 	todo.UpdatedAt = time.Now()
+	todo.Version = existingTodo.Version + 1
+	if todo.Completed && todo.CompletedAt == nil {
+		completedAt := todo.UpdatedAt
+		todo.CompletedAt = &completedAt
+	}
 	
 	// Store copy
 	todoCopy := *todo
@@ -117,6 +206,68 @@ func (m *MockTodoRepository) Update(id int, todo *models.Todo) error {
 	return nil
 }
 
+// UpdateBatch applies items in order with version and validation checks in the mock repository
+func (m *MockTodoRepository) UpdateBatch(items []models.BulkUpdateItem, validate repository.BatchValidator) ([]models.BulkUpdateResult, error) {
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	
+	results := make([]models.BulkUpdateResult, 0, len(items))
+	for _, item := range items {
+		existing, exists := m.todos[item.ID]
+		if !exists {
+			results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusNotFound})
+			continue
+		}
+		if item.Version != 0 && item.Version != existing.Version {
+			results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusConflict, Version: existing.Version})
+			continue
+		}
+		
+		proposed := &models.Todo{ID: item.ID, Title: item.Title, Description: item.Description, Completed: item.Completed}
+		err := proposed.Validate()
+		if err == nil && validate != nil {
+			err = validate(existing, proposed)
+		}
+		if err != nil {
+			results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusInvalid, Error: err.Error()})
+			continue
+		}
+		
+		m.Update(item.ID, proposed)
+		todoCopy := *proposed
+		results = append(results, models.BulkUpdateResult{ID: item.ID, Status: models.BulkStatusUpdated, Version: proposed.Version, Todo: &todoCopy})
+	}
+	return results, nil
+}
+
+// UpdateTagsBatch applies tag changes to every existing todo in the mock repository
+func (m *MockTodoRepository) UpdateTagsBatch(ids []int, add, remove []string, validate repository.BatchValidator) (*models.TagBatchResult, error) {
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	
+	result := &models.TagBatchResult{Updated: make([]models.Todo, 0), MissingIDs: make([]int, 0)}
+	for _, id := range ids {
+		existing, exists := m.todos[id]
+		if !exists {
+			result.MissingIDs = append(result.MissingIDs, id)
+			continue
+		}
+		
+		proposed := *existing
+		proposed.Tags = models.ApplyTagChanges(existing.Tags, add, remove)
+		if validate != nil {
+			if err := validate(existing, &proposed); err != nil {
+				return nil, err
+			}
+		}
+		m.Update(id, &proposed)
+		result.Updated = append(result.Updated, proposed)
+	}
+	return result, nil
+}
+
 // Delete removes a todo from the mock repository
 func (m *MockTodoRepository) Delete(id int) error {
 	if m.saveErr != nil {
@@ -131,6 +282,120 @@ func (m *MockTodoRepository) Delete(id int) error {
 	return nil
 }
 
+// PurgeBefore removes the todos before the cutoff from the mock repository
+func (m *MockTodoRepository) PurgeBefore(t time.Time, field string) ([]models.Todo, error) {
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	
+	storage := models.TodoStorage{}
+	for _, todo := range m.todos {
+		storage.Todos = append(storage.Todos, *todo)
+	}
+	purged, err := storage.PurgeBefore(t, field)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range purged {
+		delete(m.todos, todo.ID)
+	}
+	return purged, nil
+}
+
+// DeleteExpiredBy removes the expired todos from the mock repository
+func (m *MockTodoRepository) DeleteExpiredBy(now time.Time) ([]models.Todo, error) {
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	
+	storage := models.TodoStorage{}
+	for _, todo := range m.todos {
+		storage.Todos = append(storage.Todos, *todo)
+	}
+	expired := storage.TakeExpiredBy(now)
+	for _, todo := range expired {
+		delete(m.todos, todo.ID)
+	}
+	return expired, nil
+}
+
+// ArchiveCompletedBy moves completed todos into the mock archive
+func (m *MockTodoRepository) ArchiveCompletedBy(t time.Time) ([]models.Todo, error) {
+	if m.archived == nil {
+		return nil, repository.ErrArchiveDisabled
+	}
+	if m.saveErr != nil {
+		return nil, m.saveErr
+	}
+	
+	storage := models.TodoStorage{}
+	for _, todo := range m.todos {
+		storage.Todos = append(storage.Todos, *todo)
+	}
+	archived := storage.TakeCompletedBy(t)
+	for _, todo := range archived {
+		delete(m.todos, todo.ID)
+	}
+	m.archived = append(m.archived, archived...)
+	return archived, nil
+}
+
+// ListArchived returns the mock archive
+func (m *MockTodoRepository) ListArchived() ([]models.Todo, error) {
+	if m.archived == nil {
+		return nil, repository.ErrArchiveDisabled
+	}
+	return m.archived, nil
+}
+
+// CheckConsistency reports a consistent state for the mock repository
+func (m *MockTodoRepository) CheckConsistency() (*models.ConsistencyReport, error) {
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+	return &models.ConsistencyReport{Consistent: true, TodoCount: len(m.todos), NextID: m.nextID}, nil
+}
+
+// VerifyRoundTrip reports the load error, if any, for the mock repository
+func (m *MockTodoRepository) VerifyRoundTrip() error {
+	return m.loadErr
+}
+
+// ListViews returns the saved views from the mock repository
+func (m *MockTodoRepository) ListViews() ([]models.View, error) {
+	return append([]models.View{}, m.views...), nil
+}
+
+// GetView returns a saved view by name from the mock repository
+func (m *MockTodoRepository) GetView(name string) (*models.View, error) {
+	for _, view := range m.views {
+		if view.Name == name {
+			return &view, nil
+		}
+	}
+	return nil, fmt.Errorf("view %q not found", name)
+}
+
+// CreateView saves a view in the mock repository
+func (m *MockTodoRepository) CreateView(view *models.View) error {
+	if _, err := m.GetView(view.Name); err == nil {
+		return fmt.Errorf("view %q already exists", view.Name)
+	}
+	m.views = append(m.views, *view)
+	return nil
+}
+
+// DeleteView removes a saved view from the mock repository
+func (m *MockTodoRepository) DeleteView(name string) error {
+	for i, view := range m.views {
+		if view.Name == name {
+			m.views = append(m.views[:i], m.views[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("view %q not found", name)
+}
+
 // Save is a no-op for the mock repository
 func (m *MockTodoRepository) Save() error {
 	return m.saveErr
@@ -268,7 +533,7 @@ func TestCreateTodo(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
 	service := NewTodoService(mockRepo)
 	
-	todo, err := service.CreateTodo("Test Todo", "Test Description")
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Test Todo", Description: "Test Description"})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -304,7 +569,7 @@ func TestCreateTodo_ValidationErrors(t *testing.T) {
 	}
 	
 	for _, tc := range testCases {
-		_, err := service.CreateTodo(tc.title, tc.description)
+		_, err := service.CreateTodo(CreateTodoInput{Title: tc.title, Description: tc.description})
 		if err == nil {
 			t.Fatalf("Expected error for title '%s' and description length %d, got nil", tc.title, len(tc.description))
 		}
@@ -320,7 +585,7 @@ func TestCreateTodo_RepositoryError(t *testing.T) {
 	mockRepo.SetSaveError(errors.New("repository error"))
 	service := NewTodoService(mockRepo)
 	
-	_, err := service.CreateTodo("Valid Title", "Valid Description")
+	_, err := service.CreateTodo(CreateTodoInput{Title: "Valid Title", Description: "Valid Description"})
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
@@ -338,7 +603,7 @@ func TestUpdateTodo(t *testing.T) {
 	existingTodo := createTestTodo(1, "Original Title", "Original Description", false)
 	mockRepo.todos[1] = existingTodo
 	
-	updatedTodo, err := service.UpdateTodo(1, "Updated Title", "Updated Description", true)
+	updatedTodo, err := service.UpdateTodo(1, UpdateTodoInput{Title: "Updated Title", Description: "Updated Description", Completed: true})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -360,6 +625,37 @@ func TestUpdateTodo(t *testing.T) {
 	}
 }
 
+// TestPreviewUpdate tests that a dry-run update reports the edited fields without persisting
+func TestPreviewUpdate(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	created, err := service.CreateTodo(CreateTodoInput{Title: "Original Title", Description: "Same description"})
+	if err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	
+	changes, err := service.PreviewUpdate(created.ID, UpdateTodoInput{Title: "  New Title  ", Description: "Same description", Completed: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Field != "title" || changes[0].Old != "Original Title" || changes[0].New != "New Title" {
+		t.Errorf("Unexpected title change: %+v", changes[0])
+	}
+	if changes[1].Field != "completed" || changes[1].Old != false || changes[1].New != true {
+		t.Errorf("Unexpected completed change: %+v", changes[1])
+	}
+	
+	stored, _ := service.GetTodoByID(created.ID)
+	if stored.Title != "Original Title" || stored.Completed {
+		t.Errorf("Expected preview not to persist changes, got %+v", stored)
+	}
+}
+
 // TestUpdateTodo_InvalidID tests invalid ID validation for updates
 func TestUpdateTodo_InvalidID(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
@@ -368,7 +664,7 @@ func TestUpdateTodo_InvalidID(t *testing.T) {
 	testCases := []int{0, -1, -100}
 	
 	for _, id := range testCases {
-		_, err := service.UpdateTodo(id, "Valid Title", "Valid Description", false)
+		_, err := service.UpdateTodo(id, UpdateTodoInput{Title: "Valid Title", Description: "Valid Description"})
 		if err == nil {
 			t.Fatalf("Expected error for invalid ID %d, got nil", id)
 		}
@@ -383,7 +679,7 @@ func TestUpdateTodo_NotFound(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
 	service := NewTodoService(mockRepo)
 	
-	_, err := service.UpdateTodo(999, "Valid Title", "Valid Description", false)
+	_, err := service.UpdateTodo(999, UpdateTodoInput{Title: "Valid Title", Description: "Valid Description"})
 	if err == nil {
 		t.Fatal("Expected error for non-existent todo, got nil")
 	}
@@ -413,7 +709,7 @@ func TestUpdateTodo_ValidationErrors(t *testing.T) {
 	}
 	
 	for _, tc := range testCases {
-		_, err := service.UpdateTodo(1, tc.title, tc.description, false)
+		_, err := service.UpdateTodo(1, UpdateTodoInput{Title: tc.title, Description: tc.description})
 		if err == nil {
 			t.Fatalf("Expected error for title '%s' and description length %d, got nil", tc.title, len(tc.description))
 		}
@@ -432,7 +728,7 @@ func TestDeleteTodo(t *testing.T) {
 	testTodo := createTestTodo(1, "Test Todo", "Test Description", false)
 	mockRepo.todos[1] = testTodo
 	
-	err := service.DeleteTodo(1)
+	err := service.DeleteTodo(1, false)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -443,6 +739,32 @@ func TestDeleteTodo(t *testing.T) {
 	}
 }
 
+// TestDeleteTodo_RequireComplete tests that open todos are only deleted when forced
+func TestDeleteTodo_RequireComplete(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{RequireCompleteBeforeDelete: true})
+	
+	mockRepo.todos[1] = createTestTodo(1, "Open", "Still in progress", false)
+	mockRepo.todos[2] = createTestTodo(2, "Done", "Finished", true)
+	
+	if err := service.DeleteTodo(1, false); !errors.Is(err, ErrDeleteIncomplete) {
+		t.Fatalf("Expected ErrDeleteIncomplete for an open todo, got %v", err)
+	}
+	if _, exists := mockRepo.todos[1]; !exists {
+		t.Fatal("Expected the open todo to survive a blocked delete")
+	}
+	
+	if err := service.DeleteTodo(2, false); err != nil {
+		t.Fatalf("Expected a completed todo to be deleted, got %v", err)
+	}
+	if err := service.DeleteTodo(1, true); err != nil {
+		t.Fatalf("Expected a forced delete to succeed, got %v", err)
+	}
+	if len(mockRepo.todos) != 0 {
+		t.Fatalf("Expected no todos left, got %d", len(mockRepo.todos))
+	}
+}
+
 // TestDeleteTodo_InvalidID tests invalid ID validation for deletion
 func TestDeleteTodo_InvalidID(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
@@ -451,7 +773,7 @@ func TestDeleteTodo_InvalidID(t *testing.T) {
 	testCases := []int{0, -1, -100}
 	
 	for _, id := range testCases {
-		err := service.DeleteTodo(id)
+		err := service.DeleteTodo(id, false)
 		if err == nil {
 			t.Fatalf("Expected error for invalid ID %d, got nil", id)
 		}
@@ -466,7 +788,7 @@ func TestDeleteTodo_NotFound(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
 	service := NewTodoService(mockRepo)
 	
-	err := service.DeleteTodo(999)
+	err := service.DeleteTodo(999, false)
 	if err == nil {
 		t.Fatal("Expected error for non-existent todo, got nil")
 	}
@@ -487,7 +809,7 @@ func TestDeleteTodo_RepositoryError(t *testing.T) {
 	// Set repository error
 	mockRepo.SetSaveError(errors.New("repository error"))
 	
-	err := service.DeleteTodo(1)
+	err := service.DeleteTodo(1, false)
 	if err == nil {
 		t.Fatal("Expected error, got nil")
 	}
@@ -496,20 +818,1510 @@ func TestDeleteTodo_RepositoryError(t *testing.T) {
 	}
 }
 
-// TestTrimWhitespace tests that input is properly trimmed
-func TestTrimWhitespace(t *testing.T) {
+// TestCreateTodo_AutoTitle tests deriving a missing title from the description
+func TestCreateTodo_AutoTitle(t *testing.T) {
 	mockRepo := NewMockTodoRepository()
-	service := NewTodoService(mockRepo)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AutoTitle: true})
 	
-	todo, err := service.CreateTodo("  Test Todo  ", "  Test Description  ")
+	description := "\n  Call the   plumber about the leak\nThe kitchen sink drips overnight"
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "  ", Description: description})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
+	if todo.Title != "Call the plumber about the leak" {
+		t.Errorf("Expected title from the first line, got %q", todo.Title)
+	}
+	if todo.Description != strings.TrimSpace(description) {
+		t.Errorf("Expected the description to be kept, got %q", todo.Description)
+	}
 	
-	if todo.Title != "Test Todo" {
-		t.Fatalf("Expected trimmed title 'Test Todo', got '%s'", todo.Title)
+	long := strings.Repeat("word ", 40)
+	todo, err = service.CreateTodo(CreateTodoInput{Description: long})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
 	}
-	if todo.Description != "Test Description" {
-		t.Fatalf("Expected trimmed description 'Test Description', got '%s'", todo.Description)
+	if n := len([]rune(todo.Title)); n > models.AutoTitleMaxLength || !strings.HasSuffix(todo.Title, "word…") {
+		t.Errorf("Expected a title cut at a word boundary within %d characters, got %q (%d)", models.AutoTitleMaxLength, todo.Title, n)
+	}
+	
+	todo, err = service.CreateTodo(CreateTodoInput{Title: "Explicit", Description: "Ignored for the title"})
+	if err != nil || todo.Title != "Explicit" {
+		t.Errorf("Expected an explicit title to be kept, got %v, %v", todo, err)
+	}
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Description: " \n "}); err == nil || !strings.Contains(err.Error(), "title is required") {
+		t.Errorf("Expected a blank description to still require a title, got %v", err)
+	}
+	
+	if _, err := NewTodoService(mockRepo).CreateTodo(CreateTodoInput{Description: description}); err == nil {
+		t.Error("Expected an empty title to be rejected without AUTO_TITLE")
+	}
+}
+
+// TestAutoTag tests adding title hashtags to the tags on create and update
+func TestAutoTag(t *testing.T) {
+	testCases := []struct {
+		name          string
+		strip         bool
+		expectedTitle string
+	}{
+		{name: "keep hashtags in title", strip: false, expectedTitle: "Buy milk #grocery #Urgent"},
+		{name: "strip hashtags from title", strip: true, expectedTitle: "Buy milk"},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := NewMockTodoRepository()
+			service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AutoTag: true, StripHashtags: tc.strip})
+			
+			todo, err := service.CreateTodo(CreateTodoInput{Title: "Buy milk #grocery #Urgent", Tags: []string{"home"}})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(todo.Tags, []string{"home", "grocery", "urgent"}) {
+				t.Errorf("Expected tags [home grocery urgent], got %v", todo.Tags)
+			}
+			if todo.Title != tc.expectedTitle {
+				t.Errorf("Expected title %q, got %q", tc.expectedTitle, todo.Title)
+			}
+			
+			updated, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: "Buy oat milk #dairy"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(updated.Tags, []string{"home", "grocery", "urgent", "dairy"}) {
+				t.Errorf("Expected the update to add dairy, got %v", updated.Tags)
+			}
+		})
+	}
+}
+
+// TestAutoTag_NotHashtags tests text that only looks like hashtags
+func TestAutoTag_NotHashtags(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AutoTag: true, StripHashtags: true})
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Learn C# from issue#12 # later"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(todo.Tags) != 0 || todo.Title != "Learn C# from issue#12 # later" {
+		t.Errorf("Expected no tags and an unchanged title, got %q %v", todo.Title, todo.Tags)
+	}
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "#only #tags"}); err == nil || !strings.Contains(err.Error(), "more than hashtags") {
+		t.Errorf("Expected a title of only hashtags to be rejected, got %v", err)
+	}
+	
+	if todo, err := NewTodoService(mockRepo).CreateTodo(CreateTodoInput{Title: "Buy milk #grocery"}); err != nil || len(todo.Tags) != 0 {
+		t.Errorf("Expected no tags without AUTO_TAG, got %v, %v", todo, err)
+	}
+}
+
+// TestValidation_LimitError tests that length errors carry the limit and actual length
+func TestValidation_LimitError(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{MaxDescriptionLength: 10, MaxDescriptionLines: 2})
+	
+	testCases := []struct {
+		input    CreateTodoInput
+		expected models.LimitError
+	}{
+		{CreateTodoInput{Title: strings.Repeat("a", 250)}, models.LimitError{Field: "title", Unit: "characters", Max: 200, Actual: 250}},
+		{CreateTodoInput{Title: "Title", Description: "twelve chars"}, models.LimitError{Field: "description", Unit: "characters", Max: 10, Actual: 12}},
+		{CreateTodoInput{Title: "Title", Description: "a\nb\nc"}, models.LimitError{Field: "description", Unit: "lines", Max: 2, Actual: 3}},
+		{CreateTodoInput{Title: "Title", Tags: []string{strings.Repeat("t", 51)}}, models.LimitError{Field: "tag", Value: strings.Repeat("t", 51), Unit: "characters", Max: 50, Actual: 51}},
+	}
+	
+	for _, tc := range testCases {
+		_, err := service.CreateTodo(tc.input)
+		var limitErr *models.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("Expected a LimitError, got %v", err)
+		}
+		if *limitErr != tc.expected {
+			t.Errorf("Expected %+v, got %+v", tc.expected, *limitErr)
+		}
+	}
+}
+
+// TestMaxTodoBytes tests the combined size limit on todos whose fields are each within limits
+func TestMaxTodoBytes(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{MaxTodoBytes: 600})
+	
+	title := strings.Repeat("t", 150)
+	description := strings.Repeat("d", 400)
+	
+	_, err := service.CreateTodo(CreateTodoInput{Title: title, Description: description})
+	var limitErr *models.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Field != "todo" || limitErr.Max != 600 || limitErr.Actual <= 600 {
+		t.Fatalf("Expected the combined size limit to reject the todo, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "validation failed: todo must be 600 bytes or less") {
+		t.Errorf("Expected a validation error naming the limit, got %v", err)
+	}
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: title, Description: "Short"})
+	if err != nil {
+		t.Fatalf("Expected a todo within the combined limit to be created, got %v", err)
+	}
+	if _, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: title, Description: description}); !errors.As(err, &limitErr) {
+		t.Errorf("Expected an update over the combined limit to be rejected, got %v", err)
+	}
+	
+	if _, err := NewTodoService(mockRepo).CreateTodo(CreateTodoInput{Title: title, Description: description}); err != nil {
+		t.Errorf("Expected no combined limit by default, got %v", err)
+	}
+}
+
+// TestChecklist tests adding, toggling and removing checklist items
+func TestChecklist(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{MaxChecklistItems: 3})
+	mockRepo.todos[1] = createTestTodo(1, "Move house", "", false)
+	
+	for _, text := range []string{"  Pack books  ", "Pack kitchen", "Return keys"} {
+		if _, err := service.AddChecklistItem(1, text); err != nil {
+			t.Fatalf("Failed to add %q: %v", text, err)
+		}
+	}
+	var limitErr *models.LimitError
+	if _, err := service.AddChecklistItem(1, "Cancel internet"); !errors.As(err, &limitErr) || limitErr.Max != 3 {
+		t.Errorf("Expected the checklist item limit to be enforced, got %v", err)
+	}
+	if _, err := service.AddChecklistItem(1, strings.Repeat("a", models.MaxChecklistItemLength+1)); !errors.As(err, &limitErr) {
+		t.Errorf("Expected item text over the length limit to be rejected, got %v", err)
+	}
+	
+	before := mockRepo.todos[1]
+	done := true
+	todo, err := service.SetChecklistItemDone(1, 1, &done)
+	if err != nil {
+		t.Fatalf("Failed to mark item done: %v", err)
+	}
+	if before.Checklist[1].Done {
+		t.Error("Expected the previous todo's checklist not to change")
+	}
+	if todo, err = service.SetChecklistItemDone(1, 0, nil); err != nil {
+		t.Fatalf("Failed to toggle item: %v", err)
+	}
+	if completed, total, ratio := todo.ChecklistProgress(); completed != 2 || total != 3 || ratio != 2.0/3 {
+		t.Errorf("Expected 2 of 3 done, got %d of %d (%v)", completed, total, ratio)
+	}
+	if todo.Checklist[0].Text != "Pack books" {
+		t.Errorf("Expected trimmed item text, got %q", todo.Checklist[0].Text)
+	}
+	
+	if todo, err = service.RemoveChecklistItem(1, 0); err != nil {
+		t.Fatalf("Failed to remove item: %v", err)
+	}
+	if len(todo.Checklist) != 2 || todo.Checklist[0].Text != "Pack kitchen" {
+		t.Errorf("Expected the first item removed, got %+v", todo.Checklist)
+	}
+	
+	if _, err := service.SetChecklistItemDone(1, 2, nil); !errors.Is(err, ErrChecklistItemNotFound) {
+		t.Errorf("Expected ErrChecklistItemNotFound, got %v", err)
+	}
+	if _, err := service.AddChecklistItem(99, "Pack"); err == nil || !strings.Contains(err.Error(), "todo not found") {
+		t.Errorf("Expected todo not found, got %v", err)
+	}
+}
+
+// TestChecklist_AutoComplete tests that the todo's completed flag follows its checklist
+func TestChecklist_AutoComplete(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AutoCompleteChecklist: true})
+	todo := createTestTodo(1, "Move house", "", false)
+	todo.Checklist = []models.ChecklistItem{{Text: "Pack books"}, {Text: "Return keys"}}
+	mockRepo.todos[1] = todo
+	
+	steps := []struct {
+		index     int
+		done      bool
+		completed bool
+	}{
+		{0, true, false},
+		{1, true, true},
+		{0, false, false},
+		{0, true, true},
+	}
+	for _, step := range steps {
+		updated, err := service.SetChecklistItemDone(1, step.index, &step.done)
+		if err != nil {
+			t.Fatalf("Failed to set item %d done=%v: %v", step.index, step.done, err)
+		}
+		if updated.Completed != step.completed || mockRepo.todos[1].Completed != step.completed {
+			t.Errorf("After setting item %d done=%v, expected completed=%v, got %v", step.index, step.done, step.completed, updated.Completed)
+		}
+	}
+	
+	// Without the option, checking every item leaves the todo open
+	service = NewTodoService(mockRepo)
+	mockRepo.todos[1] = todo
+	for i := range todo.Checklist {
+		if _, err := service.SetChecklistItemDone(1, i, nil); err != nil {
+			t.Fatalf("Failed to toggle item %d: %v", i, err)
+		}
+	}
+	if mockRepo.todos[1].Completed {
+		t.Error("Expected the todo to stay open without AutoCompleteChecklist")
+	}
+}
+
+// TestChecklist_AutoCompleteRequiredFields tests that completing a todo from its checklist
+// still requires the configured completion fields
+func TestChecklist_AutoCompleteRequiredFields(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		AutoCompleteChecklist:    true,
+		CompletionRequiredFields: []string{CompletionFieldDescription},
+	})
+	todo := createTestTodo(1, "Move house", "", false)
+	todo.Checklist = []models.ChecklistItem{{Text: "Pack books"}}
+	mockRepo.todos[1] = todo
+	
+	_, err := service.SetChecklistItemDone(1, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "description is required") {
+		t.Fatalf("Expected the completion to require a description, got %v", err)
+	}
+	if stored := mockRepo.todos[1]; stored.Completed || stored.Checklist[0].Done {
+		t.Errorf("Expected the todo to be left unchanged, got %+v", stored)
+	}
+	
+	mockRepo.todos[1].Description = "Boxes are in the garage"
+	updated, err := service.SetChecklistItemDone(1, 0, nil)
+	if err != nil {
+		t.Fatalf("Expected the completion to succeed with a description, got %v", err)
+	}
+	if !updated.Completed {
+		t.Error("Expected the todo to be completed")
+	}
+}
+
+// TestListTodos_OverdueUsesClock tests that the overdue filter is evaluated at the service's clock
+func TestListTodos_OverdueUsesClock(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{Now: func() time.Time { return now }})
+	
+	due := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+	todo, _ := service.CreateTodo(CreateTodoInput{Title: "Due tomorrow", DueDate: &due})
+	
+	overdue := true
+	todos, err := service.ListTodos(models.ListFilter{Overdue: &overdue})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(todos) != 0 {
+		t.Errorf("Expected nothing overdue before the due date, got %+v", todos)
+	}
+	
+	now = due.Add(time.Hour)
+	todos, _ = service.ListTodos(models.ListFilter{Overdue: &overdue})
+	if len(todos) != 1 || todos[0].ID != todo.ID {
+		t.Errorf("Expected the todo overdue once the clock passes its due date, got %+v", todos)
+	}
+	if count, _ := service.CountTodos(models.ListFilter{Overdue: &overdue}); count != 1 {
+		t.Errorf("Expected a count of 1, got %d", count)
 	}
-}
\ No newline at end of file
+}
+
+// sharedListRepository returns the same slice from every List call, as the file-based
+// repository does with its cached snapshot
+type sharedListRepository struct {
+	*MockTodoRepository
+	shared []models.Todo
+}
+
+func (r *sharedListRepository) List(filter models.ListFilter) ([]models.Todo, error) {
+	return r.shared, nil
+}
+
+// TestListTodos_LeavesSharedSliceAlone tests that ordering pinned todos first never
+// reorders the slice the repository shares between callers
+func TestListTodos_LeavesSharedSliceAlone(t *testing.T) {
+	repo := &sharedListRepository{
+		MockTodoRepository: NewMockTodoRepository(),
+		shared:             []models.Todo{{ID: 1, Title: "Unpinned"}, {ID: 2, Title: "Pinned", Pinned: true}},
+	}
+	service := NewTodoService(repo)
+	
+	todos, err := service.ListTodos(models.ListFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != 2 || todos[1].ID != 1 {
+		t.Errorf("Expected the pinned todo first, got %+v", todos)
+	}
+	if repo.shared[0].ID != 1 || repo.shared[1].ID != 2 {
+		t.Errorf("Expected the shared slice left in its original order, got %+v", repo.shared)
+	}
+}
+
+// TestTrimWhitespace tests that input is properly trimmed
+func TestTrimWhitespace(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "  Test Todo  ", Description: "  Test Description  "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if todo.Title != "Test Todo" {
+		t.Fatalf("Expected trimmed title 'Test Todo', got '%s'", todo.Title)
+	}
+	if todo.Description != "Test Description" {
+		t.Fatalf("Expected trimmed description 'Test Description', got '%s'", todo.Description)
+	}
+}
+
+// recordingAuditLogger captures audit entries in memory and can simulate failures
+type recordingAuditLogger struct {
+	entries []audit.Entry
+	err     error
+}
+
+func (l *recordingAuditLogger) Log(entry audit.Entry) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *recordingAuditLogger) History(todoID int) ([]audit.Entry, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	entries := make([]audit.Entry, 0)
+	for _, entry := range l.entries {
+		if entry.TodoID == todoID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// TestAuditLog_RecordsMutations tests that each mutation produces an audit entry
+func TestAuditLog_RecordsMutations(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger, Now: func() time.Time { return now }})
+	
+	created, err := service.CreateTodo(CreateTodoInput{Title: "Audited", Description: "Before"})
+	if err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	if _, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Audited", Description: "After", Completed: true}); err != nil {
+		t.Fatalf("Failed to update todo: %v", err)
+	}
+	if err := service.DeleteTodo(created.ID, false); err != nil {
+		t.Fatalf("Failed to delete todo: %v", err)
+	}
+	
+	if len(logger.entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(logger.entries))
+	}
+	
+	create, update, del := logger.entries[0], logger.entries[1], logger.entries[2]
+	if create.Action != audit.ActionCreate || create.Before != nil || create.After == nil {
+		t.Errorf("Unexpected create entry: %+v", create)
+	}
+	if update.Action != audit.ActionUpdate || update.Before.Description != "Before" || update.After.Description != "After" {
+		t.Errorf("Unexpected update entry: %+v", update)
+	}
+	if del.Action != audit.ActionDelete || del.Before == nil || del.After != nil {
+		t.Errorf("Unexpected delete entry: %+v", del)
+	}
+	for _, entry := range logger.entries {
+		if entry.TodoID != created.ID || !entry.Timestamp.Equal(now) {
+			t.Errorf("Expected entry for todo %d stamped by the service clock, got %+v", created.ID, entry)
+		}
+	}
+}
+
+// TestAuditLog_FailureDoesNotFailOperation tests that audit errors are not propagated
+func TestAuditLog_FailureDoesNotFailOperation(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{err: errors.New("disk full")}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger})
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Still created"}); err != nil {
+		t.Errorf("Expected create to succeed despite audit failure, got %v", err)
+	}
+}
+
+// TestUpdateTodo_CompletionRequiresDescription tests that completion is blocked until a description is present
+func TestUpdateTodo_CompletionRequiresDescription(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		CompletionRequiredFields: []string{CompletionFieldDescription},
+	})
+	
+	created, err := service.CreateTodo(CreateTodoInput{Title: "Needs context"})
+	if err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	
+	_, err = service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Needs context", Description: "   ", Completed: true})
+	if err == nil {
+		t.Fatal("Expected completion without description to fail")
+	}
+	if !strings.Contains(err.Error(), "validation failed") || !strings.Contains(err.Error(), "description is required") {
+		t.Errorf("Expected description validation error, got %v", err)
+	}
+	
+	// Editing without completing is still allowed
+	if _, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Still open"}); err != nil {
+		t.Errorf("Expected non-completing update to succeed, got %v", err)
+	}
+	
+	updated, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Needs context", Description: "Done because of reasons", Completed: true})
+	if err != nil {
+		t.Fatalf("Expected completion with description to succeed, got %v", err)
+	}
+	if !updated.Completed {
+		t.Error("Expected todo to be completed")
+	}
+}
+
+// TestUpdateTodo_CompletionRequiresDueDate tests that completion is blocked until a due date is set
+func TestUpdateTodo_CompletionRequiresDueDate(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		CompletionRequiredFields: []string{CompletionFieldDueDate},
+	})
+	
+	created, err := service.CreateTodo(CreateTodoInput{Title: "Needs a deadline"})
+	if err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	
+	_, err = service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Needs a deadline", Completed: true})
+	if err == nil || !strings.Contains(err.Error(), "due date is required") {
+		t.Errorf("Expected due date validation error, got %v", err)
+	}
+	
+	due := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	updated, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Needs a deadline", Completed: true, DueDate: &due})
+	if err != nil {
+		t.Fatalf("Expected completion with a due date to succeed, got %v", err)
+	}
+	if !updated.Completed {
+		t.Error("Expected todo to be completed")
+	}
+}
+
+// TestUpdateTodo_NoCompletionRequirementsByDefault tests that the default config allows bare completion
+func TestUpdateTodo_NoCompletionRequirementsByDefault(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	created, _ := service.CreateTodo(CreateTodoInput{Title: "Bare"})
+	if _, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Bare", Completed: true}); err != nil {
+		t.Errorf("Expected completion to succeed by default, got %v", err)
+	}
+}
+
+// TestValidateCompletionRequiredFields tests rejection of unsupported field names
+func TestValidateCompletionRequiredFields(t *testing.T) {
+	if err := ValidateCompletionRequiredFields([]string{CompletionFieldDescription, CompletionFieldDueDate}); err != nil {
+		t.Errorf("Expected description and due_date to be supported, got %v", err)
+	}
+	if err := ValidateCompletionRequiredFields([]string{"owner"}); err == nil {
+		t.Error("Expected unsupported field to be rejected")
+	}
+}
+
+// TestPatchTodo tests applying JSON Patch operations through the service
+func TestPatchTodo(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	created, _ := service.CreateTodo(CreateTodoInput{Title: "Original", Description: "Description"})
+	
+	patched, err := service.PatchTodo(created.ID, []models.PatchOperation{
+		{Op: "remove", Path: "/description"},
+		{Op: "add", Path: "/completed", Value: []byte("true")},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if patched.Description != "" || !patched.Completed || patched.Title != "Original" {
+		t.Errorf("Unexpected patched todo: %+v", patched)
+	}
+	
+	_, err = service.PatchTodo(created.ID, []models.PatchOperation{{Op: "remove", Path: "/title"}})
+	if err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected removing the title to fail validation, got %v", err)
+	}
+	
+	_, err = service.PatchTodo(created.ID, []models.PatchOperation{{Op: "move", Path: "/title"}})
+	if !errors.Is(err, models.ErrInvalidPatch) {
+		t.Errorf("Expected unsupported op to be an invalid patch, got %v", err)
+	}
+}
+
+// TestBulkUpdateTodos tests a bulk update mixing successful items and a version conflict
+func TestBulkUpdateTodos(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger})
+	
+	first, _ := service.CreateTodo(CreateTodoInput{Title: "First"})
+	second, _ := service.CreateTodo(CreateTodoInput{Title: "Second"})
+	third, _ := service.CreateTodo(CreateTodoInput{Title: "Third"})
+	
+	// Someone else edits the third todo, moving it to version 2
+	service.UpdateTodo(third.ID, UpdateTodoInput{Title: "Third (edited elsewhere)"})
+	logger.entries = nil
+	
+	results, err := service.BulkUpdateTodos([]models.BulkUpdateItem{
+		{ID: first.ID, Title: "  First synced  ", Completed: true, Version: 1},
+		{ID: second.ID, Title: "Second synced"},
+		{ID: third.ID, Title: "Third synced", Version: 1},
+		{ID: 99, Title: "Missing"},
+		{ID: second.ID, Title: ""},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	expected := []string{
+		models.BulkStatusUpdated,
+		models.BulkStatusUpdated,
+		models.BulkStatusConflict,
+		models.BulkStatusNotFound,
+		models.BulkStatusInvalid,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, status := range expected {
+		if results[i].Status != status {
+			t.Errorf("Item %d: expected status %s, got %s", i, status, results[i].Status)
+		}
+	}
+	
+	if results[0].Version != 2 || results[0].Todo.Title != "First synced" {
+		t.Errorf("Expected first todo at version 2 with trimmed title, got %+v", results[0])
+	}
+	if results[2].Version != 2 {
+		t.Errorf("Expected conflict to report current version 2, got %d", results[2].Version)
+	}
+	
+	stored, _ := service.GetTodoByID(third.ID)
+	if stored.Title != "Third (edited elsewhere)" {
+		t.Errorf("Expected conflicting item to be left untouched, got %q", stored.Title)
+	}
+	
+	if len(logger.entries) != 2 {
+		t.Errorf("Expected 2 audit entries for the applied updates, got %d", len(logger.entries))
+	}
+}
+
+// TestSnoozeTodo tests deferring a todo's due date with and without an existing due date
+func TestSnoozeTodo(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	now := time.Date(2024, 2, 20, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{Now: func() time.Time { return now }})
+	
+	dueDate := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	withDue, _ := service.CreateTodo(CreateTodoInput{Title: "With due date", DueDate: &dueDate})
+	withoutDue, _ := service.CreateTodo(CreateTodoInput{Title: "Without due date"})
+	
+	snoozed, err := service.SnoozeTodo(withDue.ID, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if snoozed.DueDate == nil || !snoozed.DueDate.Equal(dueDate.Add(24*time.Hour)) {
+		t.Errorf("Expected due date %v, got %v", dueDate.Add(24*time.Hour), snoozed.DueDate)
+	}
+	if snoozed.Title != "With due date" || snoozed.Version != 2 {
+		t.Errorf("Expected other fields to be preserved and version bumped, got %+v", snoozed)
+	}
+	
+	snoozed, err = service.SnoozeTodo(withoutDue.ID, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if snoozed.DueDate == nil || !snoozed.DueDate.Equal(now.Add(7*24*time.Hour)) {
+		t.Errorf("Expected due date one week from now, got %v", snoozed.DueDate)
+	}
+	
+	if _, err := service.SnoozeTodo(99, time.Hour); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if _, err := service.SnoozeTodo(withDue.ID, 0); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for a zero duration, got %v", err)
+	}
+}
+
+// TestUpdateTagsBatch tests adding one tag and removing another across several todos
+func TestUpdateTagsBatch(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger})
+	
+	first, _ := service.CreateTodo(CreateTodoInput{Title: "First"})
+	second, _ := service.CreateTodo(CreateTodoInput{Title: "Second"})
+	mockRepo.todos[first.ID].Tags = []string{"old", "work"}
+	mockRepo.todos[second.ID].Tags = []string{"old"}
+	logger.entries = nil
+	
+	result, err := service.UpdateTagsBatch([]int{first.ID, second.ID, 99, first.ID}, []string{" Urgent ", "urgent"}, []string{"OLD"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if len(result.Updated) != 2 {
+		t.Fatalf("Expected 2 updated todos, got %d", len(result.Updated))
+	}
+	if len(result.MissingIDs) != 1 || result.MissingIDs[0] != 99 {
+		t.Errorf("Expected missing IDs [99], got %v", result.MissingIDs)
+	}
+	
+	expected := map[int][]string{
+		first.ID:  {"work", "urgent"},
+		second.ID: {"urgent"},
+	}
+	for id, tags := range expected {
+		stored, _ := service.GetTodoByID(id)
+		if !slices.Equal(stored.Tags, tags) {
+			t.Errorf("Todo %d: expected tags %v, got %v", id, tags, stored.Tags)
+		}
+	}
+	
+	if len(logger.entries) != 2 {
+		t.Errorf("Expected 2 audit entries, got %d", len(logger.entries))
+	}
+	
+	if _, err := service.UpdateTagsBatch(nil, []string{"x"}, nil); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for empty IDs, got %v", err)
+	}
+	if _, err := service.UpdateTagsBatch([]int{first.ID}, []string{"  "}, nil); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for no tag changes, got %v", err)
+	}
+}
+
+// TestGetTodoHistory tests that successive edits produce ordered history entries
+func TestGetTodoHistory(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger})
+	
+	created, _ := service.CreateTodo(CreateTodoInput{Title: "v1"})
+	service.CreateTodo(CreateTodoInput{Title: "Unrelated"})
+	service.UpdateTodo(created.ID, UpdateTodoInput{Title: "v2"})
+	service.UpdateTodo(created.ID, UpdateTodoInput{Title: "v3", Completed: true})
+	
+	history, err := service.GetTodoHistory(created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	expected := []struct {
+		action string
+		title  string
+	}{
+		{audit.ActionCreate, "v1"},
+		{audit.ActionUpdate, "v2"},
+		{audit.ActionUpdate, "v3"},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d history entries, got %d", len(expected), len(history))
+	}
+	for i, want := range expected {
+		if history[i].Action != want.action || history[i].After.Title != want.title {
+			t.Errorf("Entry %d: expected %s to %q, got %s to %q", i, want.action, want.title, history[i].Action, history[i].After.Title)
+		}
+	}
+	
+	history, err = service.GetTodoHistory(99)
+	if err != nil || len(history) != 0 {
+		t.Errorf("Expected empty history for a todo without changes, got %v (err %v)", history, err)
+	}
+	
+	if _, err := NewTodoService(mockRepo).GetTodoHistory(created.ID); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("Expected ErrHistoryUnavailable without an audit logger, got %v", err)
+	}
+}
+
+// TestPinTodo tests that pinned todos are listed ahead of unpinned ones
+func TestPinTodo(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	first, _ := service.CreateTodo(CreateTodoInput{Title: "First"})
+	second, _ := service.CreateTodo(CreateTodoInput{Title: "Second"})
+	third, _ := service.CreateTodo(CreateTodoInput{Title: "Third"})
+	
+	pinned, err := service.PinTodo(third.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !pinned.Pinned || pinned.Version != 2 {
+		t.Errorf("Expected pinned todo at version 2, got %+v", pinned)
+	}
+	service.PinTodo(second.ID)
+	
+	todos, err := service.ListTodos(models.ListFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	order := make([]int, len(todos))
+	for i, todo := range todos {
+		order[i] = todo.ID
+	}
+	if expected := []int{second.ID, third.ID, first.ID}; !slices.Equal(order, expected) {
+		t.Errorf("Expected order %v, got %v", expected, order)
+	}
+	
+	unpinned, err := service.UnpinTodo(second.ID)
+	if err != nil || unpinned.Pinned {
+		t.Errorf("Expected todo to be unpinned, got %+v (err %v)", unpinned, err)
+	}
+	todos, _ = service.ListTodos(models.ListFilter{})
+	if todos[0].ID != third.ID {
+		t.Errorf("Expected the remaining pinned todo first, got %d", todos[0].ID)
+	}
+	
+	if _, err := service.PinTodo(99); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+// TestValidation_CustomDescriptionLimit tests that the error message reflects a configured limit
+func TestValidation_CustomDescriptionLimit(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{MaxDescriptionLength: 500})
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Fits", Description: strings.Repeat("a", 500)}); err != nil {
+		t.Errorf("Expected a description at the limit to be accepted, got %v", err)
+	}
+	
+	_, err := service.CreateTodo(CreateTodoInput{Title: "Too long", Description: strings.Repeat("a", 501)})
+	if err == nil || !strings.Contains(err.Error(), "description must be 500 characters or less") {
+		t.Errorf("Expected the error to name the 500 character limit, got %v", err)
+	}
+	
+	// Without configuration the default limit applies
+	_, err = NewTodoService(mockRepo).CreateTodo(CreateTodoInput{Title: "Too long", Description: strings.Repeat("a", 1001)})
+	if err == nil || !strings.Contains(err.Error(), "description must be 1000 characters or less") {
+		t.Errorf("Expected the error to name the default limit, got %v", err)
+	}
+}
+
+// TestValidation_DescriptionLineLimit tests rejecting descriptions with too many lines when configured
+func TestValidation_DescriptionLineLimit(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{MaxDescriptionLines: 3})
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Fits", Description: "one\ntwo\nthree"}); err != nil {
+		t.Errorf("Expected a description at the line limit to be accepted, got %v", err)
+	}
+	
+	_, err := service.CreateTodo(CreateTodoInput{Title: "Too many", Description: "one\ntwo\nthree\nfour"})
+	if err == nil || !strings.Contains(err.Error(), "description must be 3 lines or less") {
+		t.Errorf("Expected the error to name the 3 line limit, got %v", err)
+	}
+	
+	// Without configuration the line count is unlimited
+	if _, err := NewTodoService(mockRepo).CreateTodo(CreateTodoInput{Title: "Long", Description: strings.Repeat("line\n", 100)}); err != nil {
+		t.Errorf("Expected no line limit by default, got %v", err)
+	}
+}
+
+// TestDescriptionTrimming tests trimming descriptions by default and preserving them when configured
+func TestDescriptionTrimming(t *testing.T) {
+	const indented = "    func main() {}\n"
+	
+	tests := []struct {
+		name     string
+		preserve bool
+		expected string
+	}{
+		{"trimmed by default", false, "func main() {}"},
+		{"preserved when configured", true, indented},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTodoServiceWithConfig(NewMockTodoRepository(), ServiceConfig{PreserveDescriptionWhitespace: tt.preserve})
+			
+			created, err := service.CreateTodo(CreateTodoInput{Title: "  Snippet  ", Description: indented})
+			if err != nil {
+				t.Fatalf("Failed to create todo: %v", err)
+			}
+			if created.Title != "Snippet" {
+				t.Errorf("Expected title to always be trimmed, got %q", created.Title)
+			}
+			if created.Description != tt.expected {
+				t.Errorf("Expected description %q on create, got %q", tt.expected, created.Description)
+			}
+			
+			updated, err := service.UpdateTodo(created.ID, UpdateTodoInput{Title: "Snippet", Description: indented})
+			if err != nil {
+				t.Fatalf("Failed to update todo: %v", err)
+			}
+			if updated.Description != tt.expected {
+				t.Errorf("Expected description %q on update, got %q", tt.expected, updated.Description)
+			}
+		})
+	}
+}
+
+// TestBurndown tests per-bucket created/completed counts across several days
+func TestBurndown(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	day := func(d, h int) time.Time {
+		return time.Date(2024, time.March, d, h, 0, 0, 0, time.UTC)
+	}
+	completed := func(tm time.Time) *time.Time { return &tm }
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "A", CreatedAt: day(4, 9), Completed: true, CompletedAt: completed(day(5, 10))}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "B", CreatedAt: day(4, 18)}
+	mockRepo.todos[3] = &models.Todo{ID: 3, Title: "C", CreatedAt: day(5, 8), Completed: true, CompletedAt: completed(day(7, 12))}
+	mockRepo.todos[4] = &models.Todo{ID: 4, Title: "D", CreatedAt: day(1, 8), Completed: true, CompletedAt: completed(day(6, 23))}
+	mockRepo.todos[5] = &models.Todo{ID: 5, Title: "E", CreatedAt: day(12, 8)}
+	
+	buckets, err := service.Burndown(day(4, 0), day(7, 23), models.BucketDay)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []models.BurndownBucket{
+		{Start: day(4, 0), Created: 2, Completed: 0},
+		{Start: day(5, 0), Created: 1, Completed: 1},
+		{Start: day(6, 0), Created: 0, Completed: 1},
+		{Start: day(7, 0), Created: 0, Completed: 1},
+	}
+	if !slices.Equal(buckets, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, buckets)
+	}
+	
+	// 2024-03-04 is a Monday, so the week bucket starts there
+	weekly, err := service.Burndown(day(1, 0), day(12, 23), models.BucketWeek)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedWeekly := []models.BurndownBucket{
+		{Start: time.Date(2024, time.February, 26, 0, 0, 0, 0, time.UTC), Created: 1, Completed: 0},
+		{Start: day(4, 0), Created: 3, Completed: 3},
+		{Start: day(11, 0), Created: 1, Completed: 0},
+	}
+	if !slices.Equal(weekly, expectedWeekly) {
+		t.Errorf("Expected %+v, got %+v", expectedWeekly, weekly)
+	}
+	
+	if _, err := service.Burndown(day(4, 0), day(7, 0), "month"); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for bucket, got %v", err)
+	}
+	if _, err := service.Burndown(day(7, 0), day(4, 0), models.BucketDay); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for reversed range, got %v", err)
+	}
+}
+
+// TestCreateTodo_Source tests recording and filtering by creation source
+func TestCreateTodo_Source(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	manual, _ := service.CreateTodo(CreateTodoInput{Title: "Manual"})
+	imported, _ := service.CreateTodo(CreateTodoInput{Title: "Imported", Source: models.SourceImport})
+	cloned, _ := service.CreateTodo(CreateTodoInput{Title: "Cloned", Source: models.SourceClone})
+	
+	for todo, expected := range map[*models.Todo]string{manual: models.SourceAPI, imported: models.SourceImport, cloned: models.SourceClone} {
+		if todo.Source != expected {
+			t.Errorf("Expected %q to have source %q, got %q", todo.Title, expected, todo.Source)
+		}
+	}
+	
+	// Todos stored before sources were recorded count as created through the API
+	mockRepo.todos[10] = &models.Todo{ID: 10, Title: "Legacy"}
+	
+	todos, err := service.ListTodos(models.ListFilter{Source: models.SourceAPI})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != manual.ID || todos[1].ID != 10 {
+		t.Errorf("Expected the manual and legacy todos, got %+v", todos)
+	}
+	todos, _ = service.ListTodos(models.ListFilter{Source: models.SourceImport})
+	if len(todos) != 1 || todos[0].ID != imported.ID {
+		t.Errorf("Expected only the imported todo, got %+v", todos)
+	}
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Bad", Source: "fax"}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for unknown source, got %v", err)
+	}
+}
+
+// TestNextActionable tests ranking open todos by pinning, then due date, then age
+func TestNextActionable(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	if _, err := service.NextActionable(); !errors.Is(err, ErrNoActionableTodo) {
+		t.Errorf("Expected ErrNoActionableTodo with no todos, got %v", err)
+	}
+	
+	due := func(days int) *time.Time {
+		d := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+		return &d
+	}
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "Oldest, no due date"}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "Due later", DueDate: due(5)}
+	mockRepo.todos[3] = &models.Todo{ID: 3, Title: "Due soon but done", DueDate: due(1), Completed: true}
+	mockRepo.todos[4] = &models.Todo{ID: 4, Title: "Due soonest", DueDate: due(2)}
+	
+	tests := []struct {
+		name     string
+		setup    func()
+		expected int
+	}{
+		{"earliest due date among open todos", func() {}, 4},
+		{"due dates tie on ID", func() { mockRepo.todos[2].DueDate = due(2) }, 2},
+		{"pinned without due date beats due dates", func() { mockRepo.todos[1].Pinned = true }, 1},
+		{"earliest due among pinned", func() { mockRepo.todos[4].Pinned = true }, 4},
+		{"completed todos are skipped", func() { mockRepo.todos[4].Completed = true; mockRepo.todos[2].Pinned = true }, 2},
+	}
+	
+	for _, tt := range tests {
+		tt.setup()
+		next, err := service.NextActionable()
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		if next.ID != tt.expected {
+			t.Errorf("%s: expected todo %d, got %d", tt.name, tt.expected, next.ID)
+		}
+	}
+}
+
+// TestDeleteExpired tests that a sweep removes only todos whose expiry has passed
+func TestDeleteExpired(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	logger := &recordingAuditLogger{}
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{AuditLogger: logger, Now: func() time.Time { return now }})
+	
+	inAnHour := now.Add(time.Hour)
+	ephemeral, err := service.CreateTodo(CreateTodoInput{Title: "Ephemeral", ExpiresAt: &inAnHour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	kept, _ := service.CreateTodo(CreateTodoInput{Title: "Kept"})
+	
+	if deleted, err := service.DeleteExpired(); err != nil || deleted != 0 {
+		t.Errorf("Expected nothing to expire yet, got %d (err %v)", deleted, err)
+	}
+	
+	now = now.Add(2 * time.Hour)
+	if deleted, err := service.DeleteExpired(); err != nil || deleted != 1 {
+		t.Errorf("Expected one expired todo to be deleted, got %d (err %v)", deleted, err)
+	}
+	if _, err := service.GetTodoByID(ephemeral.ID); err == nil {
+		t.Error("Expected the expired todo to be gone")
+	}
+	if del := logger.entries[len(logger.entries)-1]; del.Action != audit.ActionDelete || del.TodoID != ephemeral.ID || del.Before == nil {
+		t.Errorf("Expected a delete audit entry for the expired todo, got %+v", del)
+	}
+	if _, err := service.GetTodoByID(kept.ID); err != nil {
+		t.Errorf("Expected the todo without expiry to remain, got %v", err)
+	}
+	
+	past := now.Add(-time.Minute)
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Stale", ExpiresAt: &past}); err == nil || !strings.Contains(err.Error(), "expires_at must be in the future") {
+		t.Errorf("Expected validation error for a past expiry, got %v", err)
+	}
+	if _, err := service.UpdateTodo(kept.ID, UpdateTodoInput{Title: "Kept", ExpiresAt: &past}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for a past expiry on update, got %v", err)
+	}
+}
+
+func TestStaleTodos(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{Now: func() time.Time { return now }})
+	
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "Recently touched", UpdatedAt: now.AddDate(0, 0, -29)}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "Forgotten", UpdatedAt: now.AddDate(0, 0, -45)}
+	mockRepo.todos[3] = &models.Todo{ID: 3, Title: "Long forgotten", UpdatedAt: now.AddDate(0, 0, -90)}
+	mockRepo.todos[4] = &models.Todo{ID: 4, Title: "Old but completed", Completed: true, UpdatedAt: now.AddDate(0, 0, -90)}
+	
+	stale, err := service.StaleTodos(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stale) != 2 || stale[0].ID != 3 || stale[1].ID != 2 {
+		t.Errorf("Expected the two old open todos, least recently updated first, got %v", stale)
+	}
+	
+	if _, err := service.StaleTodos(0); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for a zero age, got %v", err)
+	}
+}
+
+func TestForbiddenWords(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{ForbiddenWords: []string{"spam", "Scam"}})
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Send SPAM to everyone"}); err == nil || !strings.Contains(err.Error(), `validation failed: title contains the forbidden word "SPAM"`) {
+		t.Errorf("Expected a title with a banned word to be rejected, got %v", err)
+	}
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Report", Description: "Looks like a scam."}); err == nil || !strings.Contains(err.Error(), "description contains the forbidden word") {
+		t.Errorf("Expected a description with a banned word to be rejected, got %v", err)
+	}
+	
+	// Banned words inside longer words are not whole-word matches
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Filter spammy mail", Description: "Escaped the scampi"})
+	if err != nil {
+		t.Fatalf("Expected a benign title to pass, got %v", err)
+	}
+	if _, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: "spam"}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected updates to be checked too, got %v", err)
+	}
+	
+	if err := ValidateForbiddenWords([]string{"two words"}); err == nil {
+		t.Error("Expected multi-word entries to be rejected")
+	}
+}
+
+func TestTitleControlCharacters(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{RejectTitleControlChars: true})
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Line one\nline two"}); err == nil || !strings.Contains(err.Error(), "validation failed: title must not contain control characters (found U+000A)") {
+		t.Errorf("Expected a title with an embedded newline to be rejected, got %v", err)
+	}
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Tab\there"}); err == nil || !strings.Contains(err.Error(), "control characters") {
+		t.Errorf("Expected a title with a tab to be rejected, got %v", err)
+	}
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Multi-line notes\n", Description: "Line one\nline two\tindented"})
+	if err != nil {
+		t.Fatalf("Expected control characters in the description and trailing whitespace in the title to be allowed, got %v", err)
+	}
+	if _, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: "Bell\a"}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected updates to be validated too, got %v", err)
+	}
+	
+	lenient := NewTodoService(NewMockTodoRepository())
+	if _, err := lenient.CreateTodo(CreateTodoInput{Title: "Line one\nline two"}); err != nil {
+		t.Errorf("Expected control characters to be allowed by default, got %v", err)
+	}
+}
+
+func TestTitleScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		title   string
+		wantErr string
+	}{
+		{"latin accepts latin", models.TitleScriptLatin, "Café meeting: 3 items, 50% done!", ""},
+		{"latin accepts combining marks", models.TitleScriptLatin, "Cafe\u0301 order", ""},
+		{"latin rejects cyrillic", models.TitleScriptLatin, "Купить молоко", "title must use only Latin script characters (found U+041A 'К')"},
+		{"latin rejects mixed scripts", models.TitleScriptLatin, "Buy молоко", "Latin script"},
+		{"ascii rejects accents", models.TitleScriptASCII, "Café", "title must use only ASCII characters (found U+00E9 'é')"},
+		{"ascii accepts ascii", models.TitleScriptASCII, "Plain title #1", ""},
+		{"any accepts cyrillic", models.TitleScriptAny, "Купить молоко", ""},
+		{"unset accepts cyrillic", "", "Купить молоко", ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTodoServiceWithConfig(NewMockTodoRepository(), ServiceConfig{TitleScript: tt.script})
+			
+			_, err := service.CreateTodo(CreateTodoInput{Title: tt.title})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected %q to be accepted, got %v", tt.title, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "validation failed") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected a validation error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateTodo_Defaults(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	defaults, err := ParseCreateDefaults("description=Needs triage;tags=Inbox,triage;pinned=true;due_in=48h")
+	if err != nil {
+		t.Fatalf("Failed to parse defaults: %v", err)
+	}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		Now:            func() time.Time { return now },
+		CreateDefaults: defaults,
+	})
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Unset fields"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if todo.Description != "Needs triage" || !slices.Equal(todo.Tags, []string{"inbox", "triage"}) || !todo.Pinned {
+		t.Errorf("Expected the configured defaults, got %+v", todo)
+	}
+	if todo.DueDate == nil || !todo.DueDate.Equal(now.Add(48*time.Hour)) {
+		t.Errorf("Expected due date %v, got %v", now.Add(48*time.Hour), todo.DueDate)
+	}
+	
+	due := now.Add(time.Hour)
+	explicit, err := service.CreateTodo(CreateTodoInput{Title: "Explicit", Description: "Mine", DueDate: &due})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explicit.Description != "Mine" || !explicit.DueDate.Equal(due) {
+		t.Errorf("Expected explicit values to win over defaults, got %+v", explicit)
+	}
+	
+	imported, err := service.CreateTodo(CreateTodoInput{Title: "Imported", Source: models.SourceImport})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if imported.Description != "" || len(imported.Tags) != 0 || imported.Pinned || imported.DueDate != nil {
+		t.Errorf("Expected imported todos to skip defaults, got %+v", imported)
+	}
+}
+
+func TestParseCreateDefaults_Invalid(t *testing.T) {
+	for _, spec := range []string{"priority=medium", "tags", "pinned=maybe", "due_in=-1h", "due_in=soon"} {
+		if _, err := ParseCreateDefaults(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestReopenCompletedBefore(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	early := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "Done early", Completed: true, CompletedAt: &early, Version: 2}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "Done late", Completed: true, CompletedAt: &late, Version: 2}
+	mockRepo.todos[3] = &models.Todo{ID: 3, Title: "Still open", Version: 1}
+	mockRepo.todos[4] = &models.Todo{ID: 4, Title: "Also done early", Completed: true, CompletedAt: &early, Version: 3}
+	
+	reopened, err := service.ReopenCompletedBefore(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reopened != 2 {
+		t.Errorf("Expected 2 todos reopened, got %d", reopened)
+	}
+	for id, wantCompleted := range map[int]bool{1: false, 2: true, 3: false, 4: false} {
+		todo, _ := service.GetTodoByID(id)
+		if todo.Completed != wantCompleted {
+			t.Errorf("Expected todo %d completed=%v, got %v", id, wantCompleted, todo.Completed)
+		}
+		if !todo.Completed && todo.CompletedAt != nil {
+			t.Errorf("Expected todo %d to have no completion time, got %v", id, todo.CompletedAt)
+		}
+	}
+	
+	if reopened, err := service.ReopenCompletedBefore(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)); err != nil || reopened != 0 {
+		t.Errorf("Expected nothing left to reopen, got %d (err %v)", reopened, err)
+	}
+}
+
+func TestPurgeBefore(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	old := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "Old", CreatedAt: old}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "Recent", CreatedAt: old.AddDate(1, 0, 0)}
+	cutoff := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	
+	if _, err := service.PurgeBefore(cutoff, "updated_at"); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for an unsupported field, got %v", err)
+	}
+	
+	purged, err := service.PurgeBefore(cutoff, models.PurgeFieldCreated)
+	if err != nil || purged != 1 {
+		t.Fatalf("Expected one todo purged, got %d (err %v)", purged, err)
+	}
+	if _, err := service.GetTodoByID(1); err == nil {
+		t.Error("Expected the old todo to be gone")
+	}
+	if _, err := service.GetTodoByID(2); err != nil {
+		t.Errorf("Expected the recent todo to remain, got %v", err)
+	}
+}
+
+func TestTodoService_Views(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	view, err := service.CreateView(models.View{Name: "search", Query: "  report  "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if view.Query != "report" {
+		t.Errorf("Expected query to be trimmed, got %q", view.Query)
+	}
+	
+	if _, err := service.CreateView(models.View{Name: ""}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected validation error for an empty name, got %v", err)
+	}
+	
+	if err := service.DeleteView("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := service.DeleteView("search"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := service.GetView("search"); err == nil {
+		t.Error("Expected the deleted view to be gone")
+	}
+}
+
+func TestTodoService_RelatedTodos(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	target := createTestTodo(1, "Target", "", false)
+	target.Tags = []string{"work", "urgent"}
+	mockRepo.Create(target)
+	other := createTestTodo(2, "Shares one", "", false)
+	other.Tags = []string{"urgent"}
+	mockRepo.Create(other)
+	best := createTestTodo(3, "Shares two", "", false)
+	best.Tags = []string{"urgent", "work"}
+	mockRepo.Create(best)
+	done := createTestTodo(4, "Completed", "", true)
+	done.Tags = []string{"urgent", "work"}
+	mockRepo.Create(done)
+	untagged := createTestTodo(5, "Untagged", "", false)
+	mockRepo.Create(untagged)
+	
+	related, err := service.RelatedTodos(target.ID, 0, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(related) != 2 || related[0].ID != best.ID || related[1].ID != other.ID {
+		t.Errorf("Expected todos ranked by tag overlap without completed ones, got %+v", related)
+	}
+	
+	related, err = service.RelatedTodos(untagged.ID, 0, false)
+	if err != nil || len(related) != 0 {
+		t.Errorf("Expected no related todos for an untagged todo, got %+v (err %v)", related, err)
+	}
+	
+	if _, err := service.RelatedTodos(99, 0, false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestTodoService_CompletionSemantics_PatchVsUpdate(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	todo, _ := service.CreateTodo(CreateTodoInput{Title: "Ship release"})
+	if _, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: "Ship release", Completed: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	// PATCH changes only the fields it names
+	patched, err := service.PatchTodo(todo.ID, []models.PatchOperation{
+		{Op: "replace", Path: "/title", Value: json.RawMessage(`"Ship release 1.2"`)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !patched.Completed {
+		t.Errorf("Expected a title-only patch to keep the todo completed, got %+v", patched)
+	}
+	
+	// PUT is a full replacement, so leaving completed out reopens the todo
+	updated, err := service.UpdateTodo(todo.ID, UpdateTodoInput{Title: "Ship release 1.2.1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Completed {
+		t.Errorf("Expected a full update without completed to reopen the todo, got %+v", updated)
+	}
+}
+
+func TestArchiveCompleted_Immediate(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	mockRepo.archived = []models.Todo{}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{ArchiveCompleted: true})
+	
+	done, _ := service.CreateTodo(CreateTodoInput{Title: "Finish me"})
+	open, _ := service.CreateTodo(CreateTodoInput{Title: "Leave me"})
+	
+	if _, err := service.UpdateTodo(done.ID, UpdateTodoInput{Title: "Finish me", Completed: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	todos, _ := service.GetAllTodos()
+	if len(todos) != 1 || todos[0].ID != open.ID {
+		t.Errorf("Expected the completed todo gone from the active list, got %v", todos)
+	}
+	archived, err := service.ListArchived()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != done.ID || !archived[0].Completed {
+		t.Errorf("Expected the completed todo in the archive, got %v", archived)
+	}
+}
+
+// TestImportTodos tests that imported todos are validated in full and saved together, up
+// to the first rejected one
+func TestImportTodos(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	mockRepo.archived = []models.Todo{}
+	logger := &recordingAuditLogger{}
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		AuditLogger:              logger,
+		ArchiveCompleted:         true,
+		CompletionRequiredFields: []string{CompletionFieldDescription},
+	})
+	
+	imported, err := service.ImportTodos([]models.Todo{
+		{Title: "Open", Tags: []string{"Errands"}, Pinned: true},
+		{Title: "Done", Description: "Shipped", Completed: true, Pinned: true},
+		// Completion requirements are checked before anything is saved
+		{Title: "No description", Completed: true},
+		{Title: "After the rejected one"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "description is required") {
+		t.Errorf("Expected the completion requirements to reject the third todo, got %v", err)
+	}
+	if imported != 2 {
+		t.Fatalf("Expected the 2 todos before the rejected one imported, got %d", imported)
+	}
+	
+	// The completed, pinned todo is archived right after the save
+	if len(mockRepo.todos) != 1 || len(mockRepo.archived) != 1 {
+		t.Fatalf("Expected one stored and one archived todo, got %d and %v", len(mockRepo.todos), mockRepo.archived)
+	}
+	for _, open := range mockRepo.todos {
+		if open.Title != "Open" || open.Source != models.SourceImport || !open.Pinned || len(open.Tags) != 1 || open.Tags[0] != "errands" || open.Version != 1 {
+			t.Errorf("Expected source, pin and normalized tags carried over in one save, got %+v", open)
+		}
+	}
+	if done := mockRepo.archived[0]; done.Title != "Done" || !done.Completed || done.CompletedAt == nil {
+		t.Errorf("Expected the todo imported as completed, got %+v", done)
+	}
+	
+	creates := 0
+	for _, entry := range logger.entries {
+		if entry.Action == audit.ActionCreate {
+			creates++
+		}
+	}
+	if creates != 2 || len(logger.entries) != 3 {
+		t.Errorf("Expected one create per imported todo plus the archive, got %+v", logger.entries)
+	}
+}
+
+func TestArchiveCompleted_Delayed(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	mockRepo.archived = []models.Todo{}
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		ArchiveCompleted: true,
+		ArchiveAfter:     24 * time.Hour,
+		Now:              func() time.Time { return now },
+	})
+	
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+	mockRepo.todos[1] = &models.Todo{ID: 1, Title: "Done long ago", Completed: true, CompletedAt: &old}
+	mockRepo.todos[2] = &models.Todo{ID: 2, Title: "Just done", Completed: true, CompletedAt: &recent}
+	
+	// Completing a todo doesn't archive anything while a delay is configured
+	mockRepo.todos[3] = &models.Todo{ID: 3, Title: "Done now"}
+	if _, err := service.UpdateTodo(3, UpdateTodoInput{Title: "Done now", Completed: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mockRepo.archived) != 0 {
+		t.Fatalf("Expected nothing archived on completion, got %v", mockRepo.archived)
+	}
+	
+	archived, err := service.ArchiveCompleted()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if archived != 1 || len(mockRepo.archived) != 1 || mockRepo.archived[0].ID != 1 {
+		t.Errorf("Expected only the todo completed before the delay archived, got %d: %v", archived, mockRepo.archived)
+	}
+}
+
+func TestArchiveCompleted_Disabled(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoService(mockRepo)
+	
+	done, _ := service.CreateTodo(CreateTodoInput{Title: "Finish me"})
+	if _, err := service.UpdateTodo(done.ID, UpdateTodoInput{Title: "Finish me", Completed: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	
+	if archived, err := service.ArchiveCompleted(); err != nil || archived != 0 {
+		t.Errorf("Expected archiving to do nothing when disabled, got %d (err %v)", archived, err)
+	}
+	if _, err := service.GetTodoByID(done.ID); err != nil {
+		t.Errorf("Expected the completed todo to stay active, got %v", err)
+	}
+	if _, err := service.ListArchived(); !errors.Is(err, ErrArchiveUnavailable) {
+		t.Errorf("Expected ErrArchiveUnavailable, got %v", err)
+	}
+}
+
+func TestNormalizeTodos(t *testing.T) {
+	mockRepo := NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, ServiceConfig{
+		CreateDefaults: CreateDefaults{Tags: []string{"inbox"}},
+	})
+	
+	results := service.NormalizeTodos([]CreateTodoInput{
+		{Title: "  Trim me  ", Tags: []string{"A", "a ", "b"}},
+		{Title: "Defaults"},
+		{Title: ""},
+	})
+	
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Todo.Title != "Trim me" || !slices.Equal(results[0].Todo.Tags, []string{"a", "b"}) {
+		t.Errorf("Expected a trimmed title and normalized tags, got %+v (err %v)", results[0].Todo, results[0].Err)
+	}
+	if results[1].Err != nil || !slices.Equal(results[1].Todo.Tags, []string{"inbox"}) {
+		t.Errorf("Expected the default tags filled in, got %+v (err %v)", results[1].Todo, results[1].Err)
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "validation failed") {
+		t.Errorf("Expected a validation error for the blank title, got %v", results[2].Err)
+	}
+	
+	if len(mockRepo.todos) != 0 {
+		t.Errorf("Expected nothing saved, got %d todos", len(mockRepo.todos))
+	}
+}
+
+func TestCreateTodo_NormalizesTags(t *testing.T) {
+	service := NewTodoService(NewMockTodoRepository())
+	
+	todo, err := service.CreateTodo(CreateTodoInput{Title: "Tagged", Tags: []string{" Home ", "home", "Errands"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(todo.Tags, []string{"home", "errands"}) {
+		t.Errorf("Expected normalized tags, got %v", todo.Tags)
+	}
+	
+	if _, err := service.CreateTodo(CreateTodoInput{Title: "Tagged", Tags: []string{strings.Repeat("x", models.MaxTagLength+1)}}); err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Errorf("Expected an over-long tag rejected, got %v", err)
+	}
+}
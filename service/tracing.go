@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"go-crud-todo-list/audit"
+	"go-crud-todo-list/models"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"time"
+)
+
+// tracerName identifies the instrumentation that creates the service spans
+const tracerName = "go-crud-todo-list/service"
+
+// TracingService wraps a TodoService and records a span for every call. Calls through the
+// TodoService returned by WithContext are children of the span in that context, so a
+// request's service calls appear under its server span.
+type TracingService struct {
+	TodoService
+	tracer trace.Tracer
+	ctx    context.Context
+}
+
+// NewTracingService creates a TracingService recording spans for service with provider
+func NewTracingService(service TodoService, provider trace.TracerProvider) *TracingService {
+	return &TracingService{
+		TodoService: service,
+		tracer:      provider.Tracer(tracerName),
+		ctx:         context.Background(),
+	}
+}
+
+// WithContext returns a TodoService whose spans are children of the span in ctx
+func (s *TracingService) WithContext(ctx context.Context) TodoService {
+	traced := *s
+	traced.ctx = ctx
+	return &traced
+}
+
+// start begins the span for a call to the named TodoService method
+func (s *TracingService) start(method string) trace.Span {
+	_, span := s.tracer.Start(s.ctx, "TodoService."+method)
+	return span
+}
+
+// end marks span as failed when err is set, then ends it
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// GetAllTodos records a span and delegates
+func (s *TracingService) GetAllTodos() ([]models.Todo, error) {
+	span := s.start("GetAllTodos")
+	result, err := s.TodoService.GetAllTodos()
+	end(span, err)
+	return result, err
+}
+
+// ListTodos records a span and delegates
+func (s *TracingService) ListTodos(filter models.ListFilter) ([]models.Todo, error) {
+	span := s.start("ListTodos")
+	result, err := s.TodoService.ListTodos(filter)
+	end(span, err)
+	return result, err
+}
+
+// CountTodos records a span and delegates
+func (s *TracingService) CountTodos(filter models.ListFilter) (int, error) {
+	span := s.start("CountTodos")
+	result, err := s.TodoService.CountTodos(filter)
+	end(span, err)
+	return result, err
+}
+
+// NextActionable records a span and delegates
+func (s *TracingService) NextActionable() (*models.Todo, error) {
+	span := s.start("NextActionable")
+	result, err := s.TodoService.NextActionable()
+	end(span, err)
+	return result, err
+}
+
+// StaleTodos records a span and delegates
+func (s *TracingService) StaleTodos(olderThan time.Duration) ([]models.Todo, error) {
+	span := s.start("StaleTodos")
+	result, err := s.TodoService.StaleTodos(olderThan)
+	end(span, err)
+	return result, err
+}
+
+// RelatedTodos records a span and delegates
+func (s *TracingService) RelatedTodos(id int, limit int, includeCompleted bool) ([]models.Todo, error) {
+	span := s.start("RelatedTodos")
+	result, err := s.TodoService.RelatedTodos(id, limit, includeCompleted)
+	end(span, err)
+	return result, err
+}
+
+// StreamFiltered records a span and delegates
+func (s *TracingService) StreamFiltered(w io.Writer, filter models.ListFilter) error {
+	span := s.start("StreamFiltered")
+	err := s.TodoService.StreamFiltered(w, filter)
+	end(span, err)
+	return err
+}
+
+// GetTodoByID records a span and delegates
+func (s *TracingService) GetTodoByID(id int) (*models.Todo, error) {
+	span := s.start("GetTodoByID")
+	result, err := s.TodoService.GetTodoByID(id)
+	end(span, err)
+	return result, err
+}
+
+// CreateTodo records a span and delegates
+func (s *TracingService) CreateTodo(input CreateTodoInput) (*models.Todo, error) {
+	span := s.start("CreateTodo")
+	result, err := s.TodoService.CreateTodo(input)
+	end(span, err)
+	return result, err
+}
+
+// NormalizeTodos records a span and delegates
+func (s *TracingService) NormalizeTodos(inputs []CreateTodoInput) []NormalizeResult {
+	span := s.start("NormalizeTodos")
+	result := s.TodoService.NormalizeTodos(inputs)
+	span.End()
+	return result
+}
+
+// ImportTodos records a span and delegates
+func (s *TracingService) ImportTodos(todos []models.Todo) (int, error) {
+	span := s.start("ImportTodos")
+	result, err := s.TodoService.ImportTodos(todos)
+	end(span, err)
+	return result, err
+}
+
+// UpdateTodo records a span and delegates
+func (s *TracingService) UpdateTodo(id int, input UpdateTodoInput) (*models.Todo, error) {
+	span := s.start("UpdateTodo")
+	result, err := s.TodoService.UpdateTodo(id, input)
+	end(span, err)
+	return result, err
+}
+
+// PreviewUpdate records a span and delegates
+func (s *TracingService) PreviewUpdate(id int, input UpdateTodoInput) ([]models.FieldChange, error) {
+	span := s.start("PreviewUpdate")
+	result, err := s.TodoService.PreviewUpdate(id, input)
+	end(span, err)
+	return result, err
+}
+
+// SnoozeTodo records a span and delegates
+func (s *TracingService) SnoozeTodo(id int, d time.Duration) (*models.Todo, error) {
+	span := s.start("SnoozeTodo")
+	result, err := s.TodoService.SnoozeTodo(id, d)
+	end(span, err)
+	return result, err
+}
+
+// PinTodo records a span and delegates
+func (s *TracingService) PinTodo(id int) (*models.Todo, error) {
+	span := s.start("PinTodo")
+	result, err := s.TodoService.PinTodo(id)
+	end(span, err)
+	return result, err
+}
+
+// UnpinTodo records a span and delegates
+func (s *TracingService) UnpinTodo(id int) (*models.Todo, error) {
+	span := s.start("UnpinTodo")
+	result, err := s.TodoService.UnpinTodo(id)
+	end(span, err)
+	return result, err
+}
+
+// AddChecklistItem records a span and delegates
+func (s *TracingService) AddChecklistItem(id int, text string) (*models.Todo, error) {
+	span := s.start("AddChecklistItem")
+	result, err := s.TodoService.AddChecklistItem(id, text)
+	end(span, err)
+	return result, err
+}
+
+// SetChecklistItemDone records a span and delegates
+func (s *TracingService) SetChecklistItemDone(id, index int, done *bool) (*models.Todo, error) {
+	span := s.start("SetChecklistItemDone")
+	result, err := s.TodoService.SetChecklistItemDone(id, index, done)
+	end(span, err)
+	return result, err
+}
+
+// RemoveChecklistItem records a span and delegates
+func (s *TracingService) RemoveChecklistItem(id, index int) (*models.Todo, error) {
+	span := s.start("RemoveChecklistItem")
+	result, err := s.TodoService.RemoveChecklistItem(id, index)
+	end(span, err)
+	return result, err
+}
+
+// PatchTodo records a span and delegates
+func (s *TracingService) PatchTodo(id int, ops []models.PatchOperation) (*models.Todo, error) {
+	span := s.start("PatchTodo")
+	result, err := s.TodoService.PatchTodo(id, ops)
+	end(span, err)
+	return result, err
+}
+
+// BulkUpdateTodos records a span and delegates
+func (s *TracingService) BulkUpdateTodos(items []models.BulkUpdateItem) ([]models.BulkUpdateResult, error) {
+	span := s.start("BulkUpdateTodos")
+	result, err := s.TodoService.BulkUpdateTodos(items)
+	end(span, err)
+	return result, err
+}
+
+// ReopenCompletedBefore records a span and delegates
+func (s *TracingService) ReopenCompletedBefore(before time.Time) (int, error) {
+	span := s.start("ReopenCompletedBefore")
+	result, err := s.TodoService.ReopenCompletedBefore(before)
+	end(span, err)
+	return result, err
+}
+
+// UpdateTagsBatch records a span and delegates
+func (s *TracingService) UpdateTagsBatch(ids []int, add, remove []string) (*models.TagBatchResult, error) {
+	span := s.start("UpdateTagsBatch")
+	result, err := s.TodoService.UpdateTagsBatch(ids, add, remove)
+	end(span, err)
+	return result, err
+}
+
+// DeleteTodo records a span and delegates
+func (s *TracingService) DeleteTodo(id int, force bool) error {
+	span := s.start("DeleteTodo")
+	err := s.TodoService.DeleteTodo(id, force)
+	end(span, err)
+	return err
+}
+
+// DeleteExpired records a span and delegates
+func (s *TracingService) DeleteExpired() (int, error) {
+	span := s.start("DeleteExpired")
+	result, err := s.TodoService.DeleteExpired()
+	end(span, err)
+	return result, err
+}
+
+// PurgeBefore records a span and delegates
+func (s *TracingService) PurgeBefore(before time.Time, field string) (int, error) {
+	span := s.start("PurgeBefore")
+	result, err := s.TodoService.PurgeBefore(before, field)
+	end(span, err)
+	return result, err
+}
+
+// ArchiveCompleted records a span and delegates
+func (s *TracingService) ArchiveCompleted() (int, error) {
+	span := s.start("ArchiveCompleted")
+	result, err := s.TodoService.ArchiveCompleted()
+	end(span, err)
+	return result, err
+}
+
+// ListArchived records a span and delegates
+func (s *TracingService) ListArchived() ([]models.Todo, error) {
+	span := s.start("ListArchived")
+	result, err := s.TodoService.ListArchived()
+	end(span, err)
+	return result, err
+}
+
+// GetTodoHistory records a span and delegates
+func (s *TracingService) GetTodoHistory(id int) ([]audit.Entry, error) {
+	span := s.start("GetTodoHistory")
+	result, err := s.TodoService.GetTodoHistory(id)
+	end(span, err)
+	return result, err
+}
+
+// Burndown records a span and delegates
+func (s *TracingService) Burndown(from, to time.Time, bucket string) ([]models.BurndownBucket, error) {
+	span := s.start("Burndown")
+	result, err := s.TodoService.Burndown(from, to, bucket)
+	end(span, err)
+	return result, err
+}
+
+// TagStats records a span and delegates
+func (s *TracingService) TagStats() ([]models.TagStats, error) {
+	span := s.start("TagStats")
+	result, err := s.TodoService.TagStats()
+	end(span, err)
+	return result, err
+}
+
+// CheckConsistency records a span and delegates
+func (s *TracingService) CheckConsistency() (*models.ConsistencyReport, error) {
+	span := s.start("CheckConsistency")
+	result, err := s.TodoService.CheckConsistency()
+	end(span, err)
+	return result, err
+}
+
+// VerifyStorage records a span and delegates
+func (s *TracingService) VerifyStorage() error {
+	span := s.start("VerifyStorage")
+	err := s.TodoService.VerifyStorage()
+	end(span, err)
+	return err
+}
+
+// DiffSnapshot records a span and delegates
+func (s *TracingService) DiffSnapshot(snapshot []models.Todo) (*models.DiffResult, error) {
+	span := s.start("DiffSnapshot")
+	result, err := s.TodoService.DiffSnapshot(snapshot)
+	end(span, err)
+	return result, err
+}
+
+// ListViews records a span and delegates
+func (s *TracingService) ListViews() ([]models.View, error) {
+	span := s.start("ListViews")
+	result, err := s.TodoService.ListViews()
+	end(span, err)
+	return result, err
+}
+
+// GetView records a span and delegates
+func (s *TracingService) GetView(name string) (*models.View, error) {
+	span := s.start("GetView")
+	result, err := s.TodoService.GetView(name)
+	end(span, err)
+	return result, err
+}
+
+// CreateView records a span and delegates
+func (s *TracingService) CreateView(view models.View) (*models.View, error) {
+	span := s.start("CreateView")
+	result, err := s.TodoService.CreateView(view)
+	end(span, err)
+	return result, err
+}
+
+// DeleteView records a span and delegates
+func (s *TracingService) DeleteView(name string) error {
+	span := s.start("DeleteView")
+	err := s.TodoService.DeleteView(name)
+	end(span, err)
+	return err
+}
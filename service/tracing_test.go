@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestTracingService(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	traced := NewTracingService(NewTodoService(NewMockTodoRepository()), provider)
+	
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	if _, err := traced.WithContext(ctx).CreateTodo(CreateTodoInput{Title: "Traced"}); err != nil {
+		t.Fatalf("Failed to create todo: %v", err)
+	}
+	parent.End()
+	if _, err := traced.GetTodoByID(-1); err == nil {
+		t.Fatal("Expected an error for an invalid ID")
+	}
+	
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	create, get := spans[0], spans[2]
+	if create.Name != "TodoService.CreateTodo" || create.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected the create span as a child of the context's span, got %q with parent %s", create.Name, create.Parent.SpanID())
+	}
+	if get.Name != "TodoService.GetTodoByID" || get.Parent.IsValid() {
+		t.Errorf("Expected a root span for a call without a context, got %q with parent %s", get.Name, get.Parent.SpanID())
+	}
+	if get.Status.Code != codes.Error {
+		t.Errorf("Expected the failed call's span to have error status, got %v", get.Status)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"log"
+)
+
+// Tracing modes accepted by the TRACING environment variable
+const (
+	TracingOff = "off"
+	TracingLog = "log"
+)
+
+// validateTracing reports whether mode is a supported TRACING value
+func validateTracing(mode string) error {
+	switch mode {
+	case "", TracingOff, TracingLog:
+		return nil
+	default:
+		return fmt.Errorf("must be %q or %q", TracingOff, TracingLog)
+	}
+}
+
+// logSpanExporter writes each finished span as a log line
+type logSpanExporter struct{}
+
+// ExportSpans logs the spans in a batch
+func (logSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		sc := span.SpanContext()
+		attrs := make([]string, 0, len(span.Attributes()))
+		for _, kv := range span.Attributes() {
+			attrs = append(attrs, fmt.Sprintf("%s=%s", kv.Key, kv.Value.Emit()))
+		}
+		log.Printf("span %q trace=%s span=%s duration=%s status=%s %v",
+			span.Name(), sc.TraceID(), sc.SpanID(), span.EndTime().Sub(span.StartTime()), span.Status().Code, attrs)
+	}
+	return nil
+}
+
+// Shutdown has nothing to release
+func (logSpanExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// newTracerProvider returns the tracer provider for a TRACING mode, or nil when tracing is off
+func newTracerProvider(mode string) *sdktrace.TracerProvider {
+	if mode != TracingLog {
+		return nil
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(logSpanExporter{}))
+}